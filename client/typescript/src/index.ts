@@ -0,0 +1,41 @@
+import { createClient, type Client, type Interceptor } from "@connectrpc/connect";
+import { createConnectTransport } from "@connectrpc/connect-web";
+import { DraftService } from "./gen/draft/v1/draft_pb.js";
+
+export * from "./gen/draft/v1/draft_pb.js";
+
+export type DraftClient = Client<typeof DraftService>;
+
+export interface DraftClientOptions {
+  // Base URL of the DraftStore HTTP port, e.g. "https://drafts.example.com"
+  baseUrl: string;
+  // Bearer token sent in the Authorization header
+  token?: string;
+  // API key sent in the X-API-Key header
+  apiKey?: string;
+  // Tenant sent in the X-Tenant-ID header
+  tenant?: string;
+}
+
+// createDraftClient returns a DraftService client speaking the Connect
+// protocol, which browsers can use without a proxy.
+export function createDraftClient(options: DraftClientOptions): DraftClient {
+  const headers: Interceptor = (next) => async (req) => {
+    if (options.token) {
+      req.header.set("Authorization", `Bearer ${options.token}`);
+    }
+    if (options.apiKey) {
+      req.header.set("X-API-Key", options.apiKey);
+    }
+    if (options.tenant) {
+      req.header.set("X-Tenant-ID", options.tenant);
+    }
+    return next(req);
+  };
+
+  const transport = createConnectTransport({
+    baseUrl: options.baseUrl,
+    interceptors: [headers],
+  });
+  return createClient(DraftService, transport);
+}
@@ -7,10 +7,13 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/snowmerak/DraftStore/lib/repository"
 	"github.com/snowmerak/DraftStore/lib/service/cleaner"
 	"github.com/snowmerak/DraftStore/lib/storage"
 	"github.com/snowmerak/DraftStore/lib/storage/minio"
+	"github.com/snowmerak/DraftStore/lib/storage/prefix"
 	"github.com/snowmerak/DraftStore/lib/storage/s3"
+	"github.com/snowmerak/DraftStore/lib/tenant"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
 )
 
@@ -25,8 +28,22 @@ type Config struct {
 	MinIOSecretKey string
 	MinIOUseSSL    bool
 	MinIORegion    string
+	// Draft Staging Configuration
+	DraftStaging      string
+	DraftBucketSuffix string
+	DraftPrefix       string
 	// Cleanup Configuration
 	ObjectLifetime time.Duration
+	// Deduplication Configuration
+	DedupEnabled    bool
+	BlobGracePeriod time.Duration
+	// Quota Configuration
+	QuotaEnabled    bool
+	QuotaRepository string
+	// Resumable Upload Configuration
+	TusEnabled bool
+	// Tenant Configuration
+	TenantsFile string
 }
 
 func loadConfig() *Config {
@@ -41,8 +58,22 @@ func loadConfig() *Config {
 		MinIOSecretKey: getEnv("MINIO_SECRET_KEY", "minioadmin"),
 		MinIOUseSSL:    getBoolEnv("MINIO_USE_SSL", false),
 		MinIORegion:    getEnv("MINIO_REGION", "us-east-1"),
+		// Draft Staging Configuration
+		DraftStaging:      getEnv("DRAFT_STAGING", storage.DraftStagingBucket),
+		DraftBucketSuffix: getEnv("DRAFT_BUCKET_SUFFIX", storage.DefaultDraftBucketSuffix),
+		DraftPrefix:       getEnv("DRAFT_PREFIX", storage.DefaultDraftPrefix),
 		// Cleanup Configuration
 		ObjectLifetime: getDurationEnv("OBJECT_LIFETIME", 86400) * time.Second,
+		// Deduplication Configuration
+		DedupEnabled:    getBoolEnv("DEDUP_ENABLED", false),
+		BlobGracePeriod: getDurationEnv("BLOB_GRACE_PERIOD", 3600) * time.Second,
+		// Quota Configuration
+		QuotaEnabled:    getBoolEnv("QUOTA_ENABLED", false),
+		QuotaRepository: getEnv("QUOTA_REPOSITORY", "storage"),
+		// Resumable Upload Configuration
+		TusEnabled: getBoolEnv("TUS_ENABLED", false),
+		// Tenant Configuration
+		TenantsFile: getEnv("TENANTS_FILE", ""),
 	}
 	return cfg
 }
@@ -107,6 +138,26 @@ func createStorageClient(cfg *Config) (storage.Storage, error) {
 	}
 }
 
+// createTenantStorageClient creates a tenant's own storage backend. Settings
+// the tenant leaves empty are taken from the job configuration.
+func createTenantStorageClient(cfg *Config, storageCfg *tenant.StorageConfig) (storage.Storage, error) {
+	tenantCfg := *cfg
+	tenantCfg.StorageType = storageCfg.Type
+	tenantCfg.MinIOUseSSL = storageCfg.UseSSL
+	if storageCfg.Region != "" {
+		tenantCfg.AWSRegion = storageCfg.Region
+		tenantCfg.MinIORegion = storageCfg.Region
+	}
+	if storageCfg.Endpoint != "" {
+		tenantCfg.MinIOEndpoint = storageCfg.Endpoint
+	}
+	if storageCfg.AccessKey != "" {
+		tenantCfg.MinIOAccessKey = storageCfg.AccessKey
+		tenantCfg.MinIOSecretKey = storageCfg.SecretKey
+	}
+	return createStorageClient(&tenantCfg)
+}
+
 func main() {
 	startTime := time.Now()
 	log := logger.GetServiceLogger("cleanup-job")
@@ -116,9 +167,18 @@ func main() {
 
 	// Log startup information
 	logger.LogStartup("cleanup-job", map[string]interface{}{
-		"storage_type":    cfg.StorageType,
-		"bucket_name":     cfg.BucketName,
-		"object_lifetime": cfg.ObjectLifetime.String(),
+		"storage_type":        cfg.StorageType,
+		"bucket_name":         cfg.BucketName,
+		"draft_staging":       cfg.DraftStaging,
+		"draft_bucket_suffix": cfg.DraftBucketSuffix,
+		"draft_prefix":        cfg.DraftPrefix,
+		"object_lifetime":     cfg.ObjectLifetime.String(),
+		"dedup_enabled":       cfg.DedupEnabled,
+		"blob_grace_period":   cfg.BlobGracePeriod.String(),
+		"quota_enabled":       cfg.QuotaEnabled,
+		"quota_repository":    cfg.QuotaRepository,
+		"tus_enabled":         cfg.TusEnabled,
+		"tenants_file":        cfg.TenantsFile,
 	})
 
 	if cfg.StorageType == "s3" {
@@ -144,32 +204,43 @@ func main() {
 		Str("storage_type", cfg.StorageType).
 		Msg("Storage client initialized successfully")
 
-	// Initialize cleaner service
-	log.Info().Msg("Initializing cleaner service")
-	cleanerService, err := cleaner.NewService(cleaner.ServiceOptions{
-		BucketName:     cfg.BucketName,
-		ObjectLifetime: cfg.ObjectLifetime,
-		Storage:        storageClient,
-	})
-	if err != nil {
-		log.Fatal().
-			Err(err).
-			Msg("Failed to create cleaner service")
-	}
-	log.Info().Msg("Cleaner service initialized successfully")
-
 	// Run cleanup once and exit (designed for Kubernetes Job)
-	log.Info().Msg("Starting cleanup operation")
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	if err := cleanerService.CleanupDrafts(ctx); err != nil {
+	if err := runCleanup(ctx, cfg, storageClient, cfg.BucketName); err != nil {
 		log.Fatal().
 			Err(err).
 			Msg("Cleanup operation failed")
 	}
 
+	// Clean every tenant's buckets as well
+	if cfg.TenantsFile != "" {
+		tenants, err := tenant.LoadConfigs(cfg.TenantsFile)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to load tenants")
+		}
+
+		failed := 0
+		for _, tenantCfg := range tenants {
+			if err := cleanupTenant(ctx, cfg, storageClient, tenantCfg); err != nil {
+				log.Error().
+					Err(err).
+					Str("tenant", tenantCfg.ID).
+					Msg("Tenant cleanup failed")
+				failed++
+			}
+		}
+		if failed > 0 {
+			log.Fatal().
+				Int("failed", failed).
+				Int("tenants", len(tenants)).
+				Msg("Cleanup failed for some tenants")
+		}
+	}
+
 	log.Info().
 		Dur("duration", time.Since(startTime)).
 		Msg("Cleanup operation completed successfully")
@@ -177,3 +248,97 @@ func main() {
 	// Log shutdown information
 	logger.LogShutdown("cleanup-job", time.Since(startTime))
 }
+
+// cleanupTenant runs the cleanup on a tenant's buckets, prefix and storage.
+func cleanupTenant(ctx context.Context, cfg *Config, storageClient storage.Storage, tenantCfg tenant.Config) error {
+	bucketName := cfg.BucketName
+	if tenantCfg.BucketName != "" {
+		bucketName = tenantCfg.BucketName
+	}
+	if tenantCfg.Storage != nil {
+		tenantStorage, err := createTenantStorageClient(cfg, tenantCfg.Storage)
+		if err != nil {
+			return fmt.Errorf("failed to create storage client: %w", err)
+		}
+		storageClient = tenantStorage
+	}
+	if tenantCfg.KeyPrefix != "" {
+		prefixStorage, err := prefix.NewStorage(prefix.StorageOptions{
+			Storage: storageClient,
+			Prefix:  tenantCfg.KeyPrefix,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create prefix storage: %w", err)
+		}
+		storageClient = prefixStorage
+	}
+
+	return runCleanup(ctx, cfg, storageClient, bucketName)
+}
+
+// runCleanup removes expired drafts, their quota records, expired resumable
+// uploads and unreferenced blobs of one main bucket.
+func runCleanup(ctx context.Context, cfg *Config, storageClient storage.Storage, bucketName string) error {
+	log := logger.GetServiceLogger("cleanup-job").With().
+		Str("bucket_name", bucketName).
+		Logger()
+
+	// Only the storage repository is shared with the server
+	var usageRepository repository.Repository
+	if (cfg.QuotaEnabled || cfg.TusEnabled) && cfg.QuotaRepository == "storage" {
+		var err error
+		usageRepository, err = repository.NewStorageRepository(repository.StorageRepositoryOptions{
+			Storage:    storageClient,
+			BucketName: bucketName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create usage repository: %w", err)
+		}
+	}
+
+	// Initialize cleaner service
+	log.Info().Msg("Initializing cleaner service")
+	cleanerService, err := cleaner.NewService(cleaner.ServiceOptions{
+		BucketName: bucketName,
+		DraftStaging: storage.DraftStaging{
+			Layout:       cfg.DraftStaging,
+			BucketSuffix: cfg.DraftBucketSuffix,
+			Prefix:       cfg.DraftPrefix,
+		},
+		ObjectLifetime:  cfg.ObjectLifetime,
+		BlobGracePeriod: cfg.BlobGracePeriod,
+		Storage:         storageClient,
+		Repository:      usageRepository,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cleaner service: %w", err)
+	}
+
+	log.Info().Msg("Starting cleanup operation")
+	if err := cleanerService.CleanupDrafts(ctx); err != nil {
+		return err
+	}
+
+	if usageRepository != nil {
+		log.Info().Msg("Starting draft record pruning")
+		if err := cleanerService.PruneDrafts(ctx); err != nil {
+			return err
+		}
+	}
+
+	if usageRepository != nil && cfg.TusEnabled {
+		log.Info().Msg("Starting resumable upload pruning")
+		if err := cleanerService.PruneUploads(ctx); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DedupEnabled {
+		log.Info().Msg("Starting blob garbage collection")
+		if err := cleanerService.CollectBlobs(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
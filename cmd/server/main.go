@@ -8,20 +8,42 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"buf.build/go/protovalidate"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/auth/apikey"
+	"github.com/snowmerak/DraftStore/lib/auth/jwt"
+	"github.com/snowmerak/DraftStore/lib/auth/mtls"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	authzCEL "github.com/snowmerak/DraftStore/lib/authz/cel"
+	connectController "github.com/snowmerak/DraftStore/lib/controller/connect"
 	grpcController "github.com/snowmerak/DraftStore/lib/controller/grpc"
 	webapiController "github.com/snowmerak/DraftStore/lib/controller/webapi"
+	webapiHandler "github.com/snowmerak/DraftStore/lib/controller/webapi/handler"
+	webapiMiddleware "github.com/snowmerak/DraftStore/lib/controller/webapi/middleware"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/openapi"
+	"github.com/snowmerak/DraftStore/lib/plugin/wasm"
+	"github.com/snowmerak/DraftStore/lib/processor"
+	"github.com/snowmerak/DraftStore/lib/processor/image"
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/scanner"
+	"github.com/snowmerak/DraftStore/lib/scanner/clamd"
+	apikeyService "github.com/snowmerak/DraftStore/lib/service/apikey"
 	"github.com/snowmerak/DraftStore/lib/service/draft"
 	"github.com/snowmerak/DraftStore/lib/storage"
 	"github.com/snowmerak/DraftStore/lib/storage/minio"
+	"github.com/snowmerak/DraftStore/lib/storage/prefix"
 	"github.com/snowmerak/DraftStore/lib/storage/s3"
+	"github.com/snowmerak/DraftStore/lib/tenant"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
 )
 
@@ -37,10 +59,83 @@ type Config struct {
 	MinIOUseSSL    bool
 	MinIORegion    string
 	// Server Configuration
-	GRPCPort    string
-	HTTPPort    string
-	UploadTTL   time.Duration
-	DownloadTTL time.Duration
+	GRPCPort          string
+	HTTPPort          string
+	GRPCLegacyResults bool
+	UploadTTL         time.Duration
+	DownloadTTL       time.Duration
+	MaxDownloadTTL    time.Duration
+	DraftDownloadTTL  time.Duration
+	StreamTimeout     time.Duration
+	// Draft Staging Configuration
+	DraftStaging      string
+	DraftBucketSuffix string
+	DraftPrefix       string
+	// Validation Configuration
+	ValidationMaxSize               int64
+	ValidationAllowedTypes          []string
+	ValidationRequireExtensionMatch bool
+	QuarantineBucket                string
+	// Malware Scanning Configuration
+	ClamdAddress  string
+	ClamdTimeout  time.Duration
+	ClamdFailOpen bool
+	// Integrity Configuration
+	RequireChecksum bool
+	// Deduplication Configuration
+	DedupEnabled bool
+	// Quota Configuration
+	QuotaEnabled           bool
+	QuotaRepository        string
+	QuotaMaxDrafts         int64
+	QuotaMaxDraftBytes     int64
+	QuotaMaxConfirmedBytes int64
+	QuotaFile              string
+	ObjectLifetime         time.Duration
+	// Derivative Processing Configuration
+	ImageDerivativesEnabled bool
+	ImageThumbnailWidths    []string
+	ImageFormat             string
+	ImageJPEGQuality        int64
+	ImageMaxPixels          int64
+	ImageMaxBytes           int64
+	ProcessorWorkers        int64
+	// Plugin Configuration
+	PluginDir              string
+	PluginTimeout          time.Duration
+	PluginMemoryLimitPages int64
+	// Authorization Configuration
+	AuthzRulesFile string
+	// Authentication Configuration
+	AuthAllowAnonymous bool
+	JWTIssuer          string
+	JWTAudience        string
+	JWTJWKS            string
+	JWTJWKSRefresh     time.Duration
+	APIKeysFile        string
+	AdminSubjects      []string
+	// TLS Configuration
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSClientAuth     string
+	TLSReloadInterval time.Duration
+	// Tenant Configuration
+	TenantsFile          string
+	TenantAttribute      string
+	TenantRequired       bool
+	TenantHeaderEnabled  bool
+	TenantReloadInterval time.Duration
+	// Download Link Configuration
+	LinkPublicPrefixes []string
+	LinkSecret         string
+	LinkMaxTTL         time.Duration
+	LinkURLTTL         time.Duration
+	LinkCookie         string
+	// Resumable Upload Configuration
+	TusEnabled        bool
+	TusPartSize       int64
+	TusUploadLifetime time.Duration
 }
 
 func loadConfig() *Config {
@@ -56,10 +151,83 @@ func loadConfig() *Config {
 		MinIOUseSSL:    getBoolEnv("MINIO_USE_SSL", false),
 		MinIORegion:    getEnv("MINIO_REGION", "us-east-1"),
 		// Server Configuration
-		GRPCPort:    getEnv("GRPC_PORT", "50051"),
-		HTTPPort:    getEnv("HTTP_PORT", "8080"),
-		UploadTTL:   getDurationEnv("UPLOAD_TTL", 3600) * time.Second,
-		DownloadTTL: getDurationEnv("DOWNLOAD_TTL", 3600) * time.Second,
+		GRPCPort:          getEnv("GRPC_PORT", "50051"),
+		HTTPPort:          getEnv("HTTP_PORT", "8080"),
+		GRPCLegacyResults: getBoolEnv("GRPC_LEGACY_RESULTS", false),
+		UploadTTL:         getDurationEnv("UPLOAD_TTL", 3600) * time.Second,
+		DownloadTTL:       getDurationEnv("DOWNLOAD_TTL", 3600) * time.Second,
+		MaxDownloadTTL:    getDurationEnv("MAX_DOWNLOAD_TTL", 86400) * time.Second,
+		DraftDownloadTTL:  getDurationEnv("DRAFT_DOWNLOAD_TTL", 300) * time.Second,
+		StreamTimeout:     getDurationEnv("STREAM_TIMEOUT", 3600) * time.Second,
+		// Draft Staging Configuration
+		DraftStaging:      getEnv("DRAFT_STAGING", storage.DraftStagingBucket),
+		DraftBucketSuffix: getEnv("DRAFT_BUCKET_SUFFIX", storage.DefaultDraftBucketSuffix),
+		DraftPrefix:       getEnv("DRAFT_PREFIX", storage.DefaultDraftPrefix),
+		// Validation Configuration
+		ValidationMaxSize:               getInt64Env("VALIDATION_MAX_SIZE", 0),
+		ValidationAllowedTypes:          getListEnv("VALIDATION_ALLOWED_TYPES"),
+		ValidationRequireExtensionMatch: getBoolEnv("VALIDATION_REQUIRE_EXTENSION_MATCH", false),
+		QuarantineBucket:                getEnv("QUARANTINE_BUCKET", ""),
+		// Malware Scanning Configuration
+		ClamdAddress:  getEnv("CLAMD_ADDRESS", ""),
+		ClamdTimeout:  getDurationEnv("CLAMD_TIMEOUT", 60) * time.Second,
+		ClamdFailOpen: getBoolEnv("CLAMD_FAIL_OPEN", false),
+		// Integrity Configuration
+		RequireChecksum: getBoolEnv("REQUIRE_CHECKSUM", false),
+		// Deduplication Configuration
+		DedupEnabled: getBoolEnv("DEDUP_ENABLED", false),
+		// Quota Configuration
+		QuotaEnabled:           getBoolEnv("QUOTA_ENABLED", false),
+		QuotaRepository:        getEnv("QUOTA_REPOSITORY", "storage"),
+		QuotaMaxDrafts:         getInt64Env("QUOTA_MAX_DRAFTS", 0),
+		QuotaMaxDraftBytes:     getInt64Env("QUOTA_MAX_DRAFT_BYTES", 0),
+		QuotaMaxConfirmedBytes: getInt64Env("QUOTA_MAX_CONFIRMED_BYTES", 0),
+		QuotaFile:              getEnv("QUOTA_FILE", ""),
+		ObjectLifetime:         getDurationEnv("OBJECT_LIFETIME", 86400) * time.Second,
+		// Derivative Processing Configuration
+		ImageDerivativesEnabled: getBoolEnv("IMAGE_DERIVATIVES_ENABLED", false),
+		ImageThumbnailWidths:    getListEnv("IMAGE_THUMBNAIL_WIDTHS"),
+		ImageFormat:             getEnv("IMAGE_FORMAT", ""),
+		ImageJPEGQuality:        getInt64Env("IMAGE_JPEG_QUALITY", image.DefaultJPEGQuality),
+		ImageMaxPixels:          getInt64Env("IMAGE_MAX_PIXELS", image.DefaultMaxPixels),
+		ImageMaxBytes:           getInt64Env("IMAGE_MAX_BYTES", image.DefaultMaxBytes),
+		ProcessorWorkers:        getInt64Env("PROCESSOR_WORKERS", 0),
+		// Plugin Configuration
+		PluginDir:              getEnv("PLUGIN_DIR", ""),
+		PluginTimeout:          getDurationEnv("PLUGIN_TIMEOUT", 1) * time.Second,
+		PluginMemoryLimitPages: getInt64Env("PLUGIN_MEMORY_LIMIT_PAGES", wasm.DefaultMemoryLimitPages),
+		// Authorization Configuration
+		AuthzRulesFile: getEnv("AUTHZ_RULES_FILE", ""),
+		// Authentication Configuration
+		AuthAllowAnonymous: getBoolEnv("AUTH_ALLOW_ANONYMOUS", false),
+		JWTIssuer:          getEnv("JWT_ISSUER", ""),
+		JWTAudience:        getEnv("JWT_AUDIENCE", ""),
+		JWTJWKS:            getEnv("JWT_JWKS", ""),
+		JWTJWKSRefresh:     getDurationEnv("JWT_JWKS_REFRESH", 300) * time.Second,
+		APIKeysFile:        getEnv("API_KEYS_FILE", ""),
+		AdminSubjects:      getListEnv("ADMIN_SUBJECTS"),
+		// TLS Configuration
+		TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:     getEnv("TLS_CLIENT_AUTH", ""),
+		TLSReloadInterval: getDurationEnv("TLS_RELOAD_INTERVAL", 30) * time.Second,
+		// Tenant Configuration
+		TenantsFile:          getEnv("TENANTS_FILE", ""),
+		TenantAttribute:      getEnv("TENANT_ATTRIBUTE", tenant.DefaultAttribute),
+		TenantRequired:       getBoolEnv("TENANT_REQUIRED", false),
+		TenantHeaderEnabled:  getBoolEnv("TENANT_HEADER_ENABLED", false),
+		TenantReloadInterval: getDurationEnv("TENANT_RELOAD_INTERVAL", 30) * time.Second,
+		// Download Link Configuration
+		LinkPublicPrefixes: getListEnv("LINK_PUBLIC_PREFIXES"),
+		LinkSecret:         getEnv("LINK_SECRET", ""),
+		LinkMaxTTL:         getDurationEnv("LINK_MAX_TTL", 3600) * time.Second,
+		LinkURLTTL:         getDurationEnv("LINK_URL_TTL", 0) * time.Second,
+		LinkCookie:         getEnv("LINK_COOKIE", ""),
+		// Resumable Upload Configuration
+		TusEnabled:        getBoolEnv("TUS_ENABLED", false),
+		TusPartSize:       getInt64Env("TUS_PART_SIZE", draft.DefaultResumablePartSize),
+		TusUploadLifetime: getDurationEnv("TUS_UPLOAD_LIFETIME", 0) * time.Second,
 	}
 	return cfg
 }
@@ -83,6 +251,25 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getDurationEnv(key string, defaultValue int64) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
@@ -124,6 +311,55 @@ func createStorageClient(cfg *Config) (storage.Storage, error) {
 	}
 }
 
+// createTenantStorageClient creates a tenant's own storage backend. Settings
+// the tenant leaves empty are taken from the server configuration.
+func createTenantStorageClient(cfg *Config, storageCfg *tenant.StorageConfig) (storage.Storage, error) {
+	tenantCfg := *cfg
+	tenantCfg.StorageType = storageCfg.Type
+	tenantCfg.MinIOUseSSL = storageCfg.UseSSL
+	if storageCfg.Region != "" {
+		tenantCfg.AWSRegion = storageCfg.Region
+		tenantCfg.MinIORegion = storageCfg.Region
+	}
+	if storageCfg.Endpoint != "" {
+		tenantCfg.MinIOEndpoint = storageCfg.Endpoint
+	}
+	if storageCfg.AccessKey != "" {
+		tenantCfg.MinIOAccessKey = storageCfg.AccessKey
+		tenantCfg.MinIOSecretKey = storageCfg.SecretKey
+	}
+	return createStorageClient(&tenantCfg)
+}
+
+// maxUploadSize returns the largest resumable upload the server accepts: the
+// validation maximum, if any, within what the part size can store.
+func maxUploadSize(cfg *Config) int64 {
+	partSize := cfg.TusPartSize
+	if partSize <= 0 {
+		partSize = draft.DefaultResumablePartSize
+	}
+	maxSize := draft.ResumablePolicy{PartSize: max(partSize, storage.MinPartSize)}.MaxSize()
+	if cfg.ValidationMaxSize > 0 {
+		return min(cfg.ValidationMaxSize, maxSize)
+	}
+	return maxSize
+}
+
+// createUsageRepository creates the quota repository of the given main bucket.
+func createUsageRepository(cfg *Config, storageClient storage.Storage, bucketName string) (repository.Repository, error) {
+	switch cfg.QuotaRepository {
+	case "memory":
+		return repository.NewMemoryRepository(), nil
+	case "storage":
+		return repository.NewStorageRepository(repository.StorageRepositoryOptions{
+			Storage:    storageClient,
+			BucketName: bucketName,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported quota repository: %s", cfg.QuotaRepository)
+	}
+}
+
 func main() {
 	startTime := time.Now()
 	log := logger.GetServiceLogger("server")
@@ -133,12 +369,67 @@ func main() {
 
 	// Log startup configuration
 	logger.LogStartup("server", map[string]interface{}{
-		"storage_type": cfg.StorageType,
-		"bucket_name":  cfg.BucketName,
-		"grpc_port":    cfg.GRPCPort,
-		"http_port":    cfg.HTTPPort,
-		"upload_ttl":   cfg.UploadTTL.String(),
-		"download_ttl": cfg.DownloadTTL.String(),
+		"storage_type":                       cfg.StorageType,
+		"bucket_name":                        cfg.BucketName,
+		"grpc_port":                          cfg.GRPCPort,
+		"http_port":                          cfg.HTTPPort,
+		"grpc_legacy_results":                cfg.GRPCLegacyResults,
+		"upload_ttl":                         cfg.UploadTTL.String(),
+		"download_ttl":                       cfg.DownloadTTL.String(),
+		"max_download_ttl":                   cfg.MaxDownloadTTL.String(),
+		"draft_download_ttl":                 cfg.DraftDownloadTTL.String(),
+		"stream_timeout":                     cfg.StreamTimeout.String(),
+		"draft_staging":                      cfg.DraftStaging,
+		"draft_bucket_suffix":                cfg.DraftBucketSuffix,
+		"draft_prefix":                       cfg.DraftPrefix,
+		"validation_max_size":                cfg.ValidationMaxSize,
+		"validation_allowed_types":           cfg.ValidationAllowedTypes,
+		"validation_require_extension_match": cfg.ValidationRequireExtensionMatch,
+		"quarantine_bucket":                  cfg.QuarantineBucket,
+		"clamd_address":                      cfg.ClamdAddress,
+		"clamd_timeout":                      cfg.ClamdTimeout.String(),
+		"clamd_fail_open":                    cfg.ClamdFailOpen,
+		"require_checksum":                   cfg.RequireChecksum,
+		"dedup_enabled":                      cfg.DedupEnabled,
+		"quota_enabled":                      cfg.QuotaEnabled,
+		"quota_repository":                   cfg.QuotaRepository,
+		"quota_max_drafts":                   cfg.QuotaMaxDrafts,
+		"quota_max_draft_bytes":              cfg.QuotaMaxDraftBytes,
+		"quota_max_confirmed_bytes":          cfg.QuotaMaxConfirmedBytes,
+		"quota_file":                         cfg.QuotaFile,
+		"object_lifetime":                    cfg.ObjectLifetime.String(),
+		"image_derivatives_enabled":          cfg.ImageDerivativesEnabled,
+		"image_thumbnail_widths":             cfg.ImageThumbnailWidths,
+		"image_format":                       cfg.ImageFormat,
+		"processor_workers":                  cfg.ProcessorWorkers,
+		"plugin_dir":                         cfg.PluginDir,
+		"plugin_timeout":                     cfg.PluginTimeout.String(),
+		"plugin_memory_limit_pages":          cfg.PluginMemoryLimitPages,
+		"authz_rules_file":                   cfg.AuthzRulesFile,
+		"auth_allow_anonymous":               cfg.AuthAllowAnonymous,
+		"jwt_issuer":                         cfg.JWTIssuer,
+		"jwt_audience":                       cfg.JWTAudience,
+		"jwt_jwks":                           cfg.JWTJWKS,
+		"jwt_jwks_refresh":                   cfg.JWTJWKSRefresh.String(),
+		"api_keys_file":                      cfg.APIKeysFile,
+		"admin_subjects":                     cfg.AdminSubjects,
+		"tls_cert_file":                      cfg.TLSCertFile,
+		"tls_client_ca_file":                 cfg.TLSClientCAFile,
+		"tls_client_auth":                    cfg.TLSClientAuth,
+		"tls_reload_interval":                cfg.TLSReloadInterval.String(),
+		"tenants_file":                       cfg.TenantsFile,
+		"tenant_attribute":                   cfg.TenantAttribute,
+		"tenant_required":                    cfg.TenantRequired,
+		"tenant_header_enabled":              cfg.TenantHeaderEnabled,
+		"tenant_reload_interval":             cfg.TenantReloadInterval.String(),
+		"link_public_prefixes":               cfg.LinkPublicPrefixes,
+		"link_signing_enabled":               cfg.LinkSecret != "",
+		"link_max_ttl":                       cfg.LinkMaxTTL.String(),
+		"link_url_ttl":                       cfg.LinkURLTTL.String(),
+		"link_cookie":                        cfg.LinkCookie,
+		"tus_enabled":                        cfg.TusEnabled,
+		"tus_part_size":                      cfg.TusPartSize,
+		"tus_upload_lifetime":                cfg.TusUploadLifetime.String(),
 	})
 
 	switch cfg.StorageType {
@@ -167,6 +458,8 @@ func main() {
 		cfg.HTTPPort = "8080"
 		cfg.UploadTTL = 3600 * time.Second
 		cfg.DownloadTTL = 3600 * time.Second
+		cfg.MaxDownloadTTL = 86400 * time.Second
+		cfg.DraftDownloadTTL = 300 * time.Second
 
 		log.Info().
 			Str("storage_type", cfg.StorageType).
@@ -191,21 +484,320 @@ func main() {
 		Str("storage_type", cfg.StorageType).
 		Msg("Storage client initialized successfully")
 
+	// Initialize malware scanner
+	var malwareScanner scanner.Scanner
+	if cfg.ClamdAddress != "" {
+		log.Info().
+			Str("address", cfg.ClamdAddress).
+			Msg("Initializing clamd scanner")
+		clamdClient, err := clamd.NewClient(clamd.ClientOptions{
+			Address: cfg.ClamdAddress,
+			Timeout: cfg.ClamdTimeout,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create clamd scanner")
+		}
+		malwareScanner = clamdClient
+	}
+
+	// Initialize derivative processors
+	var processors []processor.Processor
+	if cfg.ImageDerivativesEnabled {
+		widths := make([]int, 0, len(cfg.ImageThumbnailWidths))
+		for _, value := range cfg.ImageThumbnailWidths {
+			width, err := strconv.Atoi(value)
+			if err != nil {
+				log.Fatal().
+					Err(err).
+					Str("width", value).
+					Msg("Invalid thumbnail width")
+			}
+			widths = append(widths, width)
+		}
+
+		imageProcessor, err := image.NewProcessor(image.ProcessorOptions{
+			Widths:      widths,
+			Format:      cfg.ImageFormat,
+			JPEGQuality: int(cfg.ImageJPEGQuality),
+			MaxPixels:   int(cfg.ImageMaxPixels),
+			MaxBytes:    cfg.ImageMaxBytes,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create image processor")
+		}
+		processors = append(processors, imageProcessor)
+	}
+
+	// Initialize WebAssembly plugins
+	var hooks draft.Hooks
+	if cfg.PluginDir != "" {
+		log.Info().
+			Str("directory", cfg.PluginDir).
+			Msg("Loading WebAssembly plugins")
+		pluginHost, err := wasm.NewHost(context.Background(), wasm.HostOptions{
+			Directory:        cfg.PluginDir,
+			Timeout:          cfg.PluginTimeout,
+			MemoryLimitPages: uint32(cfg.PluginMemoryLimitPages),
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to load WebAssembly plugins")
+		}
+		defer pluginHost.Close(context.Background())
+
+		hooks.PreUpload = append(hooks.PreUpload, pluginHost)
+		hooks.PreConfirm = append(hooks.PreConfirm, pluginHost)
+	}
+
+	// Initialize API key store
+	var apiKeyStore apikey.Store
+	if cfg.APIKeysFile != "" {
+		log.Info().
+			Str("path", cfg.APIKeysFile).
+			Msg("Loading API keys")
+		fileStore, err := apikey.NewFileStore(cfg.APIKeysFile)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to load API keys")
+		}
+		apiKeyStore = fileStore
+	}
+
+	// Initialize authorization rules
+	var ruleAuthorizer authz.Authorizer
+	if cfg.AuthzRulesFile != "" {
+		log.Info().
+			Str("path", cfg.AuthzRulesFile).
+			Msg("Loading authorization rules")
+		rules, err := authzCEL.LoadRules(cfg.AuthzRulesFile)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to load authorization rules")
+		}
+		celAuthorizer, err := authzCEL.NewAuthorizer(authzCEL.AuthorizerOptions{
+			Rules: rules,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create authorizer")
+		}
+		ruleAuthorizer = celAuthorizer
+	}
+
+	// API key callers are additionally limited to their scopes and prefix
+	authorizer := ruleAuthorizer
+	if apiKeyStore != nil {
+		scopeAuthorizer := authz.All{apikey.ScopeAuthorizer{}}
+		if ruleAuthorizer != nil {
+			scopeAuthorizer = append(scopeAuthorizer, ruleAuthorizer)
+		}
+		authorizer = scopeAuthorizer
+	}
+
+	// Initialize usage accounting and quotas
+	var usageRepository repository.Repository
+	quotas := draft.QuotaPolicy{
+		Default: repository.Quota{
+			MaxDrafts:         cfg.QuotaMaxDrafts,
+			MaxDraftBytes:     cfg.QuotaMaxDraftBytes,
+			MaxConfirmedBytes: cfg.QuotaMaxConfirmedBytes,
+		},
+	}
+	// Resumable uploads keep their state in the same repository
+	if cfg.QuotaEnabled || cfg.TusEnabled {
+		log.Info().
+			Str("repository", cfg.QuotaRepository).
+			Msg("Initializing usage repository")
+		usageRepository, err = createUsageRepository(cfg, storageClient, cfg.BucketName)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create usage repository")
+		}
+	}
+	if cfg.QuotaEnabled {
+		if cfg.QuotaFile != "" {
+			quotas.Owners, err = draft.LoadQuotaOverrides(cfg.QuotaFile)
+			if err != nil {
+				log.Fatal().
+					Err(err).
+					Msg("Failed to load quota overrides")
+			}
+		}
+	}
+
 	// Initialize draft service
 	log.Info().Msg("Initializing draft service")
-	draftService, err := draft.NewService(draft.ServiceOptions{
-		BucketName:  cfg.BucketName,
-		Storage:     storageClient,
-		UploadTTL:   cfg.UploadTTL,
-		DownloadTTL: cfg.DownloadTTL,
-	})
+	draftOptions := draft.ServiceOptions{
+		BucketName: cfg.BucketName,
+		DraftStaging: storage.DraftStaging{
+			Layout:       cfg.DraftStaging,
+			BucketSuffix: cfg.DraftBucketSuffix,
+			Prefix:       cfg.DraftPrefix,
+		},
+		Storage:          storageClient,
+		UploadTTL:        cfg.UploadTTL,
+		DownloadTTL:      cfg.DownloadTTL,
+		MaxDownloadTTL:   cfg.MaxDownloadTTL,
+		DraftDownloadTTL: cfg.DraftDownloadTTL,
+		Validation: draft.ValidationPolicy{
+			MaxSize:               cfg.ValidationMaxSize,
+			AllowedContentTypes:   cfg.ValidationAllowedTypes,
+			RequireExtensionMatch: cfg.ValidationRequireExtensionMatch,
+			QuarantineBucket:      cfg.QuarantineBucket,
+		},
+		Scanner:          malwareScanner,
+		ScanFailOpen:     cfg.ClamdFailOpen,
+		RequireChecksum:  cfg.RequireChecksum,
+		Dedup:            cfg.DedupEnabled,
+		Processors:       processors,
+		ProcessorWorkers: int(cfg.ProcessorWorkers),
+		Hooks:            hooks,
+		Authorizer:       authorizer,
+		Repository:       usageRepository,
+		Quotas:           quotas,
+		DraftLifetime:    cfg.ObjectLifetime,
+		Links: draft.LinkPolicy{
+			PublicPrefixes: cfg.LinkPublicPrefixes,
+			Secret:         []byte(cfg.LinkSecret),
+			MaxTTL:         cfg.LinkMaxTTL,
+			URLTTL:         cfg.LinkURLTTL,
+		},
+		Resumable: draft.ResumablePolicy{
+			Enabled:  cfg.TusEnabled,
+			PartSize: cfg.TusPartSize,
+			Lifetime: cfg.TusUploadLifetime,
+		},
+	}
+	draftService, err := draft.NewService(draftOptions)
 	if err != nil {
 		log.Fatal().
 			Err(err).
 			Msg("Failed to create draft service")
 	}
+	defer draftService.Close()
 	log.Info().Msg("Draft service initialized successfully")
 
+	// Route requests to per-tenant draft services
+	var draftAPI draft.API = draftService
+	if cfg.TenantsFile != "" {
+		log.Info().
+			Str("path", cfg.TenantsFile).
+			Msg("Loading tenants")
+		registry, err := tenant.NewRegistry(tenant.RegistryOptions{
+			Path:           cfg.TenantsFile,
+			ReloadInterval: cfg.TenantReloadInterval,
+			Build: func(tenantCfg tenant.Config) (*draft.Service, error) {
+				return createTenantService(cfg, draftOptions, tenantCfg)
+			},
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to load tenants")
+		}
+		defer registry.Close()
+
+		routerOptions := tenant.RouterOptions{
+			Registry:  registry,
+			Attribute: cfg.TenantAttribute,
+		}
+		if !cfg.TenantRequired {
+			routerOptions.Default = draftService
+		}
+		router, err := tenant.NewRouter(routerOptions)
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create tenant router")
+		}
+		draftAPI = router
+	}
+
+	// Initialize authentication
+	var authenticators auth.Chain
+	// Partial settings fail startup rather than disabling authentication
+	if cfg.JWTIssuer != "" || cfg.JWTAudience != "" || cfg.JWTJWKS != "" {
+		log.Info().
+			Str("issuer", cfg.JWTIssuer).
+			Str("audience", cfg.JWTAudience).
+			Str("jwks", cfg.JWTJWKS).
+			Msg("Initializing JWT authentication")
+		verifier, err := jwt.NewVerifier(context.Background(), jwt.VerifierOptions{
+			Issuer:          cfg.JWTIssuer,
+			Audience:        cfg.JWTAudience,
+			JWKSSource:      cfg.JWTJWKS,
+			RefreshInterval: cfg.JWTJWKSRefresh,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create JWT verifier")
+		}
+		defer verifier.Close()
+		authenticators = append(authenticators, verifier)
+	}
+
+	// Initialize API key authentication and management
+	var keyService *apikeyService.Service
+	if apiKeyStore != nil {
+		authenticators = append(authenticators, apikey.NewAuthenticator(apiKeyStore))
+
+		keyService, err = apikeyService.NewService(apikeyService.ServiceOptions{
+			Store:      apiKeyStore,
+			Admins:     cfg.AdminSubjects,
+			Authorizer: ruleAuthorizer,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to create API key service")
+		}
+	}
+
+	// Initialize TLS for both listeners
+	var tlsReloader *mtls.Reloader
+	if cfg.TLSCertFile != "" {
+		log.Info().
+			Str("cert_file", cfg.TLSCertFile).
+			Str("client_ca_file", cfg.TLSClientCAFile).
+			Msg("Initializing TLS")
+		tlsReloader, err = mtls.NewReloader(mtls.ReloaderOptions{
+			CertFile:       cfg.TLSCertFile,
+			KeyFile:        cfg.TLSKeyFile,
+			ClientCAFile:   cfg.TLSClientCAFile,
+			ClientAuth:     cfg.TLSClientAuth,
+			ReloadInterval: cfg.TLSReloadInterval,
+		})
+		if err != nil {
+			log.Fatal().
+				Err(err).
+				Msg("Failed to initialize TLS")
+		}
+		defer tlsReloader.Close()
+
+		// Client certificates are tried after explicit credentials
+		if tlsReloader.VerifiesClients() {
+			authenticators = append(authenticators, mtls.NewAuthenticator())
+		}
+	}
+
+	// Requests are checked against the protovalidate rules of their messages
+	validator, err := protovalidate.New()
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to create request validator")
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -214,14 +806,14 @@ func main() {
 	log.Info().
 		Str("port", cfg.GRPCPort).
 		Msg("Starting gRPC server")
-	grpcServer := startGRPCServer(cfg.GRPCPort, draftService)
+	grpcServer := startGRPCServer(cfg, draftAPI, keyService, validator, authenticators, tlsReloader)
 	defer grpcServer.GracefulStop()
 
 	// Start HTTP server
 	log.Info().
 		Str("port", cfg.HTTPPort).
 		Msg("Starting HTTP server")
-	httpServer := startHTTPServer(cfg.HTTPPort, draftService)
+	httpServer := startHTTPServer(cfg, draftAPI, keyService, validator, authenticators, tlsReloader)
 	defer func() {
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer shutdownCancel()
@@ -244,19 +836,94 @@ func main() {
 	logger.LogShutdown("server", time.Since(startTime))
 }
 
-func startGRPCServer(port string, draftService *draft.Service) *grpc.Server {
+// createTenantService creates a tenant's draft service from the server's
+// draft options, routed to the tenant's buckets, prefix and storage.
+func createTenantService(cfg *Config, opts draft.ServiceOptions, tenantCfg tenant.Config) (*draft.Service, error) {
+	opts.Tenant = tenantCfg.ID
+	if tenantCfg.BucketName != "" {
+		opts.BucketName = tenantCfg.BucketName
+	}
+	if tenantCfg.Storage != nil {
+		tenantStorage, err := createTenantStorageClient(cfg, tenantCfg.Storage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create storage client: %w", err)
+		}
+		opts.Storage = tenantStorage
+	}
+	if tenantCfg.KeyPrefix != "" {
+		prefixStorage, err := prefix.NewStorage(prefix.StorageOptions{
+			Storage: opts.Storage,
+			Prefix:  tenantCfg.KeyPrefix,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prefix storage: %w", err)
+		}
+		opts.Storage = prefixStorage
+	}
+
+	// Usage is accounted separately for every tenant
+	if opts.Repository != nil {
+		usageRepository, err := createUsageRepository(cfg, opts.Storage, opts.BucketName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create usage repository: %w", err)
+		}
+		opts.Repository = usageRepository
+	}
+
+	return draft.NewService(opts)
+}
+
+func startGRPCServer(cfg *Config, draftService draft.API, keyService *apikeyService.Service, validator protovalidate.Validator, authenticators auth.Chain, tlsReloader *mtls.Reloader) *grpc.Server {
 	log := logger.GetServiceLogger("grpc-server")
+	port := cfg.GRPCPort
+
+	var serverOptions []grpc.ServerOption
+	if tlsReloader != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsReloader.ServerConfig("h2"))))
+	}
+
+	// Authenticate every call when any authenticator is configured
+	var interceptors []grpc.UnaryServerInterceptor
+	if len(authenticators) > 0 {
+		interceptors = append(interceptors, grpcController.AuthUnaryInterceptor(authenticators, cfg.AuthAllowAnonymous))
+	}
+	// Let unbound callers pick their tenant only when explicitly enabled
+	if cfg.TenantsFile != "" && cfg.TenantHeaderEnabled {
+		interceptors = append(interceptors, grpcController.TenantUnaryInterceptor())
+	}
+	interceptors = append(interceptors, grpcController.ValidationUnaryInterceptor(validator))
+	serverOptions = append(serverOptions, grpc.ChainUnaryInterceptor(interceptors...))
+
+	// Streaming calls go through the same checks
+	var streamInterceptors []grpc.StreamServerInterceptor
+	if len(authenticators) > 0 {
+		streamInterceptors = append(streamInterceptors, grpcController.AuthStreamInterceptor(authenticators, cfg.AuthAllowAnonymous))
+	}
+	if cfg.TenantsFile != "" && cfg.TenantHeaderEnabled {
+		streamInterceptors = append(streamInterceptors, grpcController.TenantStreamInterceptor())
+	}
+	streamInterceptors = append(streamInterceptors, grpcController.ValidationStreamInterceptor(validator))
+	serverOptions = append(serverOptions, grpc.ChainStreamInterceptor(streamInterceptors...))
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverOptions...)
 
 	// Create and register draft service
 	draftGRPCServer := grpcController.NewServer(grpcController.ServerOptions{
-		DraftService: draftService,
-		Address:      ":" + port,
+		DraftService:  draftService,
+		Address:       ":" + port,
+		LegacyResults: cfg.GRPCLegacyResults,
 	})
 
 	draftv1.RegisterDraftServiceServer(grpcServer, draftGRPCServer)
+
+	// Register API key management when API keys are enabled
+	if keyService != nil {
+		draftv1.RegisterAdminServiceServer(grpcServer, grpcController.NewAdminServer(grpcController.AdminServerOptions{
+			APIKeyService: keyService,
+			LegacyResults: cfg.GRPCLegacyResults,
+		}))
+	}
 	log.Info().
 		Str("port", port).
 		Msg("gRPC service registered")
@@ -288,26 +955,33 @@ func startGRPCServer(port string, draftService *draft.Service) *grpc.Server {
 	return grpcServer
 }
 
-func startHTTPServer(port string, draftService *draft.Service) *http.Server {
+func startHTTPServer(cfg *Config, draftService draft.API, keyService *apikeyService.Service, validator protovalidate.Validator, authenticators auth.Chain, tlsReloader *mtls.Reloader) *http.Server {
 	log := logger.GetServiceLogger("http-server")
+	port := cfg.HTTPPort
 
 	// Create router with middleware
 	router := chi.NewRouter()
 
 	// Add middleware
+	router.Use(middleware.RequestID)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(60 * time.Second))
+	// Uploads and downloads streamed through the server may outlast the request timeout
+	router.Use(webapiMiddleware.Timeout(60*time.Second, cfg.StreamTimeout, func(r *http.Request) bool {
+		return webapiHandler.IsStream(r) || connectController.IsStream(r)
+	}))
 	router.Use(middleware.Heartbeat("/health"))
 
 	// Add CORS headers
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Tenant-ID, X-Request-Id, If-None-Match, If-Modified-Since, If-Range, Range, X-Checksum-Algorithm, X-Checksum, Connect-Protocol-Version, Connect-Timeout-Ms, Grpc-Timeout, X-Grpc-Web, X-User-Agent, Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata, Upload-Defer-Length")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-Id, Retry-After, ETag, Location, Accept-Ranges, Content-Range, Content-Length, Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size, Upload-Offset, Upload-Length, Upload-Metadata, Upload-Expires")
 
-			if r.Method == "OPTIONS" {
+			// Only preflights are answered here; tus clients discover the server with plain OPTIONS requests
+			if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
@@ -316,22 +990,92 @@ func startHTTPServer(port string, draftService *draft.Service) *http.Server {
 		})
 	})
 
-	log.Info().Msg("HTTP middleware configured")
+	// Serve the OpenAPI document and explorer without credentials
+	openAPIHandler, err := openapi.NewHandler()
+	if err != nil {
+		log.Fatal().
+			Err(err).
+			Msg("Failed to load OpenAPI document")
+	}
+	openAPIHandler.RegisterRoutes(router)
+
+	// Download links are public or signed, so they accept requests without credentials
+	var linkMiddlewares []func(http.Handler) http.Handler
+	if cfg.LinkCookie != "" {
+		linkMiddlewares = append(linkMiddlewares, webapiMiddleware.CookieCredentials(cfg.LinkCookie))
+	}
+	if len(authenticators) > 0 {
+		linkMiddlewares = append(linkMiddlewares, webapiMiddleware.Authenticate(authenticators, true))
+	}
+	if cfg.TenantsFile != "" && cfg.TenantHeaderEnabled {
+		linkMiddlewares = append(linkMiddlewares, webapiMiddleware.Tenant)
+	}
+	linkRouter := router.With(linkMiddlewares...)
+
+	router.Group(func(api chi.Router) {
+		// Authenticate every request when any authenticator is configured
+		if len(authenticators) > 0 {
+			api.Use(webapiMiddleware.Authenticate(authenticators, cfg.AuthAllowAnonymous))
+		}
+
+		// Let unbound callers pick their tenant only when explicitly enabled
+		if cfg.TenantsFile != "" && cfg.TenantHeaderEnabled {
+			api.Use(webapiMiddleware.Tenant)
+		}
 
-	// Create web API server
-	webAPIServer := webapiController.NewServer(webapiController.ServerOptions{
-		Router:       router,
-		Address:      ":" + port,
-		DraftService: draftService,
+		log.Info().Msg("HTTP middleware configured")
+
+		// Create web API server
+		webapiController.NewServer(webapiController.ServerOptions{
+			Router:        api,
+			Address:       ":" + port,
+			DraftService:  draftService,
+			Validator:     validator,
+			APIKeyService: keyService,
+			LinkRouter:    linkRouter,
+			// Without authenticators every caller is anonymous
+			LinkAllowAnonymous: cfg.AuthAllowAnonymous || len(authenticators) == 0,
+			ResumableUploads:   cfg.TusEnabled,
+			MaxUploadSize:      maxUploadSize(cfg),
+		})
+
+		// Serve the DraftService over Connect, gRPC and gRPC-Web on the same port
+		connectServer := connectController.NewServer(connectController.ServerOptions{
+			Server: grpcController.NewServer(grpcController.ServerOptions{
+				DraftService:  draftService,
+				Address:       ":" + port,
+				LegacyResults: cfg.GRPCLegacyResults,
+			}),
+			Validator: validator,
+		})
+		connectPath, connectHandler := connectServer.Handler()
+		api.Handle(connectPath+"*", connectHandler)
 	})
 
+	// Warn when the REST routes drift from the OpenAPI document
+	if err := openAPIHandler.CheckRoutes(router); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("OpenAPI document does not match the REST routes")
+	}
+
+	// gRPC needs HTTP/2, which plaintext listeners only speak with prior knowledge
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
 	// Create HTTP server
 	httpServer := &http.Server{
 		Addr:         ":" + port,
-		Handler:      webAPIServer.GetRouter(),
+		Handler:      router,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		Protocols:    protocols,
+	}
+	if tlsReloader != nil {
+		httpServer.TLSConfig = tlsReloader.ServerConfig("h2", "http/1.1")
 	}
 
 	log.Info().
@@ -346,7 +1090,13 @@ func startHTTPServer(port string, draftService *draft.Service) *http.Server {
 		log.Info().
 			Str("address", ":"+port).
 			Msg("HTTP server starting to serve")
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal().
 				Err(err).
 				Msg("Failed to serve HTTP")
@@ -0,0 +1,737 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: draft/v1/admin.proto
+
+package draftv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Scope granted to an API key
+type APIKeyScope int32
+
+const (
+	APIKeyScope_API_KEY_SCOPE_UNSPECIFIED APIKeyScope = 0
+	// Issue upload URLs, preview drafts and cancel uploads
+	APIKeyScope_API_KEY_SCOPE_UPLOAD   APIKeyScope = 1
+	APIKeyScope_API_KEY_SCOPE_CONFIRM  APIKeyScope = 2
+	APIKeyScope_API_KEY_SCOPE_DOWNLOAD APIKeyScope = 3
+	// Every operation, including API key management
+	APIKeyScope_API_KEY_SCOPE_ADMIN APIKeyScope = 4
+)
+
+// Enum value maps for APIKeyScope.
+var (
+	APIKeyScope_name = map[int32]string{
+		0: "API_KEY_SCOPE_UNSPECIFIED",
+		1: "API_KEY_SCOPE_UPLOAD",
+		2: "API_KEY_SCOPE_CONFIRM",
+		3: "API_KEY_SCOPE_DOWNLOAD",
+		4: "API_KEY_SCOPE_ADMIN",
+	}
+	APIKeyScope_value = map[string]int32{
+		"API_KEY_SCOPE_UNSPECIFIED": 0,
+		"API_KEY_SCOPE_UPLOAD":      1,
+		"API_KEY_SCOPE_CONFIRM":     2,
+		"API_KEY_SCOPE_DOWNLOAD":    3,
+		"API_KEY_SCOPE_ADMIN":       4,
+	}
+)
+
+func (x APIKeyScope) Enum() *APIKeyScope {
+	p := new(APIKeyScope)
+	*p = x
+	return p
+}
+
+func (x APIKeyScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (APIKeyScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_draft_v1_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (APIKeyScope) Type() protoreflect.EnumType {
+	return &file_draft_v1_admin_proto_enumTypes[0]
+}
+
+func (x APIKeyScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use APIKeyScope.Descriptor instead.
+func (APIKeyScope) EnumDescriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+// APIKey describes a key without its secret
+type APIKey struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []APIKeyScope          `protobuf:"varint,3,rep,packed,name=scopes,proto3,enum=draft.v1.APIKeyScope" json:"scopes,omitempty"`
+	// Object keys the key may act on must start with this prefix
+	KeyPrefix     string                 `protobuf:"bytes,4,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RevokedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_draft_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []APIKeyScope {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+// CreateAPIKey messages
+type CreateAPIKeyRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Name      string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes    []APIKeyScope          `protobuf:"varint,2,rep,packed,name=scopes,proto3,enum=draft.v1.APIKeyScope" json:"scopes,omitempty"`
+	KeyPrefix string                 `protobuf:"bytes,3,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
+	// Optional key lifetime in seconds; the key never expires when zero
+	TtlSeconds    int64 `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_draft_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []APIKeyScope {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CreateAPIKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Key    *APIKey                `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Secret to send in the X-API-Key header; it is only returned once
+	Secret        string `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_draft_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateAPIKeyResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+// ListAPIKeys messages
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_draft_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Keys          []*APIKey              `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_draft_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListAPIKeysResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ListAPIKeysResponse) GetKeys() []*APIKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// RevokeAPIKey messages
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_draft_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RevokeAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_draft_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RevokeAPIKeyResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// RotateAPIKey messages
+type RotateAPIKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Time in seconds the old key keeps working
+	GracePeriodSeconds int64 `protobuf:"varint,2,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3" json:"grace_period_seconds,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_draft_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RotateAPIKeyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyRequest) GetGracePeriodSeconds() int64 {
+	if x != nil {
+		return x.GracePeriodSeconds
+	}
+	return 0
+}
+
+type RotateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Key           *APIKey                `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Secret        string                 `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyResponse) Reset() {
+	*x = RotateAPIKeyResponse{}
+	mi := &file_draft_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyResponse) ProtoMessage() {}
+
+func (x *RotateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RotateAPIKeyResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *RotateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *RotateAPIKeyResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+var File_draft_v1_admin_proto protoreflect.FileDescriptor
+
+const file_draft_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x14draft/v1/admin.proto\x12\bdraft.v1\x1a\x14draft/v1/draft.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xab\x02\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\x06scopes\x18\x03 \x03(\x0e2\x15.draft.v1.APIKeyScopeR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x04 \x01(\tR\tkeyPrefix\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\"\x98\x01\n" +
+	"\x13CreateAPIKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\x06scopes\x18\x02 \x03(\x0e2\x15.draft.v1.APIKeyScopeR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x03 \x01(\tR\tkeyPrefix\x12\x1f\n" +
+	"\vttl_seconds\x18\x04 \x01(\x03R\n" +
+	"ttlSeconds\"|\n" +
+	"\x14CreateAPIKeyResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\"\n" +
+	"\x03key\x18\x02 \x01(\v2\x10.draft.v1.APIKeyR\x03key\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\"\x14\n" +
+	"\x12ListAPIKeysRequest\"e\n" +
+	"\x13ListAPIKeysResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12$\n" +
+	"\x04keys\x18\x02 \x03(\v2\x10.draft.v1.APIKeyR\x04keys\"%\n" +
+	"\x13RevokeAPIKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"@\n" +
+	"\x14RevokeAPIKeyResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\"W\n" +
+	"\x13RotateAPIKeyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x14grace_period_seconds\x18\x02 \x01(\x03R\x12gracePeriodSeconds\"|\n" +
+	"\x14RotateAPIKeyResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\"\n" +
+	"\x03key\x18\x02 \x01(\v2\x10.draft.v1.APIKeyR\x03key\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret*\x96\x01\n" +
+	"\vAPIKeyScope\x12\x1d\n" +
+	"\x19API_KEY_SCOPE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14API_KEY_SCOPE_UPLOAD\x10\x01\x12\x19\n" +
+	"\x15API_KEY_SCOPE_CONFIRM\x10\x02\x12\x1a\n" +
+	"\x16API_KEY_SCOPE_DOWNLOAD\x10\x03\x12\x17\n" +
+	"\x13API_KEY_SCOPE_ADMIN\x10\x042\xc7\x02\n" +
+	"\fAdminService\x12M\n" +
+	"\fCreateAPIKey\x12\x1d.draft.v1.CreateAPIKeyRequest\x1a\x1e.draft.v1.CreateAPIKeyResponse\x12J\n" +
+	"\vListAPIKeys\x12\x1c.draft.v1.ListAPIKeysRequest\x1a\x1d.draft.v1.ListAPIKeysResponse\x12M\n" +
+	"\fRevokeAPIKey\x12\x1d.draft.v1.RevokeAPIKeyRequest\x1a\x1e.draft.v1.RevokeAPIKeyResponse\x12M\n" +
+	"\fRotateAPIKey\x12\x1d.draft.v1.RotateAPIKeyRequest\x1a\x1e.draft.v1.RotateAPIKeyResponseB\x91\x01\n" +
+	"\fcom.draft.v1B\n" +
+	"AdminProtoP\x01Z4github.com/snowmerak/DraftStore/gen/draft/v1;draftv1\xa2\x02\x03DXX\xaa\x02\bDraft.V1\xca\x02\bDraft\\V1\xe2\x02\x14Draft\\V1\\GPBMetadata\xea\x02\tDraft::V1b\x06proto3"
+
+var (
+	file_draft_v1_admin_proto_rawDescOnce sync.Once
+	file_draft_v1_admin_proto_rawDescData []byte
+)
+
+func file_draft_v1_admin_proto_rawDescGZIP() []byte {
+	file_draft_v1_admin_proto_rawDescOnce.Do(func() {
+		file_draft_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_draft_v1_admin_proto_rawDesc), len(file_draft_v1_admin_proto_rawDesc)))
+	})
+	return file_draft_v1_admin_proto_rawDescData
+}
+
+var file_draft_v1_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_draft_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_draft_v1_admin_proto_goTypes = []any{
+	(APIKeyScope)(0),              // 0: draft.v1.APIKeyScope
+	(*APIKey)(nil),                // 1: draft.v1.APIKey
+	(*CreateAPIKeyRequest)(nil),   // 2: draft.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),  // 3: draft.v1.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),    // 4: draft.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),   // 5: draft.v1.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),   // 6: draft.v1.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),  // 7: draft.v1.RevokeAPIKeyResponse
+	(*RotateAPIKeyRequest)(nil),   // 8: draft.v1.RotateAPIKeyRequest
+	(*RotateAPIKeyResponse)(nil),  // 9: draft.v1.RotateAPIKeyResponse
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*Result)(nil),                // 11: draft.v1.Result
+}
+var file_draft_v1_admin_proto_depIdxs = []int32{
+	0,  // 0: draft.v1.APIKey.scopes:type_name -> draft.v1.APIKeyScope
+	10, // 1: draft.v1.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	10, // 2: draft.v1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	10, // 3: draft.v1.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: draft.v1.CreateAPIKeyRequest.scopes:type_name -> draft.v1.APIKeyScope
+	11, // 5: draft.v1.CreateAPIKeyResponse.result:type_name -> draft.v1.Result
+	1,  // 6: draft.v1.CreateAPIKeyResponse.key:type_name -> draft.v1.APIKey
+	11, // 7: draft.v1.ListAPIKeysResponse.result:type_name -> draft.v1.Result
+	1,  // 8: draft.v1.ListAPIKeysResponse.keys:type_name -> draft.v1.APIKey
+	11, // 9: draft.v1.RevokeAPIKeyResponse.result:type_name -> draft.v1.Result
+	11, // 10: draft.v1.RotateAPIKeyResponse.result:type_name -> draft.v1.Result
+	1,  // 11: draft.v1.RotateAPIKeyResponse.key:type_name -> draft.v1.APIKey
+	2,  // 12: draft.v1.AdminService.CreateAPIKey:input_type -> draft.v1.CreateAPIKeyRequest
+	4,  // 13: draft.v1.AdminService.ListAPIKeys:input_type -> draft.v1.ListAPIKeysRequest
+	6,  // 14: draft.v1.AdminService.RevokeAPIKey:input_type -> draft.v1.RevokeAPIKeyRequest
+	8,  // 15: draft.v1.AdminService.RotateAPIKey:input_type -> draft.v1.RotateAPIKeyRequest
+	3,  // 16: draft.v1.AdminService.CreateAPIKey:output_type -> draft.v1.CreateAPIKeyResponse
+	5,  // 17: draft.v1.AdminService.ListAPIKeys:output_type -> draft.v1.ListAPIKeysResponse
+	7,  // 18: draft.v1.AdminService.RevokeAPIKey:output_type -> draft.v1.RevokeAPIKeyResponse
+	9,  // 19: draft.v1.AdminService.RotateAPIKey:output_type -> draft.v1.RotateAPIKeyResponse
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_draft_v1_admin_proto_init() }
+func file_draft_v1_admin_proto_init() {
+	if File_draft_v1_admin_proto != nil {
+		return
+	}
+	file_draft_v1_draft_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_draft_v1_admin_proto_rawDesc), len(file_draft_v1_admin_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_draft_v1_admin_proto_goTypes,
+		DependencyIndexes: file_draft_v1_admin_proto_depIdxs,
+		EnumInfos:         file_draft_v1_admin_proto_enumTypes,
+		MessageInfos:      file_draft_v1_admin_proto_msgTypes,
+	}.Build()
+	File_draft_v1_admin_proto = out.File
+	file_draft_v1_admin_proto_goTypes = nil
+	file_draft_v1_admin_proto_depIdxs = nil
+}
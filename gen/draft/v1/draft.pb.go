@@ -7,6 +7,8 @@
 package draftv1
 
 import (
+	_ "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -37,6 +39,10 @@ const (
 	ErrorType_ERROR_TYPE_DELETE_FAILED          ErrorType = 9
 	ErrorType_ERROR_TYPE_PRESIGNED_URL_FAILED   ErrorType = 10
 	ErrorType_ERROR_TYPE_INTERNAL_ERROR         ErrorType = 11
+	ErrorType_ERROR_TYPE_VALIDATION_FAILED      ErrorType = 12
+	ErrorType_ERROR_TYPE_INFECTED               ErrorType = 13
+	ErrorType_ERROR_TYPE_CHECKSUM_MISMATCH      ErrorType = 14
+	ErrorType_ERROR_TYPE_INVALID_REQUEST        ErrorType = 15
 )
 
 // Enum value maps for ErrorType.
@@ -54,6 +60,10 @@ var (
 		9:  "ERROR_TYPE_DELETE_FAILED",
 		10: "ERROR_TYPE_PRESIGNED_URL_FAILED",
 		11: "ERROR_TYPE_INTERNAL_ERROR",
+		12: "ERROR_TYPE_VALIDATION_FAILED",
+		13: "ERROR_TYPE_INFECTED",
+		14: "ERROR_TYPE_CHECKSUM_MISMATCH",
+		15: "ERROR_TYPE_INVALID_REQUEST",
 	}
 	ErrorType_value = map[string]int32{
 		"ERROR_TYPE_UNSPECIFIED":            0,
@@ -68,6 +78,10 @@ var (
 		"ERROR_TYPE_DELETE_FAILED":          9,
 		"ERROR_TYPE_PRESIGNED_URL_FAILED":   10,
 		"ERROR_TYPE_INTERNAL_ERROR":         11,
+		"ERROR_TYPE_VALIDATION_FAILED":      12,
+		"ERROR_TYPE_INFECTED":               13,
+		"ERROR_TYPE_CHECKSUM_MISMATCH":      14,
+		"ERROR_TYPE_INVALID_REQUEST":        15,
 	}
 )
 
@@ -98,7 +112,62 @@ func (ErrorType) EnumDescriptor() ([]byte, []int) {
 	return file_draft_v1_draft_proto_rawDescGZIP(), []int{0}
 }
 
-// Common result structure
+// ChecksumAlgorithm selects the digest used to verify uploaded content
+type ChecksumAlgorithm int32
+
+const (
+	ChecksumAlgorithm_CHECKSUM_ALGORITHM_UNSPECIFIED ChecksumAlgorithm = 0
+	ChecksumAlgorithm_CHECKSUM_ALGORITHM_SHA256      ChecksumAlgorithm = 1
+	ChecksumAlgorithm_CHECKSUM_ALGORITHM_CRC32C      ChecksumAlgorithm = 2
+	ChecksumAlgorithm_CHECKSUM_ALGORITHM_MD5         ChecksumAlgorithm = 3
+)
+
+// Enum value maps for ChecksumAlgorithm.
+var (
+	ChecksumAlgorithm_name = map[int32]string{
+		0: "CHECKSUM_ALGORITHM_UNSPECIFIED",
+		1: "CHECKSUM_ALGORITHM_SHA256",
+		2: "CHECKSUM_ALGORITHM_CRC32C",
+		3: "CHECKSUM_ALGORITHM_MD5",
+	}
+	ChecksumAlgorithm_value = map[string]int32{
+		"CHECKSUM_ALGORITHM_UNSPECIFIED": 0,
+		"CHECKSUM_ALGORITHM_SHA256":      1,
+		"CHECKSUM_ALGORITHM_CRC32C":      2,
+		"CHECKSUM_ALGORITHM_MD5":         3,
+	}
+)
+
+func (x ChecksumAlgorithm) Enum() *ChecksumAlgorithm {
+	p := new(ChecksumAlgorithm)
+	*p = x
+	return p
+}
+
+func (x ChecksumAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChecksumAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_draft_v1_draft_proto_enumTypes[1].Descriptor()
+}
+
+func (ChecksumAlgorithm) Type() protoreflect.EnumType {
+	return &file_draft_v1_draft_proto_enumTypes[1]
+}
+
+func (x ChecksumAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChecksumAlgorithm.Descriptor instead.
+func (ChecksumAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{1}
+}
+
+// Common result structure. gRPC calls report failures as status errors with
+// a google.rpc.ErrorInfo detail whose reason is the ErrorType name; the
+// result only carries them when the server runs with legacy results.
 type Result struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -242,8 +311,14 @@ func (x *CreateDraftBucketResponse) GetResult() *Result {
 
 // GetUploadURL messages
 type GetUploadURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectName    string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Optional base64 encoded digest of the file the client is going to upload
+	ChecksumAlgorithm ChecksumAlgorithm `protobuf:"varint,2,opt,name=checksum_algorithm,json=checksumAlgorithm,proto3,enum=draft.v1.ChecksumAlgorithm" json:"checksum_algorithm,omitempty"`
+	Checksum          string            `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Optional file size in bytes; uploads of any other size are rejected.
+	// Required when the caller's quota limits draft bytes
+	Size          int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -285,10 +360,35 @@ func (x *GetUploadURLRequest) GetObjectName() string {
 	return ""
 }
 
+func (x *GetUploadURLRequest) GetChecksumAlgorithm() ChecksumAlgorithm {
+	if x != nil {
+		return x.ChecksumAlgorithm
+	}
+	return ChecksumAlgorithm_CHECKSUM_ALGORITHM_UNSPECIFIED
+}
+
+func (x *GetUploadURLRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *GetUploadURLRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
 type GetUploadURLResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Url    string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Headers the client must send unchanged with the upload request
+	RequiredHeaders map[string]string `protobuf:"bytes,3,rep,name=required_headers,json=requiredHeaders,proto3" json:"required_headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Draft key the URL uploads to; differs from the request when a hook rewrote it
+	ObjectName    string `protobuf:"bytes,4,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -337,10 +437,33 @@ func (x *GetUploadURLResponse) GetUrl() string {
 	return ""
 }
 
+func (x *GetUploadURLResponse) GetRequiredHeaders() map[string]string {
+	if x != nil {
+		return x.RequiredHeaders
+	}
+	return nil
+}
+
+func (x *GetUploadURLResponse) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
 // GetDownloadURL messages
 type GetDownloadURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ObjectName    string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Optional overrides for the headers returned when the URL is fetched
+	ResponseContentDisposition string `protobuf:"bytes,2,opt,name=response_content_disposition,json=responseContentDisposition,proto3" json:"response_content_disposition,omitempty"`
+	ResponseContentType        string `protobuf:"bytes,3,opt,name=response_content_type,json=responseContentType,proto3" json:"response_content_type,omitempty"`
+	ResponseCacheControl       string `protobuf:"bytes,4,opt,name=response_cache_control,json=responseCacheControl,proto3" json:"response_cache_control,omitempty"`
+	// Optional URL lifetime in seconds, capped by the server configuration
+	// and at most 7 days
+	TtlSeconds int64 `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// Optional derivative to download instead of the original, e.g. "w256"
+	Variant       string `protobuf:"bytes,6,opt,name=variant,proto3" json:"variant,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -382,6 +505,41 @@ func (x *GetDownloadURLRequest) GetObjectName() string {
 	return ""
 }
 
+func (x *GetDownloadURLRequest) GetResponseContentDisposition() string {
+	if x != nil {
+		return x.ResponseContentDisposition
+	}
+	return ""
+}
+
+func (x *GetDownloadURLRequest) GetResponseContentType() string {
+	if x != nil {
+		return x.ResponseContentType
+	}
+	return ""
+}
+
+func (x *GetDownloadURLRequest) GetResponseCacheControl() string {
+	if x != nil {
+		return x.ResponseCacheControl
+	}
+	return ""
+}
+
+func (x *GetDownloadURLRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *GetDownloadURLRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
 type GetDownloadURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
@@ -434,17 +592,117 @@ func (x *GetDownloadURLResponse) GetUrl() string {
 	return ""
 }
 
-// ConfirmUpload messages
-type ConfirmUploadRequest struct {
+// GetDraftDownloadURL messages
+type GetDraftDownloadURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ObjectName    string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *GetDraftDownloadURLRequest) Reset() {
+	*x = GetDraftDownloadURLRequest{}
+	mi := &file_draft_v1_draft_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDraftDownloadURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDraftDownloadURLRequest) ProtoMessage() {}
+
+func (x *GetDraftDownloadURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDraftDownloadURLRequest.ProtoReflect.Descriptor instead.
+func (*GetDraftDownloadURLRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetDraftDownloadURLRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+type GetDraftDownloadURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDraftDownloadURLResponse) Reset() {
+	*x = GetDraftDownloadURLResponse{}
+	mi := &file_draft_v1_draft_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDraftDownloadURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDraftDownloadURLResponse) ProtoMessage() {}
+
+func (x *GetDraftDownloadURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDraftDownloadURLResponse.ProtoReflect.Descriptor instead.
+func (*GetDraftDownloadURLResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDraftDownloadURLResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *GetDraftDownloadURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// ConfirmUpload messages
+type ConfirmUploadRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Optional base64 encoded digest that must match the stored draft
+	ChecksumAlgorithm ChecksumAlgorithm `protobuf:"varint,2,opt,name=checksum_algorithm,json=checksumAlgorithm,proto3,enum=draft.v1.ChecksumAlgorithm" json:"checksum_algorithm,omitempty"`
+	Checksum          string            `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
 func (x *ConfirmUploadRequest) Reset() {
 	*x = ConfirmUploadRequest{}
-	mi := &file_draft_v1_draft_proto_msgTypes[7]
+	mi := &file_draft_v1_draft_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -456,7 +714,7 @@ func (x *ConfirmUploadRequest) String() string {
 func (*ConfirmUploadRequest) ProtoMessage() {}
 
 func (x *ConfirmUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_draft_v1_draft_proto_msgTypes[7]
+	mi := &file_draft_v1_draft_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,7 +727,7 @@ func (x *ConfirmUploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfirmUploadRequest.ProtoReflect.Descriptor instead.
 func (*ConfirmUploadRequest) Descriptor() ([]byte, []int) {
-	return file_draft_v1_draft_proto_rawDescGZIP(), []int{7}
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ConfirmUploadRequest) GetObjectName() string {
@@ -479,16 +737,32 @@ func (x *ConfirmUploadRequest) GetObjectName() string {
 	return ""
 }
 
+func (x *ConfirmUploadRequest) GetChecksumAlgorithm() ChecksumAlgorithm {
+	if x != nil {
+		return x.ChecksumAlgorithm
+	}
+	return ChecksumAlgorithm_CHECKSUM_ALGORITHM_UNSPECIFIED
+}
+
+func (x *ConfirmUploadRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
 type ConfirmUploadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Key the object was stored under; differs from the request when a hook rewrote it
+	ObjectName    string `protobuf:"bytes,2,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ConfirmUploadResponse) Reset() {
 	*x = ConfirmUploadResponse{}
-	mi := &file_draft_v1_draft_proto_msgTypes[8]
+	mi := &file_draft_v1_draft_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -500,7 +774,7 @@ func (x *ConfirmUploadResponse) String() string {
 func (*ConfirmUploadResponse) ProtoMessage() {}
 
 func (x *ConfirmUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_draft_v1_draft_proto_msgTypes[8]
+	mi := &file_draft_v1_draft_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -513,7 +787,7 @@ func (x *ConfirmUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfirmUploadResponse.ProtoReflect.Descriptor instead.
 func (*ConfirmUploadResponse) Descriptor() ([]byte, []int) {
-	return file_draft_v1_draft_proto_rawDescGZIP(), []int{8}
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ConfirmUploadResponse) GetResult() *Result {
@@ -523,11 +797,522 @@ func (x *ConfirmUploadResponse) GetResult() *Result {
 	return nil
 }
 
+func (x *ConfirmUploadResponse) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+// CancelUpload messages
+type CancelUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName    string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelUploadRequest) Reset() {
+	*x = CancelUploadRequest{}
+	mi := &file_draft_v1_draft_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUploadRequest) ProtoMessage() {}
+
+func (x *CancelUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUploadRequest.ProtoReflect.Descriptor instead.
+func (*CancelUploadRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CancelUploadRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+type CancelUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelUploadResponse) Reset() {
+	*x = CancelUploadResponse{}
+	mi := &file_draft_v1_draft_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUploadResponse) ProtoMessage() {}
+
+func (x *CancelUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUploadResponse.ProtoReflect.Descriptor instead.
+func (*CancelUploadResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CancelUploadResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// UploadDraft messages
+type UploadDraftRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*UploadDraftRequest_Metadata
+	//	*UploadDraftRequest_Chunk
+	Payload       isUploadDraftRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadDraftRequest) Reset() {
+	*x = UploadDraftRequest{}
+	mi := &file_draft_v1_draft_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDraftRequest) ProtoMessage() {}
+
+func (x *UploadDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDraftRequest.ProtoReflect.Descriptor instead.
+func (*UploadDraftRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UploadDraftRequest) GetPayload() isUploadDraftRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UploadDraftRequest) GetMetadata() *UploadDraftMetadata {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadDraftRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *UploadDraftRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadDraftRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadDraftRequest_Payload interface {
+	isUploadDraftRequest_Payload()
+}
+
+type UploadDraftRequest_Metadata struct {
+	Metadata *UploadDraftMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadDraftRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadDraftRequest_Metadata) isUploadDraftRequest_Payload() {}
+
+func (*UploadDraftRequest_Chunk) isUploadDraftRequest_Payload() {}
+
+type UploadDraftMetadata struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName  string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	ContentType string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// Optional base64 encoded digest the streamed content must match
+	ChecksumAlgorithm ChecksumAlgorithm `protobuf:"varint,3,opt,name=checksum_algorithm,json=checksumAlgorithm,proto3,enum=draft.v1.ChecksumAlgorithm" json:"checksum_algorithm,omitempty"`
+	Checksum          string            `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// File size in bytes; the stream must carry exactly that many
+	Size          int64 `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadDraftMetadata) Reset() {
+	*x = UploadDraftMetadata{}
+	mi := &file_draft_v1_draft_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadDraftMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDraftMetadata) ProtoMessage() {}
+
+func (x *UploadDraftMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDraftMetadata.ProtoReflect.Descriptor instead.
+func (*UploadDraftMetadata) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UploadDraftMetadata) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UploadDraftMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadDraftMetadata) GetChecksumAlgorithm() ChecksumAlgorithm {
+	if x != nil {
+		return x.ChecksumAlgorithm
+	}
+	return ChecksumAlgorithm_CHECKSUM_ALGORITHM_UNSPECIFIED
+}
+
+func (x *UploadDraftMetadata) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *UploadDraftMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type UploadDraftResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// Draft key the content was stored under; differs from the request when a hook rewrote it
+	ObjectName    string `protobuf:"bytes,2,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Size          int64  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadDraftResponse) Reset() {
+	*x = UploadDraftResponse{}
+	mi := &file_draft_v1_draft_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDraftResponse) ProtoMessage() {}
+
+func (x *UploadDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDraftResponse.ProtoReflect.Descriptor instead.
+func (*UploadDraftResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UploadDraftResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *UploadDraftResponse) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *UploadDraftResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// DownloadObject messages
+type DownloadObjectRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// Optional derivative to download instead of the original, e.g. "w256"
+	Variant string `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`
+	// Optional byte range; a zero length reads to the end of the object
+	Offset        int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int64 `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadObjectRequest) Reset() {
+	*x = DownloadObjectRequest{}
+	mi := &file_draft_v1_draft_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadObjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadObjectRequest) ProtoMessage() {}
+
+func (x *DownloadObjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadObjectRequest.ProtoReflect.Descriptor instead.
+func (*DownloadObjectRequest) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DownloadObjectRequest) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *DownloadObjectRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *DownloadObjectRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadObjectRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type DownloadObjectResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set on the first message only
+	Metadata      *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Chunk         []byte          `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadObjectResponse) Reset() {
+	*x = DownloadObjectResponse{}
+	mi := &file_draft_v1_draft_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadObjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadObjectResponse) ProtoMessage() {}
+
+func (x *DownloadObjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadObjectResponse.ProtoReflect.Descriptor instead.
+func (*DownloadObjectResponse) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DownloadObjectResponse) GetMetadata() *ObjectMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *DownloadObjectResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type ObjectMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Size of the whole object, not of the requested range
+	Size             int64  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	ContentType      string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Etag             string `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	LastModifiedUnix int64  `protobuf:"varint,4,opt,name=last_modified_unix,json=lastModifiedUnix,proto3" json:"last_modified_unix,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ObjectMetadata) Reset() {
+	*x = ObjectMetadata{}
+	mi := &file_draft_v1_draft_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectMetadata) ProtoMessage() {}
+
+func (x *ObjectMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_draft_v1_draft_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectMetadata.ProtoReflect.Descriptor instead.
+func (*ObjectMetadata) Descriptor() ([]byte, []int) {
+	return file_draft_v1_draft_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ObjectMetadata) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ObjectMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ObjectMetadata) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *ObjectMetadata) GetLastModifiedUnix() int64 {
+	if x != nil {
+		return x.LastModifiedUnix
+	}
+	return 0
+}
+
 var File_draft_v1_draft_proto protoreflect.FileDescriptor
 
 const file_draft_v1_draft_proto_rawDesc = "" +
 	"\n" +
-	"\x14draft/v1/draft.proto\x12\bdraft.v1\"{\n" +
+	"\x14draft/v1/draft.proto\x12\bdraft.v1\x1a\x1bbuf/validate/validate.proto\x1a\x14draft/v1/rules.proto\x1a\x1cgoogle/api/annotations.proto\"{\n" +
 	"\x06Result\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
 	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x122\n" +
@@ -535,24 +1320,85 @@ const file_draft_v1_draft_proto_rawDesc = "" +
 	"error_type\x18\x03 \x01(\x0e2\x13.draft.v1.ErrorTypeR\terrorType\"\x1a\n" +
 	"\x18CreateDraftBucketRequest\"E\n" +
 	"\x19CreateDraftBucketResponse\x12(\n" +
-	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\"6\n" +
-	"\x13GetUploadURLRequest\x12\x1f\n" +
-	"\vobject_name\x18\x01 \x01(\tR\n" +
-	"objectName\"R\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\"\xbc\x01\n" +
+	"\x13GetUploadURLRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\x12J\n" +
+	"\x12checksum_algorithm\x18\x02 \x01(\x0e2\x1b.draft.v1.ChecksumAlgorithmR\x11checksumAlgorithm\x12\x1a\n" +
+	"\bchecksum\x18\x03 \x01(\tR\bchecksum\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\"\x97\x02\n" +
 	"\x14GetUploadURLResponse\x12(\n" +
 	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url\"8\n" +
-	"\x15GetDownloadURLRequest\x12\x1f\n" +
-	"\vobject_name\x18\x01 \x01(\tR\n" +
-	"objectName\"T\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12^\n" +
+	"\x10required_headers\x18\x03 \x03(\v23.draft.v1.GetUploadURLResponse.RequiredHeadersEntryR\x0frequiredHeaders\x12\x1f\n" +
+	"\vobject_name\x18\x04 \x01(\tR\n" +
+	"objectName\x1aB\n" +
+	"\x14RequiredHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd1\x02\n" +
+	"\x15GetDownloadURLRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\x12@\n" +
+	"\x1cresponse_content_disposition\x18\x02 \x01(\tR\x1aresponseContentDisposition\x122\n" +
+	"\x15response_content_type\x18\x03 \x01(\tR\x13responseContentType\x124\n" +
+	"\x16response_cache_control\x18\x04 \x01(\tR\x14responseCacheControl\x12,\n" +
+	"\vttl_seconds\x18\x05 \x01(\x03B\v\xbaH\b\"\x06\x18\x80\xf5$(\x00R\n" +
+	"ttlSeconds\x123\n" +
+	"\avariant\x18\x06 \x01(\tB\x19\xbaH\x16r\x14\x18@2\x10^[A-Za-z0-9_-]*$R\avariant\"T\n" +
 	"\x16GetDownloadURLResponse\x12(\n" +
 	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\x10\n" +
-	"\x03url\x18\x02 \x01(\tR\x03url\"7\n" +
-	"\x14ConfirmUploadRequest\x12\x1f\n" +
-	"\vobject_name\x18\x01 \x01(\tR\n" +
-	"objectName\"A\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"G\n" +
+	"\x1aGetDraftDownloadURLRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\"Y\n" +
+	"\x1bGetDraftDownloadURLResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"\xa9\x01\n" +
+	"\x14ConfirmUploadRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\x12J\n" +
+	"\x12checksum_algorithm\x18\x02 \x01(\x0e2\x1b.draft.v1.ChecksumAlgorithmR\x11checksumAlgorithm\x12\x1a\n" +
+	"\bchecksum\x18\x03 \x01(\tR\bchecksum\"b\n" +
 	"\x15ConfirmUploadResponse\x12(\n" +
-	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result*\x94\x03\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\x1f\n" +
+	"\vobject_name\x18\x02 \x01(\tR\n" +
+	"objectName\"@\n" +
+	"\x13CancelUploadRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\"@\n" +
+	"\x14CancelUploadResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\"\x87\x01\n" +
+	"\x12UploadDraftRequest\x12;\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x1d.draft.v1.UploadDraftMetadataH\x00R\bmetadata\x12\"\n" +
+	"\x05chunk\x18\x02 \x01(\fB\n" +
+	"\xbaH\az\x05\x18\x80\x80\x80\x02H\x00R\x05chunkB\x10\n" +
+	"\apayload\x12\x05\xbaH\x02\b\x01\"\xf2\x01\n" +
+	"\x13UploadDraftMetadata\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\x12+\n" +
+	"\fcontent_type\x18\x02 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\vcontentType\x12J\n" +
+	"\x12checksum_algorithm\x18\x03 \x01(\x0e2\x1b.draft.v1.ChecksumAlgorithmR\x11checksumAlgorithm\x12\x1a\n" +
+	"\bchecksum\x18\x04 \x01(\tR\bchecksum\x12\x1b\n" +
+	"\x04size\x18\x05 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x04size\"t\n" +
+	"\x13UploadDraftResponse\x12(\n" +
+	"\x06result\x18\x01 \x01(\v2\x10.draft.v1.ResultR\x06result\x12\x1f\n" +
+	"\vobject_name\x18\x02 \x01(\tR\n" +
+	"objectName\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\"\xb9\x01\n" +
+	"\x15DownloadObjectRequest\x12)\n" +
+	"\vobject_name\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xc0>\x01R\n" +
+	"objectName\x123\n" +
+	"\avariant\x18\x02 \x01(\tB\x19\xbaH\x16r\x14\x18@2\x10^[A-Za-z0-9_-]*$R\avariant\x12\x1f\n" +
+	"\x06offset\x18\x03 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x06offset\x12\x1f\n" +
+	"\x06length\x18\x04 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\x06length\"d\n" +
+	"\x16DownloadObjectResponse\x124\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x18.draft.v1.ObjectMetadataR\bmetadata\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"\x89\x01\n" +
+	"\x0eObjectMetadata\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04etag\x18\x03 \x01(\tR\x04etag\x12,\n" +
+	"\x12last_modified_unix\x18\x04 \x01(\x03R\x10lastModifiedUnix*\x91\x04\n" +
 	"\tErrorType\x12\x1a\n" +
 	"\x16ERROR_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
 	"\x1bERROR_TYPE_BUCKET_NOT_FOUND\x10\x01\x12\x1f\n" +
@@ -566,12 +1412,25 @@ const file_draft_v1_draft_proto_rawDesc = "" +
 	"\x18ERROR_TYPE_DELETE_FAILED\x10\t\x12#\n" +
 	"\x1fERROR_TYPE_PRESIGNED_URL_FAILED\x10\n" +
 	"\x12\x1d\n" +
-	"\x19ERROR_TYPE_INTERNAL_ERROR\x10\v2\xe2\x02\n" +
-	"\fDraftService\x12\\\n" +
-	"\x11CreateDraftBucket\x12\".draft.v1.CreateDraftBucketRequest\x1a#.draft.v1.CreateDraftBucketResponse\x12M\n" +
-	"\fGetUploadURL\x12\x1d.draft.v1.GetUploadURLRequest\x1a\x1e.draft.v1.GetUploadURLResponse\x12S\n" +
-	"\x0eGetDownloadURL\x12\x1f.draft.v1.GetDownloadURLRequest\x1a .draft.v1.GetDownloadURLResponse\x12P\n" +
-	"\rConfirmUpload\x12\x1e.draft.v1.ConfirmUploadRequest\x1a\x1f.draft.v1.ConfirmUploadResponseB\x91\x01\n" +
+	"\x19ERROR_TYPE_INTERNAL_ERROR\x10\v\x12 \n" +
+	"\x1cERROR_TYPE_VALIDATION_FAILED\x10\f\x12\x17\n" +
+	"\x13ERROR_TYPE_INFECTED\x10\r\x12 \n" +
+	"\x1cERROR_TYPE_CHECKSUM_MISMATCH\x10\x0e\x12\x1e\n" +
+	"\x1aERROR_TYPE_INVALID_REQUEST\x10\x0f*\x91\x01\n" +
+	"\x11ChecksumAlgorithm\x12\"\n" +
+	"\x1eCHECKSUM_ALGORITHM_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19CHECKSUM_ALGORITHM_SHA256\x10\x01\x12\x1d\n" +
+	"\x19CHECKSUM_ALGORITHM_CRC32C\x10\x02\x12\x1a\n" +
+	"\x16CHECKSUM_ALGORITHM_MD5\x10\x032\x98\a\n" +
+	"\fDraftService\x12}\n" +
+	"\x11CreateDraftBucket\x12\".draft.v1.CreateDraftBucketRequest\x1a#.draft.v1.CreateDraftBucketResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/draft/bucket\x12r\n" +
+	"\fGetUploadURL\x12\x1d.draft.v1.GetUploadURLRequest\x1a\x1e.draft.v1.GetUploadURLResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/api/v1/draft/upload-url\x12z\n" +
+	"\x0eGetDownloadURL\x12\x1f.draft.v1.GetDownloadURLRequest\x1a .draft.v1.GetDownloadURLResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/api/v1/draft/download-url\x12\x8f\x01\n" +
+	"\x13GetDraftDownloadURL\x12$.draft.v1.GetDraftDownloadURLRequest\x1a%.draft.v1.GetDraftDownloadURLResponse\"+\x82\xd3\xe4\x93\x02%:\x01*\" /api/v1/draft/draft-download-url\x12r\n" +
+	"\rConfirmUpload\x12\x1e.draft.v1.ConfirmUploadRequest\x1a\x1f.draft.v1.ConfirmUploadResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/v1/draft/confirm\x12n\n" +
+	"\fCancelUpload\x12\x1d.draft.v1.CancelUploadRequest\x1a\x1e.draft.v1.CancelUploadResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/v1/draft/cancel\x12L\n" +
+	"\vUploadDraft\x12\x1c.draft.v1.UploadDraftRequest\x1a\x1d.draft.v1.UploadDraftResponse(\x01\x12U\n" +
+	"\x0eDownloadObject\x12\x1f.draft.v1.DownloadObjectRequest\x1a .draft.v1.DownloadObjectResponse0\x01B\x91\x01\n" +
 	"\fcom.draft.v1B\n" +
 	"DraftProtoP\x01Z4github.com/snowmerak/DraftStore/gen/draft/v1;draftv1\xa2\x02\x03DXX\xaa\x02\bDraft.V1\xca\x02\bDraft\\V1\xe2\x02\x14Draft\\V1\\GPBMetadata\xea\x02\tDraft::V1b\x06proto3"
 
@@ -587,39 +1446,68 @@ func file_draft_v1_draft_proto_rawDescGZIP() []byte {
 	return file_draft_v1_draft_proto_rawDescData
 }
 
-var file_draft_v1_draft_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_draft_v1_draft_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_draft_v1_draft_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_draft_v1_draft_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_draft_v1_draft_proto_goTypes = []any{
-	(ErrorType)(0),                    // 0: draft.v1.ErrorType
-	(*Result)(nil),                    // 1: draft.v1.Result
-	(*CreateDraftBucketRequest)(nil),  // 2: draft.v1.CreateDraftBucketRequest
-	(*CreateDraftBucketResponse)(nil), // 3: draft.v1.CreateDraftBucketResponse
-	(*GetUploadURLRequest)(nil),       // 4: draft.v1.GetUploadURLRequest
-	(*GetUploadURLResponse)(nil),      // 5: draft.v1.GetUploadURLResponse
-	(*GetDownloadURLRequest)(nil),     // 6: draft.v1.GetDownloadURLRequest
-	(*GetDownloadURLResponse)(nil),    // 7: draft.v1.GetDownloadURLResponse
-	(*ConfirmUploadRequest)(nil),      // 8: draft.v1.ConfirmUploadRequest
-	(*ConfirmUploadResponse)(nil),     // 9: draft.v1.ConfirmUploadResponse
+	(ErrorType)(0),                      // 0: draft.v1.ErrorType
+	(ChecksumAlgorithm)(0),              // 1: draft.v1.ChecksumAlgorithm
+	(*Result)(nil),                      // 2: draft.v1.Result
+	(*CreateDraftBucketRequest)(nil),    // 3: draft.v1.CreateDraftBucketRequest
+	(*CreateDraftBucketResponse)(nil),   // 4: draft.v1.CreateDraftBucketResponse
+	(*GetUploadURLRequest)(nil),         // 5: draft.v1.GetUploadURLRequest
+	(*GetUploadURLResponse)(nil),        // 6: draft.v1.GetUploadURLResponse
+	(*GetDownloadURLRequest)(nil),       // 7: draft.v1.GetDownloadURLRequest
+	(*GetDownloadURLResponse)(nil),      // 8: draft.v1.GetDownloadURLResponse
+	(*GetDraftDownloadURLRequest)(nil),  // 9: draft.v1.GetDraftDownloadURLRequest
+	(*GetDraftDownloadURLResponse)(nil), // 10: draft.v1.GetDraftDownloadURLResponse
+	(*ConfirmUploadRequest)(nil),        // 11: draft.v1.ConfirmUploadRequest
+	(*ConfirmUploadResponse)(nil),       // 12: draft.v1.ConfirmUploadResponse
+	(*CancelUploadRequest)(nil),         // 13: draft.v1.CancelUploadRequest
+	(*CancelUploadResponse)(nil),        // 14: draft.v1.CancelUploadResponse
+	(*UploadDraftRequest)(nil),          // 15: draft.v1.UploadDraftRequest
+	(*UploadDraftMetadata)(nil),         // 16: draft.v1.UploadDraftMetadata
+	(*UploadDraftResponse)(nil),         // 17: draft.v1.UploadDraftResponse
+	(*DownloadObjectRequest)(nil),       // 18: draft.v1.DownloadObjectRequest
+	(*DownloadObjectResponse)(nil),      // 19: draft.v1.DownloadObjectResponse
+	(*ObjectMetadata)(nil),              // 20: draft.v1.ObjectMetadata
+	nil,                                 // 21: draft.v1.GetUploadURLResponse.RequiredHeadersEntry
 }
 var file_draft_v1_draft_proto_depIdxs = []int32{
-	0, // 0: draft.v1.Result.error_type:type_name -> draft.v1.ErrorType
-	1, // 1: draft.v1.CreateDraftBucketResponse.result:type_name -> draft.v1.Result
-	1, // 2: draft.v1.GetUploadURLResponse.result:type_name -> draft.v1.Result
-	1, // 3: draft.v1.GetDownloadURLResponse.result:type_name -> draft.v1.Result
-	1, // 4: draft.v1.ConfirmUploadResponse.result:type_name -> draft.v1.Result
-	2, // 5: draft.v1.DraftService.CreateDraftBucket:input_type -> draft.v1.CreateDraftBucketRequest
-	4, // 6: draft.v1.DraftService.GetUploadURL:input_type -> draft.v1.GetUploadURLRequest
-	6, // 7: draft.v1.DraftService.GetDownloadURL:input_type -> draft.v1.GetDownloadURLRequest
-	8, // 8: draft.v1.DraftService.ConfirmUpload:input_type -> draft.v1.ConfirmUploadRequest
-	3, // 9: draft.v1.DraftService.CreateDraftBucket:output_type -> draft.v1.CreateDraftBucketResponse
-	5, // 10: draft.v1.DraftService.GetUploadURL:output_type -> draft.v1.GetUploadURLResponse
-	7, // 11: draft.v1.DraftService.GetDownloadURL:output_type -> draft.v1.GetDownloadURLResponse
-	9, // 12: draft.v1.DraftService.ConfirmUpload:output_type -> draft.v1.ConfirmUploadResponse
-	9, // [9:13] is the sub-list for method output_type
-	5, // [5:9] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0,  // 0: draft.v1.Result.error_type:type_name -> draft.v1.ErrorType
+	2,  // 1: draft.v1.CreateDraftBucketResponse.result:type_name -> draft.v1.Result
+	1,  // 2: draft.v1.GetUploadURLRequest.checksum_algorithm:type_name -> draft.v1.ChecksumAlgorithm
+	2,  // 3: draft.v1.GetUploadURLResponse.result:type_name -> draft.v1.Result
+	21, // 4: draft.v1.GetUploadURLResponse.required_headers:type_name -> draft.v1.GetUploadURLResponse.RequiredHeadersEntry
+	2,  // 5: draft.v1.GetDownloadURLResponse.result:type_name -> draft.v1.Result
+	2,  // 6: draft.v1.GetDraftDownloadURLResponse.result:type_name -> draft.v1.Result
+	1,  // 7: draft.v1.ConfirmUploadRequest.checksum_algorithm:type_name -> draft.v1.ChecksumAlgorithm
+	2,  // 8: draft.v1.ConfirmUploadResponse.result:type_name -> draft.v1.Result
+	2,  // 9: draft.v1.CancelUploadResponse.result:type_name -> draft.v1.Result
+	16, // 10: draft.v1.UploadDraftRequest.metadata:type_name -> draft.v1.UploadDraftMetadata
+	1,  // 11: draft.v1.UploadDraftMetadata.checksum_algorithm:type_name -> draft.v1.ChecksumAlgorithm
+	2,  // 12: draft.v1.UploadDraftResponse.result:type_name -> draft.v1.Result
+	20, // 13: draft.v1.DownloadObjectResponse.metadata:type_name -> draft.v1.ObjectMetadata
+	3,  // 14: draft.v1.DraftService.CreateDraftBucket:input_type -> draft.v1.CreateDraftBucketRequest
+	5,  // 15: draft.v1.DraftService.GetUploadURL:input_type -> draft.v1.GetUploadURLRequest
+	7,  // 16: draft.v1.DraftService.GetDownloadURL:input_type -> draft.v1.GetDownloadURLRequest
+	9,  // 17: draft.v1.DraftService.GetDraftDownloadURL:input_type -> draft.v1.GetDraftDownloadURLRequest
+	11, // 18: draft.v1.DraftService.ConfirmUpload:input_type -> draft.v1.ConfirmUploadRequest
+	13, // 19: draft.v1.DraftService.CancelUpload:input_type -> draft.v1.CancelUploadRequest
+	15, // 20: draft.v1.DraftService.UploadDraft:input_type -> draft.v1.UploadDraftRequest
+	18, // 21: draft.v1.DraftService.DownloadObject:input_type -> draft.v1.DownloadObjectRequest
+	4,  // 22: draft.v1.DraftService.CreateDraftBucket:output_type -> draft.v1.CreateDraftBucketResponse
+	6,  // 23: draft.v1.DraftService.GetUploadURL:output_type -> draft.v1.GetUploadURLResponse
+	8,  // 24: draft.v1.DraftService.GetDownloadURL:output_type -> draft.v1.GetDownloadURLResponse
+	10, // 25: draft.v1.DraftService.GetDraftDownloadURL:output_type -> draft.v1.GetDraftDownloadURLResponse
+	12, // 26: draft.v1.DraftService.ConfirmUpload:output_type -> draft.v1.ConfirmUploadResponse
+	14, // 27: draft.v1.DraftService.CancelUpload:output_type -> draft.v1.CancelUploadResponse
+	17, // 28: draft.v1.DraftService.UploadDraft:output_type -> draft.v1.UploadDraftResponse
+	19, // 29: draft.v1.DraftService.DownloadObject:output_type -> draft.v1.DownloadObjectResponse
+	22, // [22:30] is the sub-list for method output_type
+	14, // [14:22] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_draft_v1_draft_proto_init() }
@@ -627,13 +1515,18 @@ func file_draft_v1_draft_proto_init() {
 	if File_draft_v1_draft_proto != nil {
 		return
 	}
+	file_draft_v1_rules_proto_init()
+	file_draft_v1_draft_proto_msgTypes[13].OneofWrappers = []any{
+		(*UploadDraftRequest_Metadata)(nil),
+		(*UploadDraftRequest_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_draft_v1_draft_proto_rawDesc), len(file_draft_v1_draft_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      2,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion8
 
 const (
-	DraftService_CreateDraftBucket_FullMethodName = "/draft.v1.DraftService/CreateDraftBucket"
-	DraftService_GetUploadURL_FullMethodName      = "/draft.v1.DraftService/GetUploadURL"
-	DraftService_GetDownloadURL_FullMethodName    = "/draft.v1.DraftService/GetDownloadURL"
-	DraftService_ConfirmUpload_FullMethodName     = "/draft.v1.DraftService/ConfirmUpload"
+	DraftService_CreateDraftBucket_FullMethodName   = "/draft.v1.DraftService/CreateDraftBucket"
+	DraftService_GetUploadURL_FullMethodName        = "/draft.v1.DraftService/GetUploadURL"
+	DraftService_GetDownloadURL_FullMethodName      = "/draft.v1.DraftService/GetDownloadURL"
+	DraftService_GetDraftDownloadURL_FullMethodName = "/draft.v1.DraftService/GetDraftDownloadURL"
+	DraftService_ConfirmUpload_FullMethodName       = "/draft.v1.DraftService/ConfirmUpload"
+	DraftService_CancelUpload_FullMethodName        = "/draft.v1.DraftService/CancelUpload"
+	DraftService_UploadDraft_FullMethodName         = "/draft.v1.DraftService/UploadDraft"
+	DraftService_DownloadObject_FullMethodName      = "/draft.v1.DraftService/DownloadObject"
 )
 
 // DraftServiceClient is the client API for DraftService service.
@@ -37,8 +41,18 @@ type DraftServiceClient interface {
 	GetUploadURL(ctx context.Context, in *GetUploadURLRequest, opts ...grpc.CallOption) (*GetUploadURLResponse, error)
 	// GetDownloadURL generates a presigned URL for downloading files from the main bucket
 	GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*GetDownloadURLResponse, error)
+	// GetDraftDownloadURL generates a short-lived presigned URL for previewing an unconfirmed file in the draft bucket
+	GetDraftDownloadURL(ctx context.Context, in *GetDraftDownloadURLRequest, opts ...grpc.CallOption) (*GetDraftDownloadURLResponse, error)
 	// ConfirmUpload moves a file from draft bucket to main bucket
 	ConfirmUpload(ctx context.Context, in *ConfirmUploadRequest, opts ...grpc.CallOption) (*ConfirmUploadResponse, error)
+	// CancelUpload deletes an unconfirmed file and releases its quota reservation
+	CancelUpload(ctx context.Context, in *CancelUploadRequest, opts ...grpc.CallOption) (*CancelUploadResponse, error)
+	// UploadDraft streams a file through the server into the draft bucket, for
+	// clients that cannot reach presigned URLs. The first message carries the
+	// metadata and the following ones the content
+	UploadDraft(ctx context.Context, opts ...grpc.CallOption) (DraftService_UploadDraftClient, error)
+	// DownloadObject streams a file, or a byte range of it, from the main bucket
+	DownloadObject(ctx context.Context, in *DownloadObjectRequest, opts ...grpc.CallOption) (DraftService_DownloadObjectClient, error)
 }
 
 type draftServiceClient struct {
@@ -79,6 +93,16 @@ func (c *draftServiceClient) GetDownloadURL(ctx context.Context, in *GetDownload
 	return out, nil
 }
 
+func (c *draftServiceClient) GetDraftDownloadURL(ctx context.Context, in *GetDraftDownloadURLRequest, opts ...grpc.CallOption) (*GetDraftDownloadURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDraftDownloadURLResponse)
+	err := c.cc.Invoke(ctx, DraftService_GetDraftDownloadURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *draftServiceClient) ConfirmUpload(ctx context.Context, in *ConfirmUploadRequest, opts ...grpc.CallOption) (*ConfirmUploadResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ConfirmUploadResponse)
@@ -89,6 +113,84 @@ func (c *draftServiceClient) ConfirmUpload(ctx context.Context, in *ConfirmUploa
 	return out, nil
 }
 
+func (c *draftServiceClient) CancelUpload(ctx context.Context, in *CancelUploadRequest, opts ...grpc.CallOption) (*CancelUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelUploadResponse)
+	err := c.cc.Invoke(ctx, DraftService_CancelUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *draftServiceClient) UploadDraft(ctx context.Context, opts ...grpc.CallOption) (DraftService_UploadDraftClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DraftService_ServiceDesc.Streams[0], DraftService_UploadDraft_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &draftServiceUploadDraftClient{ClientStream: stream}
+	return x, nil
+}
+
+type DraftService_UploadDraftClient interface {
+	Send(*UploadDraftRequest) error
+	CloseAndRecv() (*UploadDraftResponse, error)
+	grpc.ClientStream
+}
+
+type draftServiceUploadDraftClient struct {
+	grpc.ClientStream
+}
+
+func (x *draftServiceUploadDraftClient) Send(m *UploadDraftRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *draftServiceUploadDraftClient) CloseAndRecv() (*UploadDraftResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadDraftResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *draftServiceClient) DownloadObject(ctx context.Context, in *DownloadObjectRequest, opts ...grpc.CallOption) (DraftService_DownloadObjectClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DraftService_ServiceDesc.Streams[1], DraftService_DownloadObject_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &draftServiceDownloadObjectClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DraftService_DownloadObjectClient interface {
+	Recv() (*DownloadObjectResponse, error)
+	grpc.ClientStream
+}
+
+type draftServiceDownloadObjectClient struct {
+	grpc.ClientStream
+}
+
+func (x *draftServiceDownloadObjectClient) Recv() (*DownloadObjectResponse, error) {
+	m := new(DownloadObjectResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DraftServiceServer is the server API for DraftService service.
 // All implementations must embed UnimplementedDraftServiceServer
 // for forward compatibility
@@ -101,8 +203,18 @@ type DraftServiceServer interface {
 	GetUploadURL(context.Context, *GetUploadURLRequest) (*GetUploadURLResponse, error)
 	// GetDownloadURL generates a presigned URL for downloading files from the main bucket
 	GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error)
+	// GetDraftDownloadURL generates a short-lived presigned URL for previewing an unconfirmed file in the draft bucket
+	GetDraftDownloadURL(context.Context, *GetDraftDownloadURLRequest) (*GetDraftDownloadURLResponse, error)
 	// ConfirmUpload moves a file from draft bucket to main bucket
 	ConfirmUpload(context.Context, *ConfirmUploadRequest) (*ConfirmUploadResponse, error)
+	// CancelUpload deletes an unconfirmed file and releases its quota reservation
+	CancelUpload(context.Context, *CancelUploadRequest) (*CancelUploadResponse, error)
+	// UploadDraft streams a file through the server into the draft bucket, for
+	// clients that cannot reach presigned URLs. The first message carries the
+	// metadata and the following ones the content
+	UploadDraft(DraftService_UploadDraftServer) error
+	// DownloadObject streams a file, or a byte range of it, from the main bucket
+	DownloadObject(*DownloadObjectRequest, DraftService_DownloadObjectServer) error
 	mustEmbedUnimplementedDraftServiceServer()
 }
 
@@ -119,9 +231,21 @@ func (UnimplementedDraftServiceServer) GetUploadURL(context.Context, *GetUploadU
 func (UnimplementedDraftServiceServer) GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadURL not implemented")
 }
+func (UnimplementedDraftServiceServer) GetDraftDownloadURL(context.Context, *GetDraftDownloadURLRequest) (*GetDraftDownloadURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDraftDownloadURL not implemented")
+}
 func (UnimplementedDraftServiceServer) ConfirmUpload(context.Context, *ConfirmUploadRequest) (*ConfirmUploadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConfirmUpload not implemented")
 }
+func (UnimplementedDraftServiceServer) CancelUpload(context.Context, *CancelUploadRequest) (*CancelUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelUpload not implemented")
+}
+func (UnimplementedDraftServiceServer) UploadDraft(DraftService_UploadDraftServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadDraft not implemented")
+}
+func (UnimplementedDraftServiceServer) DownloadObject(*DownloadObjectRequest, DraftService_DownloadObjectServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadObject not implemented")
+}
 func (UnimplementedDraftServiceServer) mustEmbedUnimplementedDraftServiceServer() {}
 
 // UnsafeDraftServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -189,6 +313,24 @@ func _DraftService_GetDownloadURL_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DraftService_GetDraftDownloadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDraftDownloadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DraftServiceServer).GetDraftDownloadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DraftService_GetDraftDownloadURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DraftServiceServer).GetDraftDownloadURL(ctx, req.(*GetDraftDownloadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DraftService_ConfirmUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ConfirmUploadRequest)
 	if err := dec(in); err != nil {
@@ -207,6 +349,71 @@ func _DraftService_ConfirmUpload_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DraftService_CancelUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DraftServiceServer).CancelUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DraftService_CancelUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DraftServiceServer).CancelUpload(ctx, req.(*CancelUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DraftService_UploadDraft_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DraftServiceServer).UploadDraft(&draftServiceUploadDraftServer{ServerStream: stream})
+}
+
+type DraftService_UploadDraftServer interface {
+	SendAndClose(*UploadDraftResponse) error
+	Recv() (*UploadDraftRequest, error)
+	grpc.ServerStream
+}
+
+type draftServiceUploadDraftServer struct {
+	grpc.ServerStream
+}
+
+func (x *draftServiceUploadDraftServer) SendAndClose(m *UploadDraftResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *draftServiceUploadDraftServer) Recv() (*UploadDraftRequest, error) {
+	m := new(UploadDraftRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DraftService_DownloadObject_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadObjectRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DraftServiceServer).DownloadObject(m, &draftServiceDownloadObjectServer{ServerStream: stream})
+}
+
+type DraftService_DownloadObjectServer interface {
+	Send(*DownloadObjectResponse) error
+	grpc.ServerStream
+}
+
+type draftServiceDownloadObjectServer struct {
+	grpc.ServerStream
+}
+
+func (x *draftServiceDownloadObjectServer) Send(m *DownloadObjectResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // DraftService_ServiceDesc is the grpc.ServiceDesc for DraftService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -226,11 +433,30 @@ var DraftService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDownloadURL",
 			Handler:    _DraftService_GetDownloadURL_Handler,
 		},
+		{
+			MethodName: "GetDraftDownloadURL",
+			Handler:    _DraftService_GetDraftDownloadURL_Handler,
+		},
 		{
 			MethodName: "ConfirmUpload",
 			Handler:    _DraftService_ConfirmUpload_Handler,
 		},
+		{
+			MethodName: "CancelUpload",
+			Handler:    _DraftService_CancelUpload_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadDraft",
+			Handler:       _DraftService_UploadDraft_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadObject",
+			Handler:       _DraftService_DownloadObject_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "draft/v1/draft.proto",
 }
@@ -0,0 +1,198 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: draft/v1/admin.proto
+
+package draftv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "draft.v1.AdminService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AdminServiceCreateAPIKeyProcedure is the fully-qualified name of the AdminService's CreateAPIKey
+	// RPC.
+	AdminServiceCreateAPIKeyProcedure = "/draft.v1.AdminService/CreateAPIKey"
+	// AdminServiceListAPIKeysProcedure is the fully-qualified name of the AdminService's ListAPIKeys
+	// RPC.
+	AdminServiceListAPIKeysProcedure = "/draft.v1.AdminService/ListAPIKeys"
+	// AdminServiceRevokeAPIKeyProcedure is the fully-qualified name of the AdminService's RevokeAPIKey
+	// RPC.
+	AdminServiceRevokeAPIKeyProcedure = "/draft.v1.AdminService/RevokeAPIKey"
+	// AdminServiceRotateAPIKeyProcedure is the fully-qualified name of the AdminService's RotateAPIKey
+	// RPC.
+	AdminServiceRotateAPIKeyProcedure = "/draft.v1.AdminService/RotateAPIKey"
+)
+
+// AdminServiceClient is a client for the draft.v1.AdminService service.
+type AdminServiceClient interface {
+	CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error)
+	ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error)
+	RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error)
+	// RotateAPIKey issues a replacement key and revokes the old one after a grace period
+	RotateAPIKey(context.Context, *connect.Request[v1.RotateAPIKeyRequest]) (*connect.Response[v1.RotateAPIKeyResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the draft.v1.AdminService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminServiceMethods := v1.File_draft_v1_admin_proto.Services().ByName("AdminService").Methods()
+	return &adminServiceClient{
+		createAPIKey: connect.NewClient[v1.CreateAPIKeyRequest, v1.CreateAPIKeyResponse](
+			httpClient,
+			baseURL+AdminServiceCreateAPIKeyProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("CreateAPIKey")),
+			connect.WithClientOptions(opts...),
+		),
+		listAPIKeys: connect.NewClient[v1.ListAPIKeysRequest, v1.ListAPIKeysResponse](
+			httpClient,
+			baseURL+AdminServiceListAPIKeysProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ListAPIKeys")),
+			connect.WithClientOptions(opts...),
+		),
+		revokeAPIKey: connect.NewClient[v1.RevokeAPIKeyRequest, v1.RevokeAPIKeyResponse](
+			httpClient,
+			baseURL+AdminServiceRevokeAPIKeyProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("RevokeAPIKey")),
+			connect.WithClientOptions(opts...),
+		),
+		rotateAPIKey: connect.NewClient[v1.RotateAPIKeyRequest, v1.RotateAPIKeyResponse](
+			httpClient,
+			baseURL+AdminServiceRotateAPIKeyProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("RotateAPIKey")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	createAPIKey *connect.Client[v1.CreateAPIKeyRequest, v1.CreateAPIKeyResponse]
+	listAPIKeys  *connect.Client[v1.ListAPIKeysRequest, v1.ListAPIKeysResponse]
+	revokeAPIKey *connect.Client[v1.RevokeAPIKeyRequest, v1.RevokeAPIKeyResponse]
+	rotateAPIKey *connect.Client[v1.RotateAPIKeyRequest, v1.RotateAPIKeyResponse]
+}
+
+// CreateAPIKey calls draft.v1.AdminService.CreateAPIKey.
+func (c *adminServiceClient) CreateAPIKey(ctx context.Context, req *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error) {
+	return c.createAPIKey.CallUnary(ctx, req)
+}
+
+// ListAPIKeys calls draft.v1.AdminService.ListAPIKeys.
+func (c *adminServiceClient) ListAPIKeys(ctx context.Context, req *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error) {
+	return c.listAPIKeys.CallUnary(ctx, req)
+}
+
+// RevokeAPIKey calls draft.v1.AdminService.RevokeAPIKey.
+func (c *adminServiceClient) RevokeAPIKey(ctx context.Context, req *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error) {
+	return c.revokeAPIKey.CallUnary(ctx, req)
+}
+
+// RotateAPIKey calls draft.v1.AdminService.RotateAPIKey.
+func (c *adminServiceClient) RotateAPIKey(ctx context.Context, req *connect.Request[v1.RotateAPIKeyRequest]) (*connect.Response[v1.RotateAPIKeyResponse], error) {
+	return c.rotateAPIKey.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the draft.v1.AdminService service.
+type AdminServiceHandler interface {
+	CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error)
+	ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error)
+	RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error)
+	// RotateAPIKey issues a replacement key and revokes the old one after a grace period
+	RotateAPIKey(context.Context, *connect.Request[v1.RotateAPIKeyRequest]) (*connect.Response[v1.RotateAPIKeyResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminServiceMethods := v1.File_draft_v1_admin_proto.Services().ByName("AdminService").Methods()
+	adminServiceCreateAPIKeyHandler := connect.NewUnaryHandler(
+		AdminServiceCreateAPIKeyProcedure,
+		svc.CreateAPIKey,
+		connect.WithSchema(adminServiceMethods.ByName("CreateAPIKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceListAPIKeysHandler := connect.NewUnaryHandler(
+		AdminServiceListAPIKeysProcedure,
+		svc.ListAPIKeys,
+		connect.WithSchema(adminServiceMethods.ByName("ListAPIKeys")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRevokeAPIKeyHandler := connect.NewUnaryHandler(
+		AdminServiceRevokeAPIKeyProcedure,
+		svc.RevokeAPIKey,
+		connect.WithSchema(adminServiceMethods.ByName("RevokeAPIKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRotateAPIKeyHandler := connect.NewUnaryHandler(
+		AdminServiceRotateAPIKeyProcedure,
+		svc.RotateAPIKey,
+		connect.WithSchema(adminServiceMethods.ByName("RotateAPIKey")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/draft.v1.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceCreateAPIKeyProcedure:
+			adminServiceCreateAPIKeyHandler.ServeHTTP(w, r)
+		case AdminServiceListAPIKeysProcedure:
+			adminServiceListAPIKeysHandler.ServeHTTP(w, r)
+		case AdminServiceRevokeAPIKeyProcedure:
+			adminServiceRevokeAPIKeyHandler.ServeHTTP(w, r)
+		case AdminServiceRotateAPIKeyProcedure:
+			adminServiceRotateAPIKeyHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) CreateAPIKey(context.Context, *connect.Request[v1.CreateAPIKeyRequest]) (*connect.Response[v1.CreateAPIKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.AdminService.CreateAPIKey is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) ListAPIKeys(context.Context, *connect.Request[v1.ListAPIKeysRequest]) (*connect.Response[v1.ListAPIKeysResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.AdminService.ListAPIKeys is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RevokeAPIKey(context.Context, *connect.Request[v1.RevokeAPIKeyRequest]) (*connect.Response[v1.RevokeAPIKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.AdminService.RevokeAPIKey is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RotateAPIKey(context.Context, *connect.Request[v1.RotateAPIKeyRequest]) (*connect.Response[v1.RotateAPIKeyResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.AdminService.RotateAPIKey is not implemented"))
+}
@@ -0,0 +1,332 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: draft/v1/draft.proto
+
+package draftv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// DraftServiceName is the fully-qualified name of the DraftService service.
+	DraftServiceName = "draft.v1.DraftService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DraftServiceCreateDraftBucketProcedure is the fully-qualified name of the DraftService's
+	// CreateDraftBucket RPC.
+	DraftServiceCreateDraftBucketProcedure = "/draft.v1.DraftService/CreateDraftBucket"
+	// DraftServiceGetUploadURLProcedure is the fully-qualified name of the DraftService's GetUploadURL
+	// RPC.
+	DraftServiceGetUploadURLProcedure = "/draft.v1.DraftService/GetUploadURL"
+	// DraftServiceGetDownloadURLProcedure is the fully-qualified name of the DraftService's
+	// GetDownloadURL RPC.
+	DraftServiceGetDownloadURLProcedure = "/draft.v1.DraftService/GetDownloadURL"
+	// DraftServiceGetDraftDownloadURLProcedure is the fully-qualified name of the DraftService's
+	// GetDraftDownloadURL RPC.
+	DraftServiceGetDraftDownloadURLProcedure = "/draft.v1.DraftService/GetDraftDownloadURL"
+	// DraftServiceConfirmUploadProcedure is the fully-qualified name of the DraftService's
+	// ConfirmUpload RPC.
+	DraftServiceConfirmUploadProcedure = "/draft.v1.DraftService/ConfirmUpload"
+	// DraftServiceCancelUploadProcedure is the fully-qualified name of the DraftService's CancelUpload
+	// RPC.
+	DraftServiceCancelUploadProcedure = "/draft.v1.DraftService/CancelUpload"
+	// DraftServiceUploadDraftProcedure is the fully-qualified name of the DraftService's UploadDraft
+	// RPC.
+	DraftServiceUploadDraftProcedure = "/draft.v1.DraftService/UploadDraft"
+	// DraftServiceDownloadObjectProcedure is the fully-qualified name of the DraftService's
+	// DownloadObject RPC.
+	DraftServiceDownloadObjectProcedure = "/draft.v1.DraftService/DownloadObject"
+)
+
+// DraftServiceClient is a client for the draft.v1.DraftService service.
+type DraftServiceClient interface {
+	// CreateDraftBucket creates the necessary buckets for draft operations
+	CreateDraftBucket(context.Context, *connect.Request[v1.CreateDraftBucketRequest]) (*connect.Response[v1.CreateDraftBucketResponse], error)
+	// GetUploadURL generates a presigned URL for uploading files to the draft bucket
+	GetUploadURL(context.Context, *connect.Request[v1.GetUploadURLRequest]) (*connect.Response[v1.GetUploadURLResponse], error)
+	// GetDownloadURL generates a presigned URL for downloading files from the main bucket
+	GetDownloadURL(context.Context, *connect.Request[v1.GetDownloadURLRequest]) (*connect.Response[v1.GetDownloadURLResponse], error)
+	// GetDraftDownloadURL generates a short-lived presigned URL for previewing an unconfirmed file in the draft bucket
+	GetDraftDownloadURL(context.Context, *connect.Request[v1.GetDraftDownloadURLRequest]) (*connect.Response[v1.GetDraftDownloadURLResponse], error)
+	// ConfirmUpload moves a file from draft bucket to main bucket
+	ConfirmUpload(context.Context, *connect.Request[v1.ConfirmUploadRequest]) (*connect.Response[v1.ConfirmUploadResponse], error)
+	// CancelUpload deletes an unconfirmed file and releases its quota reservation
+	CancelUpload(context.Context, *connect.Request[v1.CancelUploadRequest]) (*connect.Response[v1.CancelUploadResponse], error)
+	// UploadDraft streams a file through the server into the draft bucket, for
+	// clients that cannot reach presigned URLs. The first message carries the
+	// metadata and the following ones the content
+	UploadDraft(context.Context) *connect.ClientStreamForClient[v1.UploadDraftRequest, v1.UploadDraftResponse]
+	// DownloadObject streams a file, or a byte range of it, from the main bucket
+	DownloadObject(context.Context, *connect.Request[v1.DownloadObjectRequest]) (*connect.ServerStreamForClient[v1.DownloadObjectResponse], error)
+}
+
+// NewDraftServiceClient constructs a client for the draft.v1.DraftService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDraftServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) DraftServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	draftServiceMethods := v1.File_draft_v1_draft_proto.Services().ByName("DraftService").Methods()
+	return &draftServiceClient{
+		createDraftBucket: connect.NewClient[v1.CreateDraftBucketRequest, v1.CreateDraftBucketResponse](
+			httpClient,
+			baseURL+DraftServiceCreateDraftBucketProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("CreateDraftBucket")),
+			connect.WithClientOptions(opts...),
+		),
+		getUploadURL: connect.NewClient[v1.GetUploadURLRequest, v1.GetUploadURLResponse](
+			httpClient,
+			baseURL+DraftServiceGetUploadURLProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("GetUploadURL")),
+			connect.WithClientOptions(opts...),
+		),
+		getDownloadURL: connect.NewClient[v1.GetDownloadURLRequest, v1.GetDownloadURLResponse](
+			httpClient,
+			baseURL+DraftServiceGetDownloadURLProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("GetDownloadURL")),
+			connect.WithClientOptions(opts...),
+		),
+		getDraftDownloadURL: connect.NewClient[v1.GetDraftDownloadURLRequest, v1.GetDraftDownloadURLResponse](
+			httpClient,
+			baseURL+DraftServiceGetDraftDownloadURLProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("GetDraftDownloadURL")),
+			connect.WithClientOptions(opts...),
+		),
+		confirmUpload: connect.NewClient[v1.ConfirmUploadRequest, v1.ConfirmUploadResponse](
+			httpClient,
+			baseURL+DraftServiceConfirmUploadProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("ConfirmUpload")),
+			connect.WithClientOptions(opts...),
+		),
+		cancelUpload: connect.NewClient[v1.CancelUploadRequest, v1.CancelUploadResponse](
+			httpClient,
+			baseURL+DraftServiceCancelUploadProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("CancelUpload")),
+			connect.WithClientOptions(opts...),
+		),
+		uploadDraft: connect.NewClient[v1.UploadDraftRequest, v1.UploadDraftResponse](
+			httpClient,
+			baseURL+DraftServiceUploadDraftProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("UploadDraft")),
+			connect.WithClientOptions(opts...),
+		),
+		downloadObject: connect.NewClient[v1.DownloadObjectRequest, v1.DownloadObjectResponse](
+			httpClient,
+			baseURL+DraftServiceDownloadObjectProcedure,
+			connect.WithSchema(draftServiceMethods.ByName("DownloadObject")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// draftServiceClient implements DraftServiceClient.
+type draftServiceClient struct {
+	createDraftBucket   *connect.Client[v1.CreateDraftBucketRequest, v1.CreateDraftBucketResponse]
+	getUploadURL        *connect.Client[v1.GetUploadURLRequest, v1.GetUploadURLResponse]
+	getDownloadURL      *connect.Client[v1.GetDownloadURLRequest, v1.GetDownloadURLResponse]
+	getDraftDownloadURL *connect.Client[v1.GetDraftDownloadURLRequest, v1.GetDraftDownloadURLResponse]
+	confirmUpload       *connect.Client[v1.ConfirmUploadRequest, v1.ConfirmUploadResponse]
+	cancelUpload        *connect.Client[v1.CancelUploadRequest, v1.CancelUploadResponse]
+	uploadDraft         *connect.Client[v1.UploadDraftRequest, v1.UploadDraftResponse]
+	downloadObject      *connect.Client[v1.DownloadObjectRequest, v1.DownloadObjectResponse]
+}
+
+// CreateDraftBucket calls draft.v1.DraftService.CreateDraftBucket.
+func (c *draftServiceClient) CreateDraftBucket(ctx context.Context, req *connect.Request[v1.CreateDraftBucketRequest]) (*connect.Response[v1.CreateDraftBucketResponse], error) {
+	return c.createDraftBucket.CallUnary(ctx, req)
+}
+
+// GetUploadURL calls draft.v1.DraftService.GetUploadURL.
+func (c *draftServiceClient) GetUploadURL(ctx context.Context, req *connect.Request[v1.GetUploadURLRequest]) (*connect.Response[v1.GetUploadURLResponse], error) {
+	return c.getUploadURL.CallUnary(ctx, req)
+}
+
+// GetDownloadURL calls draft.v1.DraftService.GetDownloadURL.
+func (c *draftServiceClient) GetDownloadURL(ctx context.Context, req *connect.Request[v1.GetDownloadURLRequest]) (*connect.Response[v1.GetDownloadURLResponse], error) {
+	return c.getDownloadURL.CallUnary(ctx, req)
+}
+
+// GetDraftDownloadURL calls draft.v1.DraftService.GetDraftDownloadURL.
+func (c *draftServiceClient) GetDraftDownloadURL(ctx context.Context, req *connect.Request[v1.GetDraftDownloadURLRequest]) (*connect.Response[v1.GetDraftDownloadURLResponse], error) {
+	return c.getDraftDownloadURL.CallUnary(ctx, req)
+}
+
+// ConfirmUpload calls draft.v1.DraftService.ConfirmUpload.
+func (c *draftServiceClient) ConfirmUpload(ctx context.Context, req *connect.Request[v1.ConfirmUploadRequest]) (*connect.Response[v1.ConfirmUploadResponse], error) {
+	return c.confirmUpload.CallUnary(ctx, req)
+}
+
+// CancelUpload calls draft.v1.DraftService.CancelUpload.
+func (c *draftServiceClient) CancelUpload(ctx context.Context, req *connect.Request[v1.CancelUploadRequest]) (*connect.Response[v1.CancelUploadResponse], error) {
+	return c.cancelUpload.CallUnary(ctx, req)
+}
+
+// UploadDraft calls draft.v1.DraftService.UploadDraft.
+func (c *draftServiceClient) UploadDraft(ctx context.Context) *connect.ClientStreamForClient[v1.UploadDraftRequest, v1.UploadDraftResponse] {
+	return c.uploadDraft.CallClientStream(ctx)
+}
+
+// DownloadObject calls draft.v1.DraftService.DownloadObject.
+func (c *draftServiceClient) DownloadObject(ctx context.Context, req *connect.Request[v1.DownloadObjectRequest]) (*connect.ServerStreamForClient[v1.DownloadObjectResponse], error) {
+	return c.downloadObject.CallServerStream(ctx, req)
+}
+
+// DraftServiceHandler is an implementation of the draft.v1.DraftService service.
+type DraftServiceHandler interface {
+	// CreateDraftBucket creates the necessary buckets for draft operations
+	CreateDraftBucket(context.Context, *connect.Request[v1.CreateDraftBucketRequest]) (*connect.Response[v1.CreateDraftBucketResponse], error)
+	// GetUploadURL generates a presigned URL for uploading files to the draft bucket
+	GetUploadURL(context.Context, *connect.Request[v1.GetUploadURLRequest]) (*connect.Response[v1.GetUploadURLResponse], error)
+	// GetDownloadURL generates a presigned URL for downloading files from the main bucket
+	GetDownloadURL(context.Context, *connect.Request[v1.GetDownloadURLRequest]) (*connect.Response[v1.GetDownloadURLResponse], error)
+	// GetDraftDownloadURL generates a short-lived presigned URL for previewing an unconfirmed file in the draft bucket
+	GetDraftDownloadURL(context.Context, *connect.Request[v1.GetDraftDownloadURLRequest]) (*connect.Response[v1.GetDraftDownloadURLResponse], error)
+	// ConfirmUpload moves a file from draft bucket to main bucket
+	ConfirmUpload(context.Context, *connect.Request[v1.ConfirmUploadRequest]) (*connect.Response[v1.ConfirmUploadResponse], error)
+	// CancelUpload deletes an unconfirmed file and releases its quota reservation
+	CancelUpload(context.Context, *connect.Request[v1.CancelUploadRequest]) (*connect.Response[v1.CancelUploadResponse], error)
+	// UploadDraft streams a file through the server into the draft bucket, for
+	// clients that cannot reach presigned URLs. The first message carries the
+	// metadata and the following ones the content
+	UploadDraft(context.Context, *connect.ClientStream[v1.UploadDraftRequest]) (*connect.Response[v1.UploadDraftResponse], error)
+	// DownloadObject streams a file, or a byte range of it, from the main bucket
+	DownloadObject(context.Context, *connect.Request[v1.DownloadObjectRequest], *connect.ServerStream[v1.DownloadObjectResponse]) error
+}
+
+// NewDraftServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDraftServiceHandler(svc DraftServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	draftServiceMethods := v1.File_draft_v1_draft_proto.Services().ByName("DraftService").Methods()
+	draftServiceCreateDraftBucketHandler := connect.NewUnaryHandler(
+		DraftServiceCreateDraftBucketProcedure,
+		svc.CreateDraftBucket,
+		connect.WithSchema(draftServiceMethods.ByName("CreateDraftBucket")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceGetUploadURLHandler := connect.NewUnaryHandler(
+		DraftServiceGetUploadURLProcedure,
+		svc.GetUploadURL,
+		connect.WithSchema(draftServiceMethods.ByName("GetUploadURL")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceGetDownloadURLHandler := connect.NewUnaryHandler(
+		DraftServiceGetDownloadURLProcedure,
+		svc.GetDownloadURL,
+		connect.WithSchema(draftServiceMethods.ByName("GetDownloadURL")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceGetDraftDownloadURLHandler := connect.NewUnaryHandler(
+		DraftServiceGetDraftDownloadURLProcedure,
+		svc.GetDraftDownloadURL,
+		connect.WithSchema(draftServiceMethods.ByName("GetDraftDownloadURL")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceConfirmUploadHandler := connect.NewUnaryHandler(
+		DraftServiceConfirmUploadProcedure,
+		svc.ConfirmUpload,
+		connect.WithSchema(draftServiceMethods.ByName("ConfirmUpload")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceCancelUploadHandler := connect.NewUnaryHandler(
+		DraftServiceCancelUploadProcedure,
+		svc.CancelUpload,
+		connect.WithSchema(draftServiceMethods.ByName("CancelUpload")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceUploadDraftHandler := connect.NewClientStreamHandler(
+		DraftServiceUploadDraftProcedure,
+		svc.UploadDraft,
+		connect.WithSchema(draftServiceMethods.ByName("UploadDraft")),
+		connect.WithHandlerOptions(opts...),
+	)
+	draftServiceDownloadObjectHandler := connect.NewServerStreamHandler(
+		DraftServiceDownloadObjectProcedure,
+		svc.DownloadObject,
+		connect.WithSchema(draftServiceMethods.ByName("DownloadObject")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/draft.v1.DraftService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DraftServiceCreateDraftBucketProcedure:
+			draftServiceCreateDraftBucketHandler.ServeHTTP(w, r)
+		case DraftServiceGetUploadURLProcedure:
+			draftServiceGetUploadURLHandler.ServeHTTP(w, r)
+		case DraftServiceGetDownloadURLProcedure:
+			draftServiceGetDownloadURLHandler.ServeHTTP(w, r)
+		case DraftServiceGetDraftDownloadURLProcedure:
+			draftServiceGetDraftDownloadURLHandler.ServeHTTP(w, r)
+		case DraftServiceConfirmUploadProcedure:
+			draftServiceConfirmUploadHandler.ServeHTTP(w, r)
+		case DraftServiceCancelUploadProcedure:
+			draftServiceCancelUploadHandler.ServeHTTP(w, r)
+		case DraftServiceUploadDraftProcedure:
+			draftServiceUploadDraftHandler.ServeHTTP(w, r)
+		case DraftServiceDownloadObjectProcedure:
+			draftServiceDownloadObjectHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDraftServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDraftServiceHandler struct{}
+
+func (UnimplementedDraftServiceHandler) CreateDraftBucket(context.Context, *connect.Request[v1.CreateDraftBucketRequest]) (*connect.Response[v1.CreateDraftBucketResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.CreateDraftBucket is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) GetUploadURL(context.Context, *connect.Request[v1.GetUploadURLRequest]) (*connect.Response[v1.GetUploadURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.GetUploadURL is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) GetDownloadURL(context.Context, *connect.Request[v1.GetDownloadURLRequest]) (*connect.Response[v1.GetDownloadURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.GetDownloadURL is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) GetDraftDownloadURL(context.Context, *connect.Request[v1.GetDraftDownloadURLRequest]) (*connect.Response[v1.GetDraftDownloadURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.GetDraftDownloadURL is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) ConfirmUpload(context.Context, *connect.Request[v1.ConfirmUploadRequest]) (*connect.Response[v1.ConfirmUploadResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.ConfirmUpload is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) CancelUpload(context.Context, *connect.Request[v1.CancelUploadRequest]) (*connect.Response[v1.CancelUploadResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.CancelUpload is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) UploadDraft(context.Context, *connect.ClientStream[v1.UploadDraftRequest]) (*connect.Response[v1.UploadDraftResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.UploadDraft is not implemented"))
+}
+
+func (UnimplementedDraftServiceHandler) DownloadObject(context.Context, *connect.Request[v1.DownloadObjectRequest], *connect.ServerStream[v1.DownloadObjectResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("draft.v1.DraftService.DownloadObject is not implemented"))
+}
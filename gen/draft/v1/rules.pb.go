@@ -0,0 +1,91 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: draft/v1/rules.proto
+
+package draftv1
+
+import (
+	validate "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_draft_v1_rules_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*validate.StringRules)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         1000,
+		Name:          "draft.v1.object_key",
+		Tag:           "varint,1000,opt,name=object_key",
+		Filename:      "draft/v1/rules.proto",
+	},
+}
+
+// Extension fields to validate.StringRules.
+var (
+	// optional bool object_key = 1000;
+	E_ObjectKey = &file_draft_v1_rules_proto_extTypes[0]
+)
+
+var File_draft_v1_rules_proto protoreflect.FileDescriptor
+
+const file_draft_v1_rules_proto_rawDesc = "" +
+	"\n" +
+	"\x14draft/v1/rules.proto\x12\bdraft.v1\x1a\x1bbuf/validate/validate.proto:\xd0\x03\n" +
+	"\n" +
+	"object_key\x12\x19.buf.validate.StringRules\x18\xe8\a \x01(\bB\x94\x03\xc2H\x90\x03\n" +
+	"\x87\x01\n" +
+	"\x15string.object_key.len\x1anrule && (this.size() == 0 || bytes(this).size() > 1024) ? 'value length must be between 1 and 1024 bytes' : ''\n" +
+	"\x89\x01\n" +
+	"\x19string.object_key.charset\x1alrule && !this.matches('^[A-Za-z0-9!_.*()/-]*$') ? 'value may only contain letters, digits and !-_.*()/' : ''\n" +
+	"x\n" +
+	"\x1astring.object_key.segments\x1aZrule && this.matches('(^|/)[.][.]?(/|$)') ? 'value must not contain . or .. segments' : ''R\tobjectKeyB\x91\x01\n" +
+	"\fcom.draft.v1B\n" +
+	"RulesProtoP\x01Z4github.com/snowmerak/DraftStore/gen/draft/v1;draftv1\xa2\x02\x03DXX\xaa\x02\bDraft.V1\xca\x02\bDraft\\V1\xe2\x02\x14Draft\\V1\\GPBMetadata\xea\x02\tDraft::V1"
+
+var file_draft_v1_rules_proto_goTypes = []any{
+	(*validate.StringRules)(nil), // 0: buf.validate.StringRules
+}
+var file_draft_v1_rules_proto_depIdxs = []int32{
+	0, // 0: draft.v1.object_key:extendee -> buf.validate.StringRules
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_draft_v1_rules_proto_init() }
+func file_draft_v1_rules_proto_init() {
+	if File_draft_v1_rules_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_draft_v1_rules_proto_rawDesc), len(file_draft_v1_rules_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_draft_v1_rules_proto_goTypes,
+		DependencyIndexes: file_draft_v1_rules_proto_depIdxs,
+		ExtensionInfos:    file_draft_v1_rules_proto_extTypes,
+	}.Build()
+	File_draft_v1_rules_proto = out.File
+	file_draft_v1_rules_proto_goTypes = nil
+	file_draft_v1_rules_proto_depIdxs = nil
+}
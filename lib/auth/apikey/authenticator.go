@@ -0,0 +1,102 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+)
+
+// Principal attributes of API key callers.
+const (
+	AttributeScopes = "scopes"
+	AttributePrefix = "key_prefix"
+)
+
+var (
+	_ auth.Authenticator = (*Authenticator)(nil)
+	_ authz.Authorizer   = ScopeAuthorizer{}
+)
+
+// Authenticator authenticates callers presenting an API key in the X-API-Key header.
+type Authenticator struct {
+	store Store
+}
+
+func NewAuthenticator(store Store) *Authenticator {
+	return &Authenticator{store: store}
+}
+
+// Authenticate implements auth.Authenticator.
+func (a *Authenticator) Authenticate(ctx context.Context, header auth.Header) (auth.Principal, error) {
+	secret := header.Get(Header)
+	if secret == "" {
+		return auth.Principal{}, auth.ErrNoCredentials
+	}
+
+	id, err := ParseID(secret)
+	if err != nil {
+		return auth.Principal{}, auth.Unauthenticated(err)
+	}
+
+	key, err := a.store.Get(ctx, id)
+	if errors.Is(err, ErrKeyNotFound) {
+		return auth.Principal{}, auth.Unauthenticated(ErrInvalidSecret)
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if !key.Verify(secret) || !key.Active(time.Now()) {
+		return auth.Principal{}, auth.Unauthenticated(ErrInvalidSecret)
+	}
+
+	return auth.Principal{
+		Subject: key.ID,
+		Method:  auth.MethodAPIKey,
+		Attributes: map[string]string{
+			AttributeScopes: strings.Join(key.Scopes, " "),
+			AttributePrefix: key.Prefix,
+		},
+	}, nil
+}
+
+// ScopeAuthorizer restricts API key callers to the operations covered by their
+// scopes and to object keys under their prefix. Other callers are allowed, so
+// it only narrows other authorizers combined with it in authz.All and never
+// grants authz.OperationAdmin to them on its own.
+type ScopeAuthorizer struct{}
+
+// Authorize implements authz.Authorizer.
+func (ScopeAuthorizer) Authorize(ctx context.Context, req authz.Request) (bool, error) {
+	if req.Principal.Method != auth.MethodAPIKey {
+		return true, nil
+	}
+
+	scopes := strings.Fields(req.Principal.Attributes[AttributeScopes])
+
+	var scope string
+	switch req.Operation {
+	case authz.OperationUpload, authz.OperationDraftDownload, authz.OperationCancel:
+		scope = ScopeUpload
+	case authz.OperationConfirm:
+		scope = ScopeConfirm
+	case authz.OperationDownload:
+		scope = ScopeDownload
+	default:
+		scope = ScopeAdmin
+	}
+	// The admin scope covers every operation
+	if !slices.Contains(scopes, scope) && !slices.Contains(scopes, ScopeAdmin) {
+		return false, nil
+	}
+
+	if prefix := req.Principal.Attributes[AttributePrefix]; prefix != "" && req.Key != "" && !strings.HasPrefix(req.Key, prefix) {
+		return false, nil
+	}
+
+	return true, nil
+}
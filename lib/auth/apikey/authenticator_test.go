@@ -0,0 +1,65 @@
+package apikey_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/auth/apikey"
+	"github.com/snowmerak/DraftStore/lib/authz"
+)
+
+func keyPrincipal(scopes, prefix string) auth.Principal {
+	return auth.Principal{
+		Subject: "3f2a9c1d7b6e5a40",
+		Method:  auth.MethodAPIKey,
+		Attributes: map[string]string{
+			apikey.AttributeScopes: scopes,
+			apikey.AttributePrefix: prefix,
+		},
+	}
+}
+
+func TestScopeAuthorizer(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		operation string
+		key       string
+		want      bool
+	}{
+		{name: "upload scope uploads", principal: keyPrincipal("upload", ""), operation: authz.OperationUpload, key: "a.txt", want: true},
+		{name: "upload scope previews drafts", principal: keyPrincipal("upload", ""), operation: authz.OperationDraftDownload, key: "a.txt", want: true},
+		{name: "upload scope cancels", principal: keyPrincipal("upload", ""), operation: authz.OperationCancel, key: "a.txt", want: true},
+		{name: "upload scope cannot confirm", principal: keyPrincipal("upload", ""), operation: authz.OperationConfirm, key: "a.txt", want: false},
+		{name: "confirm scope confirms", principal: keyPrincipal("upload confirm", ""), operation: authz.OperationConfirm, key: "a.txt", want: true},
+		{name: "download scope downloads", principal: keyPrincipal("download", ""), operation: authz.OperationDownload, key: "a.txt", want: true},
+		{name: "download scope cannot upload", principal: keyPrincipal("download", ""), operation: authz.OperationUpload, key: "a.txt", want: false},
+		{name: "no scopes", principal: keyPrincipal("", ""), operation: authz.OperationDownload, key: "a.txt", want: false},
+		{name: "admin operation needs admin scope", principal: keyPrincipal("upload confirm download", ""), operation: authz.OperationAdmin, want: false},
+		{name: "unknown operation needs admin scope", principal: keyPrincipal("upload", ""), operation: authz.OperationCreateBucket, want: false},
+		{name: "admin scope administers", principal: keyPrincipal("admin", ""), operation: authz.OperationAdmin, want: true},
+		{name: "admin scope covers every operation", principal: keyPrincipal("admin", ""), operation: authz.OperationConfirm, key: "a.txt", want: true},
+		{name: "key under prefix", principal: keyPrincipal("upload", "tenants/acme/"), operation: authz.OperationUpload, key: "tenants/acme/a.txt", want: true},
+		{name: "key outside prefix", principal: keyPrincipal("upload", "tenants/acme/"), operation: authz.OperationUpload, key: "tenants/other/a.txt", want: false},
+		{name: "admin scope keeps prefix", principal: keyPrincipal("admin", "tenants/acme/"), operation: authz.OperationDownload, key: "a.txt", want: false},
+		{name: "operation without key ignores prefix", principal: keyPrincipal("admin", "tenants/acme/"), operation: authz.OperationAdmin, want: true},
+		{name: "other callers are not restricted", principal: auth.Principal{Subject: "alice", Method: auth.MethodJWT}, operation: authz.OperationUpload, key: "a.txt", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := apikey.ScopeAuthorizer{}.Authorize(context.Background(), authz.Request{
+				Operation: tt.operation,
+				Key:       tt.key,
+				Principal: tt.principal,
+			})
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
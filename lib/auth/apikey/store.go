@@ -0,0 +1,185 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+type Store interface {
+	Get(ctx context.Context, id string) (Key, error)
+	List(ctx context.Context) ([]Key, error)
+	// Put creates or replaces a key.
+	Put(ctx context.Context, key Key) error
+}
+
+var _ Store = (*FileStore)(nil)
+
+// FileStore keeps keys in a JSON file. Changes made by other replicas are
+// picked up when the file's modification time changes.
+type FileStore struct {
+	path    string
+	mu      sync.RWMutex
+	keys    map[string]Key
+	modTime time.Time
+}
+
+type fileDocument struct {
+	Keys []Key `json:"keys"`
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	log := logger.GetServiceLogger("apikey-store")
+
+	store := &FileStore{
+		path: path,
+		keys: make(map[string]Key),
+	}
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("path", path).
+		Int("keys", len(store.keys)).
+		Msg("API key file store initialized")
+
+	return store, nil
+}
+
+// reload reads the file if it changed since the last read. A missing file is
+// an empty store.
+func (s *FileStore) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat api key file: %w", err)
+	}
+
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read api key file: %w", err)
+	}
+
+	var document fileDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to decode api key file: %w", err)
+	}
+
+	keys := make(map[string]Key, len(document.Keys))
+	for _, key := range document.Keys {
+		keys[key.ID] = key
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.modTime = info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(ctx context.Context, id string) (Key, error) {
+	if err := s.reload(); err != nil {
+		return Key{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return Key{}, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// List implements Store.
+func (s *FileStore) List(ctx context.Context) ([]Key, error) {
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Put implements Store. The file is replaced atomically.
+func (s *FileStore) Put(ctx context.Context, key Key) error {
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make(map[string]Key, len(s.keys)+1)
+	for id, existing := range s.keys {
+		keys[id] = existing
+	}
+	keys[key.ID] = key
+
+	document := fileDocument{Keys: make([]Key, 0, len(keys))}
+	for _, k := range keys {
+		document.Keys = append(document.Keys, k)
+	}
+	sort.Slice(document.Keys, func(i, j int) bool {
+		return document.Keys[i].ID < document.Keys[j].ID
+	})
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode api key file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".apikeys-*")
+	if err != nil {
+		return fmt.Errorf("failed to write api key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write api key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write api key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace api key file: %w", err)
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat api key file: %w", err)
+	}
+	s.keys = keys
+	s.modTime = info.ModTime()
+	return nil
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request carries
+	// no credentials it understands.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrUnauthenticated is returned when credentials are present but invalid.
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// Header gives access to request headers or gRPC metadata.
+type Header interface {
+	Get(name string) string
+}
+
+// Authenticator derives the caller's principal from request headers.
+type Authenticator interface {
+	Authenticate(ctx context.Context, header Header) (Principal, error)
+}
+
+// Chain tries each authenticator in order and returns the first principal.
+// An authenticator returning ErrNoCredentials passes to the next one.
+type Chain []Authenticator
+
+// Authenticate implements Authenticator.
+func (c Chain) Authenticate(ctx context.Context, header Header) (Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(ctx, header)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+		return principal, nil
+	}
+	return Principal{}, ErrNoCredentials
+}
+
+// Unauthenticated wraps err so it satisfies errors.Is(err, ErrUnauthenticated).
+func Unauthenticated(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+}
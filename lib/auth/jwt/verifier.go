@@ -0,0 +1,420 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	DefaultRefreshInterval = 5 * time.Minute
+	DefaultLeeway          = 30 * time.Second
+	// MinRefreshInterval rate limits refreshes triggered by unknown key IDs.
+	MinRefreshInterval = 30 * time.Second
+)
+
+var _ auth.Authenticator = (*Verifier)(nil)
+
+// Verifier authenticates bearer tokens signed by keys of a JWKS.
+type Verifier struct {
+	issuer    string
+	audience  string
+	leeway    time.Duration
+	jwksURL   string
+	client    *http.Client
+	refresh   time.Duration
+	mu        sync.RWMutex
+	keys      keySet
+	refreshed time.Time
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+type VerifierOptions struct {
+	// Issuer is required and compared with the iss claim. When JWKSSource
+	// is empty the JWKS URL is discovered from the issuer's OpenID Connect
+	// configuration.
+	Issuer string
+	// Audience is required and must be contained in the aud claim, so that
+	// tokens the issuer minted for other services are rejected.
+	Audience string
+	// JWKSSource is a JWKS file path or http(s) URL.
+	JWKSSource string
+	// RefreshInterval is how often the JWKS is reloaded.
+	RefreshInterval time.Duration
+	// Leeway tolerates clock skew when checking exp and nbf.
+	Leeway     time.Duration
+	HTTPClient *http.Client
+}
+
+func NewVerifier(ctx context.Context, opts VerifierOptions) (*Verifier, error) {
+	log := logger.GetServiceLogger("jwt-verifier")
+
+	if opts.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if opts.Audience == "" {
+		return nil, errors.New("audience is required")
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+	if opts.Leeway <= 0 {
+		opts.Leeway = DefaultLeeway
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	v := &Verifier{
+		issuer:   opts.Issuer,
+		audience: opts.Audience,
+		leeway:   opts.Leeway,
+		jwksURL:  opts.JWKSSource,
+		client:   opts.HTTPClient,
+		refresh:  opts.RefreshInterval,
+		stop:     make(chan struct{}),
+	}
+
+	if v.jwksURL == "" {
+		jwksURL, err := discoverJWKSURL(ctx, v.client, v.issuer)
+		if err != nil {
+			return nil, err
+		}
+		v.jwksURL = jwksURL
+	}
+
+	if err := v.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	go v.refreshLoop()
+
+	log.Info().
+		Str("issuer", v.issuer).
+		Str("audience", v.audience).
+		Str("jwks", v.jwksURL).
+		Dur("refresh_interval", v.refresh).
+		Int("keys", len(v.keys)).
+		Msg("JWT verifier initialized")
+
+	return v, nil
+}
+
+// Close stops the background JWKS refresh.
+func (v *Verifier) Close() {
+	v.stopOnce.Do(func() {
+		close(v.stop)
+	})
+}
+
+func (v *Verifier) reload(ctx context.Context) error {
+	keys, err := loadKeySet(ctx, v.client, v.jwksURL)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.refreshed = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *Verifier) refreshLoop() {
+	log := logger.GetServiceLogger("jwt-verifier")
+
+	ticker := time.NewTicker(v.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), v.refresh)
+			if err := v.reload(ctx); err != nil {
+				// Keep serving with the previous keys
+				log.Error().
+					Err(err).
+					Str("jwks", v.jwksURL).
+					Msg("Failed to refresh JWKS")
+			}
+			cancel()
+		}
+	}
+}
+
+// key returns the key for kid, reloading the JWKS once if it is unknown so
+// rotated keys are picked up before the next scheduled refresh.
+func (v *Verifier) key(ctx context.Context, kid string) (signingKey, error) {
+	v.mu.RLock()
+	key, ok := v.lookup(kid)
+	v.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	// Record the attempt up front so failing reloads are rate limited too
+	v.mu.Lock()
+	stale := time.Since(v.refreshed) > MinRefreshInterval
+	if stale {
+		v.refreshed = time.Now()
+	}
+	v.mu.Unlock()
+
+	if stale {
+		if err := v.reload(ctx); err != nil {
+			return signingKey{}, err
+		}
+		v.mu.RLock()
+		key, ok = v.lookup(kid)
+		v.mu.RUnlock()
+		if ok {
+			return key, nil
+		}
+	}
+	return signingKey{}, fmt.Errorf("unknown key %q", kid)
+}
+
+func (v *Verifier) lookup(kid string) (signingKey, bool) {
+	if key, ok := v.keys[kid]; ok {
+		return key, true
+	}
+	// Tokens without kid are accepted when the set holds a single key
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
+
+// Authenticate implements auth.Authenticator for "Authorization: Bearer" headers.
+func (v *Verifier) Authenticate(ctx context.Context, header auth.Header) (auth.Principal, error) {
+	token, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return auth.Principal{}, auth.ErrNoCredentials
+	}
+
+	claims, err := v.Verify(ctx, token)
+	if err != nil {
+		return auth.Principal{}, auth.Unauthenticated(err)
+	}
+
+	// A principal without subject would be treated as anonymous
+	if subject, _ := claims["sub"].(string); subject == "" {
+		return auth.Principal{}, auth.Unauthenticated(errors.New("token has no subject"))
+	}
+
+	return principalFromClaims(claims), nil
+}
+
+// Verify checks the token's signature, issuer, audience and lifetime and
+// returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) validateClaims(claims map[string]any) error {
+	now := time.Now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(exp, 0).Add(v.leeway)) {
+		return errors.New("token is expired")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.leeway).Before(time.Unix(nbf, 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if iss, _ := claims["iss"].(string); iss != v.issuer {
+		return fmt.Errorf("unexpected issuer %q", iss)
+	}
+
+	var audiences []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = []string{aud}
+	case []any:
+		for _, value := range aud {
+			if s, ok := value.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	if !slices.Contains(audiences, v.audience) {
+		return fmt.Errorf("token is not issued for audience %q", v.audience)
+	}
+
+	return nil
+}
+
+// verifySignature checks signature with key. The algorithm must match the
+// JWK's alg, when set, and the key type and curve, so a key is never used
+// with an algorithm it was not issued for.
+func verifySignature(alg string, key signingKey, signed, signature []byte) error {
+	var (
+		hash  crypto.Hash
+		curve elliptic.Curve
+	)
+	switch alg {
+	case "RS256", "PS256":
+		hash = crypto.SHA256
+	case "RS384", "PS384":
+		hash = crypto.SHA384
+	case "RS512", "PS512":
+		hash = crypto.SHA512
+	case "ES256":
+		hash, curve = crypto.SHA256, elliptic.P256()
+	case "ES384":
+		hash, curve = crypto.SHA384, elliptic.P384()
+	case "ES512":
+		hash, curve = crypto.SHA512, elliptic.P521()
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	if key.alg != "" && key.alg != alg {
+		return fmt.Errorf("algorithm %s does not match key algorithm %s", alg, key.alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		publicKey, ok := key.key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s does not match key type", alg)
+		}
+		var err error
+		if alg[0] == 'R' {
+			err = rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(publicKey, hash, digest, signature, nil)
+		}
+		if err != nil {
+			return errors.New("invalid token signature")
+		}
+	case "ES":
+		publicKey, ok := key.key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s does not match key type", alg)
+		}
+		if publicKey.Curve != curve {
+			return fmt.Errorf("algorithm %s does not match key curve %s", alg, publicKey.Curve.Params().Name)
+		}
+		// JWS encodes ECDSA signatures as fixed size r || s
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func numericClaim(claims map[string]any, name string) (int64, bool) {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(value), true
+}
+
+// principalFromClaims maps scalar and string array claims to principal
+// attributes. Arrays are joined with spaces, like the OAuth scope claim.
+func principalFromClaims(claims map[string]any) auth.Principal {
+	attributes := make(map[string]string, len(claims))
+	for name, value := range claims {
+		switch value := value.(type) {
+		case string:
+			attributes[name] = value
+		case float64:
+			attributes[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		case bool:
+			attributes[name] = strconv.FormatBool(value)
+		case []any:
+			values := make([]string, 0, len(value))
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+			attributes[name] = strings.Join(values, " ")
+		}
+	}
+
+	subject, _ := claims["sub"].(string)
+	return auth.Principal{
+		Subject:    subject,
+		Method:     auth.MethodJWT,
+		Attributes: attributes,
+	}
+}
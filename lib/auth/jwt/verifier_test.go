@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.test"
+	testAudience = "draftstore"
+)
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+// newTestKeys writes a JWKS of an RSA key restricted to RS256, an unrestricted
+// RSA key and a P-256 key, and returns its path.
+func newTestKeys(t *testing.T) (testKeys, string) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+
+	rsaJWK := func(kid, alg string) jwk {
+		return jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Alg: alg,
+			N:   encodeBigInt(rsaKey.N),
+			E:   encodeBigInt(big.NewInt(int64(rsaKey.E))),
+		}
+	}
+	document, err := json.Marshal(map[string][]jwk{"keys": {
+		rsaJWK("rs256", "RS256"),
+		rsaJWK("rsa", ""),
+		{
+			Kty: "EC",
+			Kid: "ec",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+			Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+	}})
+	if err != nil {
+		t.Fatalf("failed to encode JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, document, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+	return testKeys{rsa: rsaKey, ec: ecKey}, path
+}
+
+// sign returns a token of claims signed with alg by the key kid.
+func (k testKeys) sign(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to encode token: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	var err error
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "PS256":
+		signature, err = rsa.SignPSS(rand.Reader, k.rsa, crypto.SHA256, digest[:], nil)
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	default:
+		signature = []byte("signature")
+	}
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"sub": "alice",
+		"iss": testIssuer,
+		"aud": testAudience,
+		"exp": now.Add(time.Hour).Unix(),
+		"iat": now.Unix(),
+	}
+}
+
+func TestVerifierVerify(t *testing.T) {
+	keys, path := newTestKeys(t)
+	verifier, err := NewVerifier(context.Background(), VerifierOptions{
+		Issuer:     testIssuer,
+		Audience:   testAudience,
+		JWKSSource: path,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	t.Cleanup(verifier.Close)
+
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		alg     string
+		kid     string
+		claims  map[string]any
+		wantErr string
+	}{
+		{name: "valid RS256", alg: "RS256", kid: "rs256", claims: validClaims()},
+		{name: "valid PS256", alg: "PS256", kid: "rsa", claims: validClaims()},
+		{name: "valid ES256", alg: "ES256", kid: "ec", claims: validClaims()},
+		{name: "audience in list", alg: "ES256", kid: "ec", claims: with("aud", []string{"other", testAudience})},
+		{name: "algorithm differs from key algorithm", alg: "PS256", kid: "rs256", claims: validClaims(), wantErr: "does not match key algorithm"},
+		{name: "RSA algorithm with EC key", alg: "RS256", kid: "ec", claims: validClaims(), wantErr: "does not match key type"},
+		{name: "EC algorithm with RSA key", alg: "ES256", kid: "rsa", claims: validClaims(), wantErr: "does not match key type"},
+		{name: "EC algorithm with other curve", alg: "ES384", kid: "ec", claims: validClaims(), wantErr: "does not match key curve"},
+		{name: "unsigned token", alg: "none", kid: "rsa", claims: validClaims(), wantErr: "unsupported algorithm"},
+		{name: "HMAC token", alg: "HS256", kid: "rsa", claims: validClaims(), wantErr: "unsupported algorithm"},
+		{name: "unknown key", alg: "ES256", kid: "missing", claims: validClaims(), wantErr: "unknown key"},
+		{name: "expired", alg: "ES256", kid: "ec", claims: with("exp", time.Now().Add(-time.Hour).Unix()), wantErr: "expired"},
+		{name: "expired within leeway", alg: "ES256", kid: "ec", claims: with("exp", time.Now().Add(-DefaultLeeway/2).Unix())},
+		{name: "no expiry", alg: "ES256", kid: "ec", claims: with("exp", nil), wantErr: "no expiry"},
+		{name: "not valid yet", alg: "ES256", kid: "ec", claims: with("nbf", time.Now().Add(time.Hour).Unix()), wantErr: "not valid yet"},
+		{name: "wrong issuer", alg: "ES256", kid: "ec", claims: with("iss", "https://other.test"), wantErr: "unexpected issuer"},
+		{name: "no issuer", alg: "ES256", kid: "ec", claims: with("iss", nil), wantErr: "unexpected issuer"},
+		{name: "wrong audience", alg: "ES256", kid: "ec", claims: with("aud", "other"), wantErr: "audience"},
+		{name: "no audience", alg: "ES256", kid: "ec", claims: with("aud", nil), wantErr: "audience"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := keys.sign(t, tt.alg, tt.kid, tt.claims)
+			_, err := verifier.Verify(context.Background(), token)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifierTamperedToken(t *testing.T) {
+	keys, path := newTestKeys(t)
+	verifier, err := NewVerifier(context.Background(), VerifierOptions{
+		Issuer:     testIssuer,
+		Audience:   testAudience,
+		JWKSSource: path,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	t.Cleanup(verifier.Close)
+
+	token := strings.Split(keys.sign(t, "RS256", "rs256", validClaims()), ".")
+	claims := validClaims()
+	claims["sub"] = "mallory"
+	data, _ := json.Marshal(claims)
+	token[1] = base64.RawURLEncoding.EncodeToString(data)
+
+	if _, err := verifier.Verify(context.Background(), strings.Join(token, ".")); err == nil || !strings.Contains(err.Error(), "invalid token signature") {
+		t.Fatalf("Verify() error = %v, want invalid token signature", err)
+	}
+}
+
+func TestNewVerifierRequiresIssuerAndAudience(t *testing.T) {
+	_, path := newTestKeys(t)
+
+	tests := []struct {
+		name string
+		opts VerifierOptions
+	}{
+		{name: "no issuer", opts: VerifierOptions{Audience: testAudience, JWKSSource: path}},
+		{name: "no audience", opts: VerifierOptions{Issuer: testIssuer, JWKSSource: path}},
+		{name: "neither", opts: VerifierOptions{JWKSSource: path}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewVerifier(context.Background(), tt.opts)
+			if err == nil {
+				verifier.Close()
+				t.Fatal("NewVerifier() succeeded, want error")
+			}
+		})
+	}
+}
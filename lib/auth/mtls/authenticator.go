@@ -0,0 +1,58 @@
+package mtls
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+)
+
+// Principal attributes of mTLS callers.
+const (
+	AttributeCommonName  = "cn"
+	AttributeSPIFFEID    = "spiffe_id"
+	AttributeTrustDomain = "trust_domain"
+)
+
+var _ auth.Authenticator = (*Authenticator)(nil)
+
+// Authenticator maps verified client certificates to principals. The subject
+// is the certificate's SPIFFE ID, or its common name when it has none.
+type Authenticator struct{}
+
+func NewAuthenticator() *Authenticator {
+	return &Authenticator{}
+}
+
+// Authenticate implements auth.Authenticator. It only considers certificates
+// verified during the TLS handshake.
+func (a *Authenticator) Authenticate(ctx context.Context, header auth.Header) (auth.Principal, error) {
+	state := auth.TLSStateFromContext(ctx)
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return auth.Principal{}, auth.ErrNoCredentials
+	}
+
+	return principalFromCertificate(state.VerifiedChains[0][0]), nil
+}
+
+func principalFromCertificate(cert *x509.Certificate) auth.Principal {
+	attributes := map[string]string{
+		AttributeCommonName: cert.Subject.CommonName,
+	}
+	subject := cert.Subject.CommonName
+
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" {
+			attributes[AttributeSPIFFEID] = uri.String()
+			attributes[AttributeTrustDomain] = uri.Host
+			subject = uri.String()
+			break
+		}
+	}
+
+	return auth.Principal{
+		Subject:    subject,
+		Method:     auth.MethodMTLS,
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,219 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const DefaultReloadInterval = 30 * time.Second
+
+// Client certificate policies.
+const (
+	// ClientAuthNone does not ask for client certificates.
+	ClientAuthNone = "none"
+	// ClientAuthOptional verifies client certificates when presented.
+	ClientAuthOptional = "optional"
+	// ClientAuthRequire rejects handshakes without a valid client certificate.
+	ClientAuthRequire = "require"
+)
+
+// Reloader serves a certificate and client CA bundle read from disk and
+// reloads them when the files change, so rotation needs no restart.
+type Reloader struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+	interval   time.Duration
+	mu         sync.RWMutex
+	cert       *tls.Certificate
+	clientCAs  *x509.CertPool
+	modTimes   [3]time.Time
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+type ReloaderOptions struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of CAs trusted to sign client certificates.
+	ClientCAFile string
+	// ClientAuth is ClientAuthNone, ClientAuthOptional or ClientAuthRequire.
+	// It defaults to ClientAuthRequire when ClientCAFile is set.
+	ClientAuth string
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration
+}
+
+func NewReloader(opts ReloaderOptions) (*Reloader, error) {
+	log := logger.GetServiceLogger("tls-reloader")
+
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("certificate and key files are required")
+	}
+	if opts.ReloadInterval <= 0 {
+		opts.ReloadInterval = DefaultReloadInterval
+	}
+	if opts.ClientAuth == "" {
+		opts.ClientAuth = ClientAuthNone
+		if opts.ClientCAFile != "" {
+			opts.ClientAuth = ClientAuthRequire
+		}
+	}
+
+	var clientAuth tls.ClientAuthType
+	switch opts.ClientAuth {
+	case ClientAuthNone:
+		clientAuth = tls.NoClientCert
+	case ClientAuthOptional:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth mode %q", opts.ClientAuth)
+	}
+	if clientAuth != tls.NoClientCert && opts.ClientCAFile == "" {
+		return nil, errors.New("a client CA file is required to verify client certificates")
+	}
+
+	r := &Reloader{
+		certFile:   opts.CertFile,
+		keyFile:    opts.KeyFile,
+		caFile:     opts.ClientCAFile,
+		clientAuth: clientAuth,
+		interval:   opts.ReloadInterval,
+		stop:       make(chan struct{}),
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	go r.reloadLoop()
+
+	log.Info().
+		Str("cert_file", r.certFile).
+		Str("client_ca_file", r.caFile).
+		Str("client_auth", opts.ClientAuth).
+		Dur("reload_interval", r.interval).
+		Msg("TLS reloader initialized")
+
+	return r, nil
+}
+
+// Close stops watching the files.
+func (r *Reloader) Close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+// VerifiesClients reports whether client certificates are verified.
+func (r *Reloader) VerifiesClients() bool {
+	return r.clientAuth != tls.NoClientCert
+}
+
+// ServerConfig returns a TLS configuration that always uses the latest
+// certificate and client CAs. nextProtos are the ALPN protocols to offer.
+func (r *Reloader) ServerConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		// The CA pool is only read from the config, so a fresh one is
+		// returned for every handshake
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    r.clientCAs,
+			}, nil
+		},
+	}
+}
+
+// reload reads the files if any of them changed and reports whether it did.
+// The previous certificates stay in use when reading fails.
+func (r *Reloader) reload() (bool, error) {
+	var modTimes [3]time.Time
+	for i, path := range []string{r.certFile, r.keyFile, r.caFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	r.mu.RLock()
+	unchanged := modTimes == r.modTimes
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.caFile != "" {
+		data, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return false, fmt.Errorf("no certificates found in %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *Reloader) reloadLoop() {
+	log := logger.GetServiceLogger("tls-reloader")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				// Keep serving with the previous certificates
+				log.Error().
+					Err(err).
+					Msg("Failed to reload TLS certificates")
+				continue
+			}
+			if reloaded {
+				log.Info().
+					Str("cert_file", r.certFile).
+					Str("client_ca_file", r.caFile).
+					Msg("TLS certificates reloaded")
+			}
+		}
+	}
+}
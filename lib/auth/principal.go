@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+)
+
+// Authentication methods of a Principal.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+	MethodMTLS   = "mtls"
+)
+
+// Principal identifies the caller of a request.
+type Principal struct {
+	// Subject is the caller's identity, e.g. a JWT subject, an API key ID or
+	// a client certificate's SPIFFE ID.
+	Subject string
+	// Method names how the caller authenticated, e.g. "jwt", "api_key" or "mtls".
+	Method string
+	// Attributes carries claims specific to the authentication method.
+	Attributes map[string]string
+}
+
+// Anonymous reports whether the principal carries no identity.
+func (p Principal) Anonymous() bool {
+	return p.Subject == ""
+}
+
+// LogFields returns the fields identifying the principal in logs. It never
+// contains credentials.
+func (p Principal) LogFields() map[string]string {
+	fields := map[string]string{
+		"subject":     p.Subject,
+		"auth_method": p.Method,
+	}
+	if p.Method == MethodAPIKey {
+		fields["api_key_id"] = p.Subject
+	}
+	return fields
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored in ctx. It returns the zero
+// Principal for unauthenticated requests.
+func FromContext(ctx context.Context) Principal {
+	principal, _ := ctx.Value(principalKey{}).(Principal)
+	return principal
+}
+
+type tlsStateKey struct{}
+
+// WithTLSState returns a copy of ctx carrying the TLS state of the connection
+// the request arrived on, so authenticators can inspect client certificates.
+func WithTLSState(ctx context.Context, state *tls.ConnectionState) context.Context {
+	return context.WithValue(ctx, tlsStateKey{}, state)
+}
+
+// TLSStateFromContext returns the TLS state stored in ctx, or nil for
+// plaintext connections.
+func TLSStateFromContext(ctx context.Context) *tls.ConnectionState {
+	state, _ := ctx.Value(tlsStateKey{}).(*tls.ConnectionState)
+	return state
+}
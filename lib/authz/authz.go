@@ -0,0 +1,48 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+)
+
+// Operations checked by an Authorizer.
+const (
+	OperationCreateBucket  = "create_bucket"
+	OperationUpload        = "upload"
+	OperationDownload      = "download"
+	OperationDraftDownload = "draft_download"
+	OperationConfirm       = "confirm"
+	OperationCancel        = "cancel"
+	// OperationAdmin covers administrative operations such as API key management.
+	OperationAdmin = "admin"
+)
+
+type Authorizer interface {
+	// Authorize reports whether the principal may perform the request.
+	Authorize(ctx context.Context, req Request) (bool, error)
+}
+
+// Request describes an operation to authorize.
+type Request struct {
+	Operation string
+	// Key is the object key the operation acts on. It is empty for bucket operations.
+	Key       string
+	Principal auth.Principal
+	// Fields holds operation specific request fields, keyed by their API names.
+	Fields map[string]any
+}
+
+// All allows a request only when every authorizer allows it.
+type All []Authorizer
+
+// Authorize implements Authorizer.
+func (a All) Authorize(ctx context.Context, req Request) (bool, error) {
+	for _, authorizer := range a {
+		allowed, err := authorizer.Authorize(ctx, req)
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
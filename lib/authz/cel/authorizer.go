@@ -0,0 +1,133 @@
+package cel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DefaultCostLimit bounds the evaluation cost of a single rule.
+	DefaultCostLimit = 10_000
+)
+
+var _ authz.Authorizer = (*Authorizer)(nil)
+
+// Authorizer allows a request when any of its CEL rules evaluates to true.
+type Authorizer struct {
+	programs []program
+}
+
+type program struct {
+	rule    string
+	program cel.Program
+}
+
+type AuthorizerOptions struct {
+	// Rules are boolean CEL expressions over the variables
+	//
+	//	op        string              the operation, e.g. "confirm"
+	//	key       string              the object key
+	//	principal map(string, dyn)    the caller's claims with "sub" and "method"
+	//	request   map(string, dyn)    the request fields
+	Rules     []string
+	CostLimit uint64
+}
+
+func NewAuthorizer(opts AuthorizerOptions) (*Authorizer, error) {
+	log := logger.GetServiceLogger("cel-authorizer")
+
+	if opts.CostLimit == 0 {
+		opts.CostLimit = DefaultCostLimit
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("op", cel.StringType),
+		cel.Variable("key", cel.StringType),
+		cel.Variable("principal", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	authorizer := &Authorizer{}
+	for _, rule := range opts.Rules {
+		ast, issues := env.Compile(rule)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile rule %q: %w", rule, issues.Err())
+		}
+		if ast.OutputType() != cel.BoolType {
+			return nil, fmt.Errorf("rule %q returns %s, not bool", rule, ast.OutputType())
+		}
+
+		prg, err := env.Program(ast, cel.CostLimit(opts.CostLimit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rule %q: %w", rule, err)
+		}
+		authorizer.programs = append(authorizer.programs, program{rule: rule, program: prg})
+	}
+
+	log.Info().
+		Int("rules", len(authorizer.programs)).
+		Uint64("cost_limit", opts.CostLimit).
+		Msg("CEL authorizer initialized")
+
+	return authorizer, nil
+}
+
+// Authorize implements authz.Authorizer. A rule that fails to evaluate, for
+// example by reading a claim the principal does not have, does not match.
+func (a *Authorizer) Authorize(ctx context.Context, req authz.Request) (bool, error) {
+	log := logger.GetServiceLogger("cel-authorizer").With().
+		Str("operation", req.Operation).
+		Str("object_name", req.Key).
+		Str("subject", req.Principal.Subject).
+		Logger()
+
+	principal := make(map[string]any, len(req.Principal.Attributes)+2)
+	for name, value := range req.Principal.Attributes {
+		principal[name] = value
+	}
+	principal["sub"] = req.Principal.Subject
+	principal["method"] = req.Principal.Method
+
+	fields := req.Fields
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
+	activation := map[string]any{
+		"op":        req.Operation,
+		"key":       req.Key,
+		"principal": principal,
+		"request":   fields,
+	}
+
+	for _, p := range a.programs {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		out, _, err := p.program.ContextEval(ctx, activation)
+		if err != nil {
+			log.Debug().
+				Err(err).
+				Str("rule", p.rule).
+				Msg("Rule evaluation failed")
+			continue
+		}
+		if allowed, ok := out.Value().(bool); ok && allowed {
+			log.Debug().
+				Str("rule", p.rule).
+				Msg("Request allowed by rule")
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
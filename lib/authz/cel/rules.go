@@ -0,0 +1,26 @@
+package cel
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadRules reads one rule per line from path. Blank lines and lines starting
+// with '#' are ignored.
+func LoadRules(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var rules []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, line)
+	}
+	return rules, nil
+}
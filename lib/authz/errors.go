@@ -0,0 +1,6 @@
+package authz
+
+import "errors"
+
+// ErrAccessDenied is returned when an authorizer denies an operation.
+var ErrAccessDenied = errors.New("access denied")
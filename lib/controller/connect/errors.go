@@ -0,0 +1,32 @@
+package connect
+
+import (
+	"errors"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowmerak/DraftStore/lib/controller/errormap"
+)
+
+// connectError converts a gRPC status error of the wrapped server into a
+// Connect error with the same code, message and details.
+func connectError(err error) error {
+	// Errors of the Connect stream itself pass through unchanged
+	if connectErr := new(connect.Error); errors.As(err, &connectErr) {
+		return connectErr
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		st = errormap.MapToStatus(err)
+	}
+
+	connectErr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, detail := range st.Proto().GetDetails() {
+		if errorDetail, dErr := connect.NewErrorDetail(detail); dErr == nil {
+			connectErr.AddDetail(errorDetail)
+		}
+	}
+	return connectErr
+}
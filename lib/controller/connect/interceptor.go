@@ -0,0 +1,69 @@
+package connect
+
+import (
+	"context"
+	"path"
+
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// ValidationInterceptor rejects requests and streamed messages violating the
+// protovalidate rules of their message with an invalid_argument error.
+func ValidationInterceptor(validator protovalidate.Validator) connect.Interceptor {
+	return &validationInterceptor{validator: validator}
+}
+
+type validationInterceptor struct {
+	validator protovalidate.Validator
+}
+
+func (i *validationInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.validate(ctx, req.Spec().Procedure, req.Any()); err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+func (i *validationInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *validationInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(ctx, &validatingConn{StreamingHandlerConn: conn, ctx: ctx, interceptor: i})
+	}
+}
+
+func (i *validationInterceptor) validate(ctx context.Context, procedure string, msg any) error {
+	if msg, ok := msg.(proto.Message); ok {
+		if err := i.validator.Validate(msg); err != nil {
+			log := logger.GetHandlerLogger(ctx, "connect", path.Base(procedure), procedure)
+			log.Warn().
+				Err(err).
+				Msg("Rejected invalid request")
+			return connectError(err)
+		}
+	}
+	return nil
+}
+
+// validatingConn validates every message received on a streaming call.
+type validatingConn struct {
+	connect.StreamingHandlerConn
+	ctx         context.Context
+	interceptor *validationInterceptor
+}
+
+func (c *validatingConn) Receive(msg any) error {
+	if err := c.StreamingHandlerConn.Receive(msg); err != nil {
+		return err
+	}
+	return c.interceptor.validate(c.ctx, c.Spec().Procedure, msg)
+}
@@ -0,0 +1,111 @@
+package connect
+
+import (
+	"context"
+	"net/http"
+
+	"buf.build/go/protovalidate"
+	"connectrpc.com/connect"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/gen/draft/v1/draftv1connect"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+var _ draftv1connect.DraftServiceHandler = (*Server)(nil)
+
+// Server serves the DraftService over the Connect, gRPC and gRPC-Web
+// protocols by delegating to the gRPC controller.
+type Server struct {
+	server    draftv1.DraftServiceServer
+	validator protovalidate.Validator
+}
+
+type ServerOptions struct {
+	// Server handles the calls, usually the gRPC controller.
+	Server draftv1.DraftServiceServer
+	// Validator enforces the protovalidate rules of requests.
+	Validator protovalidate.Validator
+}
+
+func NewServer(option ServerOptions) *Server {
+	log := logger.GetServiceLogger("connect-controller")
+
+	server := &Server{
+		server:    option.Server,
+		validator: option.Validator,
+	}
+
+	log.Info().Msg("Connect server controller initialized")
+	return server
+}
+
+// Handler returns the path prefix and the HTTP handler serving the DraftService.
+func (s *Server) Handler() (string, http.Handler) {
+	return draftv1connect.NewDraftServiceHandler(s, connect.WithInterceptors(ValidationInterceptor(s.validator)))
+}
+
+// CreateDraftBucket implements draftv1connect.DraftServiceHandler.
+func (s *Server) CreateDraftBucket(ctx context.Context, req *connect.Request[draftv1.CreateDraftBucketRequest]) (*connect.Response[draftv1.CreateDraftBucketResponse], error) {
+	res, err := s.server.CreateDraftBucket(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// GetUploadURL implements draftv1connect.DraftServiceHandler.
+func (s *Server) GetUploadURL(ctx context.Context, req *connect.Request[draftv1.GetUploadURLRequest]) (*connect.Response[draftv1.GetUploadURLResponse], error) {
+	res, err := s.server.GetUploadURL(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// GetDownloadURL implements draftv1connect.DraftServiceHandler.
+func (s *Server) GetDownloadURL(ctx context.Context, req *connect.Request[draftv1.GetDownloadURLRequest]) (*connect.Response[draftv1.GetDownloadURLResponse], error) {
+	res, err := s.server.GetDownloadURL(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// GetDraftDownloadURL implements draftv1connect.DraftServiceHandler.
+func (s *Server) GetDraftDownloadURL(ctx context.Context, req *connect.Request[draftv1.GetDraftDownloadURLRequest]) (*connect.Response[draftv1.GetDraftDownloadURLResponse], error) {
+	res, err := s.server.GetDraftDownloadURL(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// ConfirmUpload implements draftv1connect.DraftServiceHandler.
+func (s *Server) ConfirmUpload(ctx context.Context, req *connect.Request[draftv1.ConfirmUploadRequest]) (*connect.Response[draftv1.ConfirmUploadResponse], error) {
+	res, err := s.server.ConfirmUpload(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// CancelUpload implements draftv1connect.DraftServiceHandler.
+func (s *Server) CancelUpload(ctx context.Context, req *connect.Request[draftv1.CancelUploadRequest]) (*connect.Response[draftv1.CancelUploadResponse], error) {
+	res, err := s.server.CancelUpload(ctx, req.Msg)
+	if err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(res), nil
+}
+
+// IsStream reports whether r calls a streaming procedure, so that it can be
+// exempted from the request timeout.
+func IsStream(r *http.Request) bool {
+	switch r.URL.Path {
+	case draftv1connect.DraftServiceUploadDraftProcedure, draftv1connect.DraftServiceDownloadObjectProcedure:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,80 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/metadata"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+)
+
+// UploadDraft implements draftv1connect.DraftServiceHandler.
+func (s *Server) UploadDraft(ctx context.Context, stream *connect.ClientStream[draftv1.UploadDraftRequest]) (*connect.Response[draftv1.UploadDraftResponse], error) {
+	upload := &uploadDraftStream{serverStream: serverStream{ctx: ctx}, stream: stream}
+	if err := s.server.UploadDraft(upload); err != nil {
+		return nil, connectError(err)
+	}
+	return connect.NewResponse(upload.response), nil
+}
+
+// DownloadObject implements draftv1connect.DraftServiceHandler.
+func (s *Server) DownloadObject(ctx context.Context, req *connect.Request[draftv1.DownloadObjectRequest], stream *connect.ServerStream[draftv1.DownloadObjectResponse]) error {
+	if err := s.server.DownloadObject(req.Msg, &downloadObjectStream{serverStream: serverStream{ctx: ctx}, stream: stream}); err != nil {
+		return connectError(err)
+	}
+	return nil
+}
+
+// uploadDraftStream adapts a Connect client stream to the gRPC stream
+// expected by the wrapped server.
+type uploadDraftStream struct {
+	serverStream
+	stream   *connect.ClientStream[draftv1.UploadDraftRequest]
+	response *draftv1.UploadDraftResponse
+}
+
+func (s *uploadDraftStream) Recv() (*draftv1.UploadDraftRequest, error) {
+	if !s.stream.Receive() {
+		if err := s.stream.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return s.stream.Msg(), nil
+}
+
+func (s *uploadDraftStream) SendAndClose(res *draftv1.UploadDraftResponse) error {
+	s.response = res
+	return nil
+}
+
+// downloadObjectStream adapts a Connect server stream to the gRPC stream
+// expected by the wrapped server.
+type downloadObjectStream struct {
+	serverStream
+	stream *connect.ServerStream[draftv1.DownloadObjectResponse]
+}
+
+func (s *downloadObjectStream) Send(res *draftv1.DownloadObjectResponse) error {
+	return s.stream.Send(res)
+}
+
+// serverStream implements the grpc.ServerStream methods the wrapped server
+// does not use. Headers and trailers are left to Connect.
+type serverStream struct {
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context     { return s.ctx }
+func (s serverStream) SetHeader(metadata.MD) error  { return nil }
+func (s serverStream) SendHeader(metadata.MD) error { return nil }
+func (s serverStream) SetTrailer(metadata.MD)       {}
+func (s serverStream) SendMsg(any) error            { return errUntypedMessage }
+func (s serverStream) RecvMsg(any) error            { return errUntypedMessage }
+
+// errUntypedMessage is returned by the untyped stream methods, which the
+// typed adapters replace.
+var errUntypedMessage = errors.New("untyped stream messages are not supported")
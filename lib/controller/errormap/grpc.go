@@ -0,0 +1,113 @@
+package errormap
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+)
+
+const (
+	// ErrorDomain is the ErrorInfo domain of DraftStore errors.
+	ErrorDomain = "draftstore"
+	// DefaultRetryDelay is the delay suggested to clients retrying a
+	// retryable error.
+	DefaultRetryDelay = time.Second
+)
+
+// IsRetryable reports whether an operation failing with errorType may
+// succeed when retried unchanged.
+func IsRetryable(errorType draftv1.ErrorType) bool {
+	return errorType == draftv1.ErrorType_ERROR_TYPE_NETWORK_ERROR
+}
+
+// MapToGRPCCode maps a protobuf ErrorType to a gRPC status code
+func MapToGRPCCode(errorType draftv1.ErrorType) codes.Code {
+	switch errorType {
+	case draftv1.ErrorType_ERROR_TYPE_BUCKET_NOT_FOUND, draftv1.ErrorType_ERROR_TYPE_OBJECT_NOT_FOUND:
+		return codes.NotFound
+	case draftv1.ErrorType_ERROR_TYPE_ACCESS_DENIED:
+		return codes.PermissionDenied
+	case draftv1.ErrorType_ERROR_TYPE_NETWORK_ERROR:
+		return codes.Unavailable
+	case draftv1.ErrorType_ERROR_TYPE_STORAGE_QUOTA_EXCEEDED:
+		return codes.ResourceExhausted
+	case draftv1.ErrorType_ERROR_TYPE_INVALID_OBJECT_NAME,
+		draftv1.ErrorType_ERROR_TYPE_VALIDATION_FAILED,
+		draftv1.ErrorType_ERROR_TYPE_CHECKSUM_MISMATCH,
+		draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST:
+		return codes.InvalidArgument
+	case draftv1.ErrorType_ERROR_TYPE_INFECTED:
+		return codes.FailedPrecondition
+	case draftv1.ErrorType_ERROR_TYPE_BUCKET_ALREADY_EXISTS:
+		return codes.AlreadyExists
+	default:
+		return codes.Internal
+	}
+}
+
+// MapToStatus maps Go errors to a gRPC status carrying the ErrorType and
+// retryability as ErrorInfo and, for retryable errors, a RetryInfo detail.
+func MapToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	// The caller gave up; there is nothing to classify
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	}
+
+	errorType := MapToErrorType(err)
+	retryable := IsRetryable(errorType)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: errorType.String(),
+			Domain: ErrorDomain,
+			Metadata: map[string]string{
+				"retryable": strconv.FormatBool(retryable),
+			},
+		},
+	}
+	var validationErr *protovalidate.ValidationError
+	if errors.As(err, &validationErr) {
+		details = append(details, badRequest(validationErr))
+	}
+	if retryable {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(DefaultRetryDelay),
+		})
+	}
+
+	st := status.New(MapToGRPCCode(errorType), err.Error())
+	if withDetails, dErr := st.WithDetails(details...); dErr == nil {
+		return withDetails
+	}
+	return st
+}
+
+// badRequest lists the violations of a request as a BadRequest detail.
+func badRequest(err *protovalidate.ValidationError) *errdetails.BadRequest {
+	detail := &errdetails.BadRequest{}
+	for _, violation := range err.Violations {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       protovalidate.FieldPathString(violation.Proto.GetField()),
+			Description: violation.Proto.GetMessage(),
+			Reason:      violation.Proto.GetRuleId(),
+		})
+	}
+	return detail
+}
@@ -0,0 +1,53 @@
+package errormap
+
+import (
+	"errors"
+	"net/http"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+// MapToHTTPCode maps a protobuf ErrorType to an HTTP status code
+func MapToHTTPCode(errorType draftv1.ErrorType) int {
+	switch errorType {
+	case draftv1.ErrorType_ERROR_TYPE_UNSPECIFIED:
+		return http.StatusOK
+	case draftv1.ErrorType_ERROR_TYPE_BUCKET_NOT_FOUND, draftv1.ErrorType_ERROR_TYPE_OBJECT_NOT_FOUND:
+		return http.StatusNotFound
+	case draftv1.ErrorType_ERROR_TYPE_ACCESS_DENIED:
+		return http.StatusForbidden
+	case draftv1.ErrorType_ERROR_TYPE_NETWORK_ERROR:
+		return http.StatusServiceUnavailable
+	case draftv1.ErrorType_ERROR_TYPE_STORAGE_QUOTA_EXCEEDED:
+		return http.StatusTooManyRequests
+	case draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST,
+		draftv1.ErrorType_ERROR_TYPE_INVALID_OBJECT_NAME,
+		draftv1.ErrorType_ERROR_TYPE_VALIDATION_FAILED,
+		draftv1.ErrorType_ERROR_TYPE_CHECKSUM_MISMATCH,
+		draftv1.ErrorType_ERROR_TYPE_INFECTED:
+		return http.StatusBadRequest
+	case draftv1.ErrorType_ERROR_TYPE_BUCKET_ALREADY_EXISTS:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// MapToHTTPStatus maps Go errors to an HTTP status code. Objects over the
+// maximum size are reported as 413 although their ErrorType is
+// ERROR_TYPE_VALIDATION_FAILED. Resumable upload offset mismatches and drafts
+// replaced during confirmation are reported as 409 and locked uploads as 423
+// although their ErrorType is ERROR_TYPE_INVALID_REQUEST.
+func MapToHTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, draft.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, draft.ErrOffsetMismatch), errors.Is(err, storage.ErrPreconditionFailed):
+		return http.StatusConflict
+	case errors.Is(err, draft.ErrUploadLocked):
+		return http.StatusLocked
+	}
+	return MapToHTTPCode(MapToErrorType(err))
+}
@@ -1,9 +1,16 @@
 package errormap
 
 import (
+	"errors"
 	"strings"
 
+	"buf.build/go/protovalidate"
+
 	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/auth/apikey"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/tenant"
 )
 
 // MapToErrorType maps Go errors to protobuf ErrorType enum
@@ -12,6 +19,38 @@ func MapToErrorType(err error) draftv1.ErrorType {
 		return draftv1.ErrorType_ERROR_TYPE_UNSPECIFIED
 	}
 
+	// Typed errors take precedence over message matching
+	var validationErr *protovalidate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST
+	case errors.Is(err, draft.ErrValidationFailed):
+		return draftv1.ErrorType_ERROR_TYPE_VALIDATION_FAILED
+	case errors.Is(err, draft.ErrInfected):
+		return draftv1.ErrorType_ERROR_TYPE_INFECTED
+	case errors.Is(err, draft.ErrChecksumMismatch):
+		return draftv1.ErrorType_ERROR_TYPE_CHECKSUM_MISMATCH
+	case errors.Is(err, draft.ErrInvalidChecksum), errors.Is(err, draft.ErrInvalidSize),
+		errors.Is(err, draft.ErrOffsetMismatch), errors.Is(err, draft.ErrUploadLocked):
+		return draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST
+	case errors.Is(err, draft.ErrQuotaExceeded):
+		return draftv1.ErrorType_ERROR_TYPE_STORAGE_QUOTA_EXCEEDED
+	case errors.Is(err, draft.ErrInvalidObjectName):
+		return draftv1.ErrorType_ERROR_TYPE_INVALID_OBJECT_NAME
+	case errors.Is(err, draft.ErrRejected), errors.Is(err, draft.ErrAccessDenied), errors.Is(err, draft.ErrInvalidLink):
+		return draftv1.ErrorType_ERROR_TYPE_ACCESS_DENIED
+	case errors.Is(err, tenant.ErrUnknownTenant), errors.Is(err, tenant.ErrTenantRequired), errors.Is(err, tenant.ErrTenantMismatch):
+		return draftv1.ErrorType_ERROR_TYPE_ACCESS_DENIED
+	case errors.Is(err, draft.ErrUploadNotFound), errors.Is(err, apikey.ErrKeyNotFound):
+		return draftv1.ErrorType_ERROR_TYPE_OBJECT_NOT_FOUND
+	case errors.Is(err, apikey.ErrInvalidScope):
+		return draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST
+	case errors.Is(err, storage.ErrObjectNotFound):
+		return draftv1.ErrorType_ERROR_TYPE_OBJECT_NOT_FOUND
+	case errors.Is(err, storage.ErrPreconditionFailed):
+		return draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST
+	}
+
 	errMsg := strings.ToLower(err.Error())
 
 	switch {
@@ -0,0 +1,161 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/service/apikey"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+	"github.com/snowmerak/DraftStore/lib/util/protoconv"
+)
+
+type AdminServer struct {
+	draftv1.UnimplementedAdminServiceServer
+	apiKeyService *apikey.Service
+	legacyResults bool
+}
+
+type AdminServerOptions struct {
+	APIKeyService *apikey.Service
+	// LegacyResults reports failures in the response's Result with an OK status.
+	LegacyResults bool
+}
+
+func NewAdminServer(option AdminServerOptions) *AdminServer {
+	log := logger.GetServiceLogger("grpc-controller")
+
+	server := &AdminServer{
+		apiKeyService: option.APIKeyService,
+		legacyResults: option.LegacyResults,
+	}
+
+	log.Info().Msg("gRPC admin server controller initialized")
+
+	return server
+}
+
+// CreateAPIKey creates an API key and returns its secret once
+func (s *AdminServer) CreateAPIKey(ctx context.Context, req *draftv1.CreateAPIKeyRequest) (*draftv1.CreateAPIKeyResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "CreateAPIKey", "/draft.v1.AdminService/CreateAPIKey").With().
+		Str("name", req.Name).
+		Logger()
+
+	log.Info().Msg("Handling CreateAPIKey request")
+
+	key, secret, err := s.apiKeyService.CreateKey(ctx, apikey.CreateKeyOptions{
+		Name:   req.Name,
+		Scopes: protoconv.ToScopes(req.Scopes),
+		Prefix: req.KeyPrefix,
+		TTL:    time.Duration(req.TtlSeconds) * time.Second,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("CreateAPIKey operation failed")
+		if s.legacyResults {
+			return &draftv1.CreateAPIKeyResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	log.Info().
+		Str("key_id", key.ID).
+		Msg("CreateAPIKey operation completed successfully")
+	return &draftv1.CreateAPIKeyResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+		Key:    protoconv.FromAPIKey(key),
+		Secret: secret,
+	}, nil
+}
+
+// ListAPIKeys lists all API keys without their secrets
+func (s *AdminServer) ListAPIKeys(ctx context.Context, req *draftv1.ListAPIKeysRequest) (*draftv1.ListAPIKeysResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "ListAPIKeys", "/draft.v1.AdminService/ListAPIKeys")
+
+	log.Info().Msg("Handling ListAPIKeys request")
+
+	keys, err := s.apiKeyService.ListKeys(ctx)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("ListAPIKeys operation failed")
+		if s.legacyResults {
+			return &draftv1.ListAPIKeysResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	response := &draftv1.ListAPIKeysResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+	}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, protoconv.FromAPIKey(key))
+	}
+
+	log.Info().
+		Int("keys", len(keys)).
+		Msg("ListAPIKeys operation completed successfully")
+	return response, nil
+}
+
+// RevokeAPIKey disables an API key immediately
+func (s *AdminServer) RevokeAPIKey(ctx context.Context, req *draftv1.RevokeAPIKeyRequest) (*draftv1.RevokeAPIKeyResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "RevokeAPIKey", "/draft.v1.AdminService/RevokeAPIKey").With().
+		Str("key_id", req.Id).
+		Logger()
+
+	log.Info().Msg("Handling RevokeAPIKey request")
+
+	if err := s.apiKeyService.RevokeKey(ctx, req.Id); err != nil {
+		log.Error().
+			Err(err).
+			Msg("RevokeAPIKey operation failed")
+		if s.legacyResults {
+			return &draftv1.RevokeAPIKeyResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	log.Info().Msg("RevokeAPIKey operation completed successfully")
+	return &draftv1.RevokeAPIKeyResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+	}, nil
+}
+
+// RotateAPIKey replaces an API key and revokes the old one after a grace period
+func (s *AdminServer) RotateAPIKey(ctx context.Context, req *draftv1.RotateAPIKeyRequest) (*draftv1.RotateAPIKeyResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "RotateAPIKey", "/draft.v1.AdminService/RotateAPIKey").With().
+		Str("key_id", req.Id).
+		Logger()
+
+	log.Info().Msg("Handling RotateAPIKey request")
+
+	key, secret, err := s.apiKeyService.RotateKey(ctx, req.Id, time.Duration(req.GracePeriodSeconds)*time.Second)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("RotateAPIKey operation failed")
+		if s.legacyResults {
+			return &draftv1.RotateAPIKeyResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	log.Info().
+		Str("new_key_id", key.ID).
+		Msg("RotateAPIKey operation completed successfully")
+	return &draftv1.RotateAPIKeyResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+		Key:    protoconv.FromAPIKey(key),
+		Secret: secret,
+	}, nil
+}
@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/controller/errormap"
+)
+
+// statusError converts a service error into a gRPC status error carrying
+// its ErrorType and retry details.
+func statusError(err error) error {
+	return errormap.MapToStatus(err).Err()
+}
+
+// errorResult reports a service error in a response's Result for clients
+// relying on legacy results.
+func errorResult(err error) *draftv1.Result {
+	return &draftv1.Result{
+		Success:      false,
+		ErrorMessage: err.Error(),
+		ErrorType:    errormap.MapToErrorType(err),
+	}
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"buf.build/go/protovalidate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/tenant"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// metadataHeader adapts incoming gRPC metadata to auth.Header.
+type metadataHeader metadata.MD
+
+func (h metadataHeader) Get(name string) string {
+	if values := metadata.MD(h).Get(strings.ToLower(name)); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// AuthUnaryInterceptor resolves the caller with authenticator and stores the
+// principal in the request context. Calls without credentials are rejected
+// unless allowAnonymous is set.
+func AuthUnaryInterceptor(authenticator auth.Authenticator, allowAnonymous bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator, allowAnonymous, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the streaming counterpart of AuthUnaryInterceptor.
+func AuthStreamInterceptor(authenticator auth.Authenticator, allowAnonymous bool) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), authenticator, allowAnonymous, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate resolves the caller of fullMethod and returns ctx with its principal.
+func authenticate(ctx context.Context, authenticator auth.Authenticator, allowAnonymous bool, fullMethod string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			ctx = auth.WithTLSState(ctx, &tlsInfo.State)
+		}
+	}
+
+	principal, err := authenticator.Authenticate(ctx, metadataHeader(md))
+	switch {
+	case err == nil:
+		return logger.WithFields(auth.WithPrincipal(ctx, principal), principal.LogFields()), nil
+	case errors.Is(err, auth.ErrNoCredentials) && allowAnonymous:
+		return ctx, nil
+	default:
+		log := logger.GetHandlerLogger(ctx, "grpc", path.Base(fullMethod), fullMethod)
+		log.Warn().
+			Err(err).
+			Msg("Rejected unauthenticated request")
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+}
+
+// TenantUnaryInterceptor stores the tenant named by the tenant header in the
+// request context.
+func TenantUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withTenant(ctx), req)
+	}
+}
+
+// TenantStreamInterceptor is the streaming counterpart of TenantUnaryInterceptor.
+func TenantStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: stream, ctx: withTenant(stream.Context())})
+	}
+}
+
+// withTenant returns ctx with the tenant named by the tenant header, if any.
+func withTenant(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if id := metadataHeader(md).Get(tenant.Header); id != "" {
+		ctx = logger.WithFields(tenant.WithID(ctx, id), map[string]string{"tenant": id})
+	}
+	return ctx
+}
+
+// ValidationUnaryInterceptor rejects requests violating the protovalidate
+// rules of their message with an INVALID_ARGUMENT status.
+func ValidationUnaryInterceptor(validator protovalidate.Validator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if err := validator.Validate(msg); err != nil {
+				log := logger.GetHandlerLogger(ctx, "grpc", path.Base(info.FullMethod), info.FullMethod)
+				log.Warn().
+					Err(err).
+					Msg("Rejected invalid request")
+				return nil, statusError(err)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ValidationStreamInterceptor rejects streams carrying a message that
+// violates the protovalidate rules of its type with an INVALID_ARGUMENT status.
+func ValidationStreamInterceptor(validator protovalidate.Validator) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: stream, validator: validator, fullMethod: info.FullMethod})
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// validatingStream validates every message received on a server stream.
+type validatingStream struct {
+	grpc.ServerStream
+	validator  protovalidate.Validator
+	fullMethod string
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if msg, ok := m.(proto.Message); ok {
+		if err := s.validator.Validate(msg); err != nil {
+			log := logger.GetHandlerLogger(s.Context(), "grpc", path.Base(s.fullMethod), s.fullMethod)
+			log.Warn().
+				Err(err).
+				Msg("Rejected invalid request")
+			return statusError(err)
+		}
+	}
+	return nil
+}
@@ -3,34 +3,41 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"time"
 
 	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
 	"github.com/snowmerak/DraftStore/lib/service/draft"
-	"github.com/snowmerak/DraftStore/lib/util/errormap"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
+	"github.com/snowmerak/DraftStore/lib/util/protoconv"
 )
 
 type Server struct {
 	draftv1.UnimplementedDraftServiceServer
-	draftService *draft.Service
-	address      string
+	draftService  draft.API
+	address       string
+	legacyResults bool
 }
 
 type ServerOptions struct {
-	DraftService *draft.Service
+	DraftService draft.API
 	Address      string
+	// LegacyResults reports failures in the response's Result with an OK
+	// status, as before status codes were returned. Kept for old clients.
+	LegacyResults bool
 }
 
 func NewServer(option ServerOptions) *Server {
 	log := logger.GetServiceLogger("grpc-controller")
 
 	server := &Server{
-		draftService: option.DraftService,
-		address:      option.Address,
+		draftService:  option.DraftService,
+		address:       option.Address,
+		legacyResults: option.LegacyResults,
 	}
 
 	log.Info().
 		Str("address", server.address).
+		Bool("legacy_results", server.legacyResults).
 		Msg("gRPC server controller initialized")
 
 	return server
@@ -38,7 +45,7 @@ func NewServer(option ServerOptions) *Server {
 
 // CreateDraftBucket creates the necessary buckets for draft operations
 func (s *Server) CreateDraftBucket(ctx context.Context, req *draftv1.CreateDraftBucketRequest) (*draftv1.CreateDraftBucketResponse, error) {
-	log := logger.GetHandlerLogger("grpc", "CreateDraftBucket", "/draft.v1.DraftService/CreateDraftBucket")
+	log := logger.GetHandlerLogger(ctx, "grpc", "CreateDraftBucket", "/draft.v1.DraftService/CreateDraftBucket")
 
 	log.Info().Msg("Handling CreateDraftBucket request")
 
@@ -47,13 +54,10 @@ func (s *Server) CreateDraftBucket(ctx context.Context, req *draftv1.CreateDraft
 		log.Error().
 			Err(err).
 			Msg("CreateDraftBucket operation failed")
-		return &draftv1.CreateDraftBucketResponse{
-			Result: &draftv1.Result{
-				Success:      false,
-				ErrorMessage: err.Error(),
-				ErrorType:    errormap.MapToErrorType(err),
-			},
-		}, nil
+		if s.legacyResults {
+			return &draftv1.CreateDraftBucketResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
 	}
 
 	log.Info().Msg("CreateDraftBucket operation completed successfully")
@@ -66,57 +70,62 @@ func (s *Server) CreateDraftBucket(ctx context.Context, req *draftv1.CreateDraft
 
 // GetUploadURL generates a presigned URL for uploading files to the draft bucket
 func (s *Server) GetUploadURL(ctx context.Context, req *draftv1.GetUploadURLRequest) (*draftv1.GetUploadURLResponse, error) {
-	log := logger.GetHandlerLogger("grpc", "GetUploadURL", "/draft.v1.DraftService/GetUploadURL").With().
+	log := logger.GetHandlerLogger(ctx, "grpc", "GetUploadURL", "/draft.v1.DraftService/GetUploadURL").With().
 		Str("object_name", req.ObjectName).
 		Logger()
 
 	log.Info().Msg("Handling GetUploadURL request")
 
-	url, err := s.draftService.GetUploadURL(ctx, req.ObjectName)
+	uploadURL, err := s.draftService.GetUploadURL(ctx, req.ObjectName, draft.UploadURLOptions{
+		Checksum: protoconv.ToChecksum(req.ChecksumAlgorithm, req.Checksum),
+		Size:     req.Size,
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Msg("GetUploadURL operation failed")
-		return &draftv1.GetUploadURLResponse{
-			Result: &draftv1.Result{
-				Success:      false,
-				ErrorMessage: err.Error(),
-				ErrorType:    errormap.MapToErrorType(err),
-			},
-		}, nil
+		if s.legacyResults {
+			return &draftv1.GetUploadURLResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
 	}
 
 	log.Info().
-		Str("url_length", fmt.Sprintf("%d", len(url))).
+		Str("url_length", fmt.Sprintf("%d", len(uploadURL.URL))).
 		Msg("GetUploadURL operation completed successfully")
 	return &draftv1.GetUploadURLResponse{
 		Result: &draftv1.Result{
 			Success: true,
 		},
-		Url: url,
+		Url:             uploadURL.URL,
+		RequiredHeaders: uploadURL.RequiredHeaders,
+		ObjectName:      uploadURL.ObjectName,
 	}, nil
 }
 
 // GetDownloadURL generates a presigned URL for downloading files from the main bucket
 func (s *Server) GetDownloadURL(ctx context.Context, req *draftv1.GetDownloadURLRequest) (*draftv1.GetDownloadURLResponse, error) {
-	log := logger.GetHandlerLogger("grpc", "GetDownloadURL", "/draft.v1.DraftService/GetDownloadURL").With().
+	log := logger.GetHandlerLogger(ctx, "grpc", "GetDownloadURL", "/draft.v1.DraftService/GetDownloadURL").With().
 		Str("object_name", req.ObjectName).
 		Logger()
 
 	log.Info().Msg("Handling GetDownloadURL request")
 
-	url, err := s.draftService.GetDownloadURL(ctx, req.ObjectName)
+	url, err := s.draftService.GetDownloadURL(ctx, req.ObjectName, draft.DownloadURLOptions{
+		TTL:                        time.Duration(req.TtlSeconds) * time.Second,
+		Variant:                    req.Variant,
+		ResponseContentDisposition: req.ResponseContentDisposition,
+		ResponseContentType:        req.ResponseContentType,
+		ResponseCacheControl:       req.ResponseCacheControl,
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Msg("GetDownloadURL operation failed")
-		return &draftv1.GetDownloadURLResponse{
-			Result: &draftv1.Result{
-				Success:      false,
-				ErrorMessage: err.Error(),
-				ErrorType:    errormap.MapToErrorType(err),
-			},
-		}, nil
+		if s.legacyResults {
+			return &draftv1.GetDownloadURLResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
 	}
 
 	log.Info().
@@ -130,32 +139,90 @@ func (s *Server) GetDownloadURL(ctx context.Context, req *draftv1.GetDownloadURL
 	}, nil
 }
 
+// GetDraftDownloadURL generates a presigned URL for previewing files in the draft bucket
+func (s *Server) GetDraftDownloadURL(ctx context.Context, req *draftv1.GetDraftDownloadURLRequest) (*draftv1.GetDraftDownloadURLResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "GetDraftDownloadURL", "/draft.v1.DraftService/GetDraftDownloadURL").With().
+		Str("object_name", req.ObjectName).
+		Logger()
+
+	log.Info().Msg("Handling GetDraftDownloadURL request")
+
+	url, err := s.draftService.GetDraftDownloadURL(ctx, req.ObjectName)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("GetDraftDownloadURL operation failed")
+		if s.legacyResults {
+			return &draftv1.GetDraftDownloadURLResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	log.Info().
+		Str("url_length", fmt.Sprintf("%d", len(url))).
+		Msg("GetDraftDownloadURL operation completed successfully")
+	return &draftv1.GetDraftDownloadURLResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+		Url: url,
+	}, nil
+}
+
 // ConfirmUpload moves a file from draft bucket to main bucket
 func (s *Server) ConfirmUpload(ctx context.Context, req *draftv1.ConfirmUploadRequest) (*draftv1.ConfirmUploadResponse, error) {
-	log := logger.GetHandlerLogger("grpc", "ConfirmUpload", "/draft.v1.DraftService/ConfirmUpload").With().
+	log := logger.GetHandlerLogger(ctx, "grpc", "ConfirmUpload", "/draft.v1.DraftService/ConfirmUpload").With().
 		Str("object_name", req.ObjectName).
 		Logger()
 
 	log.Info().Msg("Handling ConfirmUpload request")
 
-	err := s.draftService.ConfirmUpload(ctx, req.ObjectName)
+	objectName, err := s.draftService.ConfirmUpload(ctx, req.ObjectName, draft.ConfirmUploadOptions{
+		Checksum: protoconv.ToChecksum(req.ChecksumAlgorithm, req.Checksum),
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Msg("ConfirmUpload operation failed")
-		return &draftv1.ConfirmUploadResponse{
-			Result: &draftv1.Result{
-				Success:      false,
-				ErrorMessage: err.Error(),
-				ErrorType:    errormap.MapToErrorType(err),
-			},
-		}, nil
+		if s.legacyResults {
+			return &draftv1.ConfirmUploadResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
 	}
 
-	log.Info().Msg("ConfirmUpload operation completed successfully")
+	log.Info().
+		Str("stored_object_name", objectName).
+		Msg("ConfirmUpload operation completed successfully")
 	return &draftv1.ConfirmUploadResponse{
 		Result: &draftv1.Result{
 			Success: true,
 		},
+		ObjectName: objectName,
+	}, nil
+}
+
+// CancelUpload deletes an unconfirmed file and releases its quota reservation
+func (s *Server) CancelUpload(ctx context.Context, req *draftv1.CancelUploadRequest) (*draftv1.CancelUploadResponse, error) {
+	log := logger.GetHandlerLogger(ctx, "grpc", "CancelUpload", "/draft.v1.DraftService/CancelUpload").With().
+		Str("object_name", req.ObjectName).
+		Logger()
+
+	log.Info().Msg("Handling CancelUpload request")
+
+	if err := s.draftService.CancelUpload(ctx, req.ObjectName); err != nil {
+		log.Error().
+			Err(err).
+			Msg("CancelUpload operation failed")
+		if s.legacyResults {
+			return &draftv1.CancelUploadResponse{Result: errorResult(err)}, nil
+		}
+		return nil, statusError(err)
+	}
+
+	log.Info().Msg("CancelUpload operation completed successfully")
+	return &draftv1.CancelUploadResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
 	}, nil
 }
@@ -0,0 +1,163 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+	"github.com/snowmerak/DraftStore/lib/util/protoconv"
+)
+
+// downloadChunkSize is the size of the content chunks sent by DownloadObject.
+const downloadChunkSize = 64 << 10
+
+// UploadDraft streams a file through the server into the draft bucket
+func (s *Server) UploadDraft(stream draftv1.DraftService_UploadDraftServer) error {
+	ctx := stream.Context()
+	log := logger.GetHandlerLogger(ctx, "grpc", "UploadDraft", draftv1.DraftService_UploadDraft_FullMethodName)
+
+	first, err := stream.Recv()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Failed to receive upload metadata")
+		return err
+	}
+	metadata := first.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the upload metadata")
+	}
+
+	log = log.With().
+		Str("object_name", metadata.ObjectName).
+		Int64("size", metadata.Size).
+		Logger()
+
+	log.Info().Msg("Handling UploadDraft request")
+
+	objectName, err := s.draftService.UploadDraft(ctx, metadata.ObjectName, &chunkReader{recv: stream.Recv}, metadata.Size, draft.UploadOptions{
+		ContentType: metadata.ContentType,
+		Checksum:    protoconv.ToChecksum(metadata.ChecksumAlgorithm, metadata.Checksum),
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("UploadDraft operation failed")
+		if s.legacyResults {
+			return stream.SendAndClose(&draftv1.UploadDraftResponse{Result: errorResult(err)})
+		}
+		return statusError(err)
+	}
+
+	log.Info().
+		Str("draft_object_name", objectName).
+		Msg("UploadDraft operation completed successfully")
+	return stream.SendAndClose(&draftv1.UploadDraftResponse{
+		Result: &draftv1.Result{
+			Success: true,
+		},
+		ObjectName: objectName,
+		Size:       metadata.Size,
+	})
+}
+
+// DownloadObject streams a file, or a byte range of it, from the main bucket
+func (s *Server) DownloadObject(req *draftv1.DownloadObjectRequest, stream draftv1.DraftService_DownloadObjectServer) error {
+	ctx := stream.Context()
+	log := logger.GetHandlerLogger(ctx, "grpc", "DownloadObject", draftv1.DraftService_DownloadObject_FullMethodName).With().
+		Str("object_name", req.ObjectName).
+		Int64("offset", req.Offset).
+		Int64("length", req.Length).
+		Logger()
+
+	log.Info().Msg("Handling DownloadObject request")
+
+	object, err := s.draftService.OpenObject(ctx, req.ObjectName, draft.OpenObjectOptions{
+		Variant: req.Variant,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("DownloadObject operation failed")
+		return statusError(err)
+	}
+
+	body, err := object.Range(ctx, req.Offset, req.Length)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("DownloadObject operation failed")
+		return statusError(err)
+	}
+	defer body.Close()
+
+	response := &draftv1.DownloadObjectResponse{
+		Metadata: &draftv1.ObjectMetadata{
+			Size:             object.Size,
+			ContentType:      object.ContentType,
+			Etag:             object.ETag,
+			LastModifiedUnix: object.LastModified.Unix(),
+		},
+	}
+	buf := make([]byte, downloadChunkSize)
+	var sent int64
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 || response.Metadata != nil {
+			response.Chunk = buf[:n]
+			if sErr := stream.Send(response); sErr != nil {
+				log.Warn().
+					Err(sErr).
+					Int64("sent", sent).
+					Msg("Failed to send object chunk")
+				return sErr
+			}
+			sent += int64(n)
+			response = &draftv1.DownloadObjectResponse{}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			log.Error().
+				Err(err).
+				Int64("sent", sent).
+				Msg("Failed to read object")
+			return statusError(fmt.Errorf("failed to read object: %w", err))
+		}
+	}
+
+	log.Info().
+		Int64("sent", sent).
+		Msg("DownloadObject operation completed successfully")
+	return nil
+}
+
+// chunkReader reads the content chunks of an UploadDraft stream.
+type chunkReader struct {
+	recv  func() (*draftv1.UploadDraftRequest, error)
+	chunk []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetMetadata() != nil {
+			return 0, status.Error(codes.InvalidArgument, "upload metadata may only be sent first")
+		}
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
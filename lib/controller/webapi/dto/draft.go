@@ -6,16 +6,31 @@ import (
 
 // Re-export protobuf types for API usage
 type (
-	ErrorType                 = draftv1.ErrorType
-	Result                    = draftv1.Result
-	CreateDraftBucketRequest  = draftv1.CreateDraftBucketRequest
-	CreateDraftBucketResponse = draftv1.CreateDraftBucketResponse
-	GetUploadURLRequest       = draftv1.GetUploadURLRequest
-	GetUploadURLResponse      = draftv1.GetUploadURLResponse
-	GetDownloadURLRequest     = draftv1.GetDownloadURLRequest
-	GetDownloadURLResponse    = draftv1.GetDownloadURLResponse
-	ConfirmUploadRequest      = draftv1.ConfirmUploadRequest
-	ConfirmUploadResponse     = draftv1.ConfirmUploadResponse
+	ErrorType                   = draftv1.ErrorType
+	Result                      = draftv1.Result
+	CreateDraftBucketRequest    = draftv1.CreateDraftBucketRequest
+	CreateDraftBucketResponse   = draftv1.CreateDraftBucketResponse
+	GetUploadURLRequest         = draftv1.GetUploadURLRequest
+	GetUploadURLResponse        = draftv1.GetUploadURLResponse
+	GetDownloadURLRequest       = draftv1.GetDownloadURLRequest
+	GetDownloadURLResponse      = draftv1.GetDownloadURLResponse
+	GetDraftDownloadURLRequest  = draftv1.GetDraftDownloadURLRequest
+	GetDraftDownloadURLResponse = draftv1.GetDraftDownloadURLResponse
+	ConfirmUploadRequest        = draftv1.ConfirmUploadRequest
+	ConfirmUploadResponse       = draftv1.ConfirmUploadResponse
+	CancelUploadRequest         = draftv1.CancelUploadRequest
+	CancelUploadResponse        = draftv1.CancelUploadResponse
+	UploadDraftMetadata         = draftv1.UploadDraftMetadata
+	DownloadObjectRequest       = draftv1.DownloadObjectRequest
+	CreateAPIKeyRequest         = draftv1.CreateAPIKeyRequest
+	CreateAPIKeyResponse        = draftv1.CreateAPIKeyResponse
+	ListAPIKeysRequest          = draftv1.ListAPIKeysRequest
+	ListAPIKeysResponse         = draftv1.ListAPIKeysResponse
+	RevokeAPIKeyRequest         = draftv1.RevokeAPIKeyRequest
+	RevokeAPIKeyResponse        = draftv1.RevokeAPIKeyResponse
+	RotateAPIKeyRequest         = draftv1.RotateAPIKeyRequest
+	RotateAPIKeyResponse        = draftv1.RotateAPIKeyResponse
+	APIKey                      = draftv1.APIKey
 )
 
 // Error type constants for easier access
@@ -32,4 +47,8 @@ const (
 	ErrorTypeDeleteFailed         = draftv1.ErrorType_ERROR_TYPE_DELETE_FAILED
 	ErrorTypePresignedURLFailed   = draftv1.ErrorType_ERROR_TYPE_PRESIGNED_URL_FAILED
 	ErrorTypeInternalError        = draftv1.ErrorType_ERROR_TYPE_INTERNAL_ERROR
+	ErrorTypeValidationFailed     = draftv1.ErrorType_ERROR_TYPE_VALIDATION_FAILED
+	ErrorTypeInfected             = draftv1.ErrorType_ERROR_TYPE_INFECTED
+	ErrorTypeChecksumMismatch     = draftv1.ErrorType_ERROR_TYPE_CHECKSUM_MISMATCH
+	ErrorTypeInvalidRequest       = draftv1.ErrorType_ERROR_TYPE_INVALID_REQUEST
 )
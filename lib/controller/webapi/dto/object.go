@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+// CreateDraftRequest is the body of POST /api/v2/drafts.
+type CreateDraftRequest struct {
+	Key string `json:"key"`
+	// ChecksumAlgorithm is SHA256, CRC32C or MD5. Empty when no checksum is declared.
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`
+	Size              int64  `json:"size,omitempty"`
+}
+
+// CreateDraftResponse is the body of a draft created by POST /api/v2/drafts.
+type CreateDraftResponse struct {
+	Key             string            `json:"key"`
+	UploadURL       string            `json:"upload_url"`
+	RequiredHeaders map[string]string `json:"required_headers,omitempty"`
+}
+
+// Draft describes an unconfirmed draft returned by GET /api/v2/drafts/{key}.
+type Draft struct {
+	Key            string    `json:"key"`
+	Size           int64     `json:"size"`
+	ContentType    string    `json:"content_type,omitempty"`
+	LastModified   time.Time `json:"last_modified"`
+	ChecksumSHA256 string    `json:"checksum_sha256,omitempty"`
+	ChecksumCRC32C string    `json:"checksum_crc32c,omitempty"`
+}
+
+// UploadDraftResponse is the body of a draft uploaded by PUT /api/v2/drafts/{key}.
+type UploadDraftResponse struct {
+	// Key is where the draft was stored, which differs from the request key
+	// when a hook rewrote it.
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// ConfirmDraftRequest is the optional body of POST /api/v2/drafts/{key}:confirm.
+type ConfirmDraftRequest struct {
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`
+}
+
+// ConfirmDraftResponse is the body of POST /api/v2/drafts/{key}:confirm.
+type ConfirmDraftResponse struct {
+	// Key is where the object was stored, which differs from the draft key
+	// when a hook rewrote it.
+	Key string `json:"key"`
+}
+
+// CreateLinkRequest is the optional body of POST /api/v2/objects/{key}:link.
+type CreateLinkRequest struct {
+	// TTLSeconds is the lifetime of the link. Zero issues the longest link allowed.
+	TTLSeconds int64 `json:"ttl_seconds,omitempty"`
+}
+
+// Link is a signed download link to an object.
+type Link struct {
+	// URL is the path and query of the link, relative to the server.
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
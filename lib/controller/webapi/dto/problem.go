@@ -0,0 +1,19 @@
+package dto
+
+// ProblemContentType is the media type of Problem bodies.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details body returned for failed requests.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// ErrorType is the name of the ErrorType of the failure.
+	ErrorType string `json:"error_type"`
+	// Retryable reports whether the request may succeed when retried unchanged.
+	Retryable bool `json:"retryable"`
+	// RequestID identifies the request in the server logs.
+	RequestID string `json:"request_id,omitempty"`
+}
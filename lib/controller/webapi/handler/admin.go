@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/service/apikey"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+	"github.com/snowmerak/DraftStore/lib/util/protoconv"
+)
+
+type AdminHandler struct {
+	apiKeyService *apikey.Service
+}
+
+func NewAdminHandler(apiKeyService *apikey.Service) *AdminHandler {
+	log := logger.GetServiceLogger("webapi-handler")
+
+	handler := &AdminHandler{
+		apiKeyService: apiKeyService,
+	}
+
+	log.Info().Msg("WebAPI admin handler initialized")
+	return handler
+}
+
+// CreateAPIKey handles POST /api/v1/admin/api-key/create
+func (h *AdminHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/admin/api-key/create")
+	ctx := r.Context()
+
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("name", req.Name).
+		Msg("Handling CreateAPIKey request")
+
+	key, secret, err := h.apiKeyService.CreateKey(ctx, apikey.CreateKeyOptions{
+		Name:   req.Name,
+		Scopes: protoconv.ToScopes(req.Scopes),
+		Prefix: req.KeyPrefix,
+		TTL:    time.Duration(req.TtlSeconds) * time.Second,
+	})
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("CreateAPIKey operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.CreateAPIKeyResponse{
+		Result: &dto.Result{Success: true},
+	}
+
+	response.Key = protoconv.FromAPIKey(key)
+	response.Secret = secret
+	log.Info().
+		Str("key_id", key.ID).
+		Msg("CreateAPIKey operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// ListAPIKeys handles POST /api/v1/admin/api-key/list
+func (h *AdminHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/admin/api-key/list")
+	ctx := r.Context()
+
+	log.Info().Msg("Handling ListAPIKeys request")
+
+	keys, err := h.apiKeyService.ListKeys(ctx)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("ListAPIKeys operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.ListAPIKeysResponse{
+		Result: &dto.Result{Success: true},
+	}
+
+	for _, key := range keys {
+		response.Keys = append(response.Keys, protoconv.FromAPIKey(key))
+	}
+	log.Info().
+		Int("keys", len(keys)).
+		Msg("ListAPIKeys operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// RevokeAPIKey handles POST /api/v1/admin/api-key/revoke
+func (h *AdminHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/admin/api-key/revoke")
+	ctx := r.Context()
+
+	var req dto.RevokeAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("key_id", req.Id).
+		Msg("Handling RevokeAPIKey request")
+
+	err := h.apiKeyService.RevokeKey(ctx, req.Id)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("key_id", req.Id).
+			Msg("RevokeAPIKey operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.RevokeAPIKeyResponse{
+		Result: &dto.Result{Success: true},
+	}
+
+	log.Info().
+		Str("key_id", req.Id).
+		Msg("RevokeAPIKey operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// RotateAPIKey handles POST /api/v1/admin/api-key/rotate
+func (h *AdminHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/admin/api-key/rotate")
+	ctx := r.Context()
+
+	var req dto.RotateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("key_id", req.Id).
+		Msg("Handling RotateAPIKey request")
+
+	key, secret, err := h.apiKeyService.RotateKey(ctx, req.Id, time.Duration(req.GracePeriodSeconds)*time.Second)
+
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("key_id", req.Id).
+			Msg("RotateAPIKey operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.RotateAPIKeyResponse{
+		Result: &dto.Result{Success: true},
+	}
+
+	response.Key = protoconv.FromAPIKey(key)
+	response.Secret = secret
+	log.Info().
+		Str("key_id", req.Id).
+		Str("new_key_id", key.ID).
+		Msg("RotateAPIKey operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// RegisterRoutes registers all admin routes
+func (h *AdminHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/v1/admin", func(r chi.Router) {
+		r.Post("/api-key/create", h.CreateAPIKey)
+		r.Post("/api-key/list", h.ListAPIKeys)
+		r.Post("/api-key/revoke", h.RevokeAPIKey)
+		r.Post("/api-key/rotate", h.RotateAPIKey)
+	})
+}
@@ -1,25 +1,33 @@
 package handler
 
 import (
-	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
+	"buf.build/go/protovalidate"
 	"github.com/go-chi/chi/v5"
-	"github.com/snowmerak/DraftStore/lib/controller/webapi/converter"
 	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
 	"github.com/snowmerak/DraftStore/lib/service/draft"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
+	"github.com/snowmerak/DraftStore/lib/util/protoconv"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type DraftHandler struct {
-	draftService *draft.Service
+	draftService draft.API
+	validator    protovalidate.Validator
 }
 
-func NewDraftHandler(draftService *draft.Service) *DraftHandler {
+func NewDraftHandler(draftService draft.API, validator protovalidate.Validator) *DraftHandler {
 	log := logger.GetServiceLogger("webapi-handler")
 
 	handler := &DraftHandler{
 		draftService: draftService,
+		validator:    validator,
 	}
 
 	log.Info().Msg("WebAPI draft handler initialized")
@@ -28,52 +36,46 @@ func NewDraftHandler(draftService *draft.Service) *DraftHandler {
 
 // CreateDraftBucket handles POST /api/v1/draft/bucket
 func (h *DraftHandler) CreateDraftBucket(w http.ResponseWriter, r *http.Request) {
-	log := logger.GetHandlerLogger("http", "POST", "/api/v1/draft/bucket")
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/bucket")
 	ctx := r.Context()
 
 	log.Info().Msg("Handling CreateDraftBucket request")
 
 	err := h.draftService.CreateDraftBucket(ctx)
-	result := converter.ConvertErrorToResult(err)
-
-	response := &dto.CreateDraftBucketResponse{
-		Result: result,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
 	if err != nil {
 		log.Error().
 			Err(err).
 			Msg("CreateDraftBucket operation failed")
-		w.WriteHeader(http.StatusInternalServerError)
-	} else {
-		log.Info().Msg("CreateDraftBucket operation completed successfully")
-		w.WriteHeader(http.StatusOK)
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.CreateDraftBucketResponse{
+		Result: &dto.Result{Success: true},
 	}
 
-	json.NewEncoder(w).Encode(response)
+	log.Info().Msg("CreateDraftBucket operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
 }
 
 // GetUploadURL handles POST /api/v1/draft/upload-url
 func (h *DraftHandler) GetUploadURL(w http.ResponseWriter, r *http.Request) {
-	log := logger.GetHandlerLogger("http", "POST", "/api/v1/draft/upload-url")
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/upload-url")
 	ctx := r.Context()
 
 	var req dto.GetUploadURLRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeMessage(w, r, &req); err != nil {
 		log.Error().
 			Err(err).
 			Msg("Failed to decode request body")
-		result := &dto.Result{
-			Success:      false,
-			ErrorMessage: "Invalid request body",
-			ErrorType:    dto.ErrorTypeInternalError,
-		}
-		response := &dto.GetUploadURLResponse{Result: result}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
 		return
 	}
 
@@ -81,51 +83,50 @@ func (h *DraftHandler) GetUploadURL(w http.ResponseWriter, r *http.Request) {
 		Str("object_name", req.ObjectName).
 		Msg("Handling GetUploadURL request")
 
-	url, err := h.draftService.GetUploadURL(ctx, req.ObjectName)
-	result := converter.ConvertErrorToResult(err)
-
-	response := &dto.GetUploadURLResponse{
-		Result: result,
-		Url:    url,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
+	uploadURL, err := h.draftService.GetUploadURL(ctx, req.ObjectName, draft.UploadURLOptions{
+		Checksum: protoconv.ToChecksum(req.ChecksumAlgorithm, req.Checksum),
+		Size:     req.Size,
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("object_name", req.ObjectName).
 			Msg("GetUploadURL operation failed")
-		w.WriteHeader(http.StatusInternalServerError)
-	} else {
-		log.Info().
-			Str("object_name", req.ObjectName).
-			Msg("GetUploadURL operation completed successfully")
-		w.WriteHeader(http.StatusOK)
+		respond.Error(w, r, err)
+		return
 	}
 
-	json.NewEncoder(w).Encode(response)
+	response := &dto.GetUploadURLResponse{
+		Result:          &dto.Result{Success: true},
+		Url:             uploadURL.URL,
+		RequiredHeaders: uploadURL.RequiredHeaders,
+		ObjectName:      uploadURL.ObjectName,
+	}
+
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("GetUploadURL operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
 }
 
 // GetDownloadURL handles POST /api/v1/draft/download-url
 func (h *DraftHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
-	log := logger.GetHandlerLogger("http", "POST", "/api/v1/draft/download-url")
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/download-url")
 	ctx := r.Context()
 
 	var req dto.GetDownloadURLRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeMessage(w, r, &req); err != nil {
 		log.Error().
 			Err(err).
 			Msg("Failed to decode request body")
-		result := &dto.Result{
-			Success:      false,
-			ErrorMessage: "Invalid request body",
-			ErrorType:    dto.ErrorTypeInternalError,
-		}
-		response := &dto.GetDownloadURLResponse{Result: result}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
 		return
 	}
 
@@ -133,51 +134,97 @@ func (h *DraftHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
 		Str("object_name", req.ObjectName).
 		Msg("Handling GetDownloadURL request")
 
-	url, err := h.draftService.GetDownloadURL(ctx, req.ObjectName)
-	result := converter.ConvertErrorToResult(err)
+	url, err := h.draftService.GetDownloadURL(ctx, req.ObjectName, draft.DownloadURLOptions{
+		TTL:                        time.Duration(req.TtlSeconds) * time.Second,
+		Variant:                    req.Variant,
+		ResponseContentDisposition: req.ResponseContentDisposition,
+		ResponseContentType:        req.ResponseContentType,
+		ResponseCacheControl:       req.ResponseCacheControl,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", req.ObjectName).
+			Msg("GetDownloadURL operation failed")
+		respond.Error(w, r, err)
+		return
+	}
 
 	response := &dto.GetDownloadURLResponse{
-		Result: result,
+		Result: &dto.Result{Success: true},
 		Url:    url,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("GetDownloadURL operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
+}
+
+// GetDraftDownloadURL handles POST /api/v1/draft/draft-download-url
+func (h *DraftHandler) GetDraftDownloadURL(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/draft-download-url")
+	ctx := r.Context()
+
+	var req dto.GetDraftDownloadURLRequest
+	if err := decodeMessage(w, r, &req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("Handling GetDraftDownloadURL request")
+
+	url, err := h.draftService.GetDraftDownloadURL(ctx, req.ObjectName)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("object_name", req.ObjectName).
-			Msg("GetDownloadURL operation failed")
-		w.WriteHeader(http.StatusInternalServerError)
-	} else {
-		log.Info().
-			Str("object_name", req.ObjectName).
-			Msg("GetDownloadURL operation completed successfully")
-		w.WriteHeader(http.StatusOK)
+			Msg("GetDraftDownloadURL operation failed")
+		respond.Error(w, r, err)
+		return
 	}
 
-	json.NewEncoder(w).Encode(response)
+	response := &dto.GetDraftDownloadURLResponse{
+		Result: &dto.Result{Success: true},
+		Url:    url,
+	}
+
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("GetDraftDownloadURL operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
 }
 
 // ConfirmUpload handles POST /api/v1/draft/confirm
 func (h *DraftHandler) ConfirmUpload(w http.ResponseWriter, r *http.Request) {
-	log := logger.GetHandlerLogger("http", "POST", "/api/v1/draft/confirm")
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/confirm")
 	ctx := r.Context()
 
 	var req dto.ConfirmUploadRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeMessage(w, r, &req); err != nil {
 		log.Error().
 			Err(err).
 			Msg("Failed to decode request body")
-		result := &dto.Result{
-			Success:      false,
-			ErrorMessage: "Invalid request body",
-			ErrorType:    dto.ErrorTypeInternalError,
-		}
-		response := &dto.ConfirmUploadResponse{Result: result}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
 		return
 	}
 
@@ -185,28 +232,72 @@ func (h *DraftHandler) ConfirmUpload(w http.ResponseWriter, r *http.Request) {
 		Str("object_name", req.ObjectName).
 		Msg("Handling ConfirmUpload request")
 
-	err := h.draftService.ConfirmUpload(ctx, req.ObjectName)
-	result := converter.ConvertErrorToResult(err)
+	objectName, err := h.draftService.ConfirmUpload(ctx, req.ObjectName, draft.ConfirmUploadOptions{
+		Checksum: protoconv.ToChecksum(req.ChecksumAlgorithm, req.Checksum),
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", req.ObjectName).
+			Msg("ConfirmUpload operation failed")
+		respond.Error(w, r, err)
+		return
+	}
 
 	response := &dto.ConfirmUploadResponse{
-		Result: result,
+		Result:     &dto.Result{Success: true},
+		ObjectName: objectName,
+	}
+
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("ConfirmUpload operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
+}
+
+// CancelUpload handles POST /api/v1/draft/cancel
+func (h *DraftHandler) CancelUpload(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v1/draft/cancel")
+	ctx := r.Context()
+
+	var req dto.CancelUploadRequest
+	if err := decodeMessage(w, r, &req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("Handling CancelUpload request")
+
+	err := h.draftService.CancelUpload(ctx, req.ObjectName)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Str("object_name", req.ObjectName).
-			Msg("ConfirmUpload operation failed")
-		w.WriteHeader(http.StatusInternalServerError)
-	} else {
-		log.Info().
-			Str("object_name", req.ObjectName).
-			Msg("ConfirmUpload operation completed successfully")
-		w.WriteHeader(http.StatusOK)
+			Msg("CancelUpload operation failed")
+		respond.Error(w, r, err)
+		return
 	}
 
-	json.NewEncoder(w).Encode(response)
+	response := &dto.CancelUploadResponse{
+		Result: &dto.Result{Success: true},
+	}
+
+	log.Info().
+		Str("object_name", req.ObjectName).
+		Msg("CancelUpload operation completed successfully")
+	respond.Message(w, http.StatusOK, response)
 }
 
 // RegisterRoutes registers all draft-related routes
@@ -215,6 +306,23 @@ func (h *DraftHandler) RegisterRoutes(r chi.Router) {
 		r.Post("/bucket", h.CreateDraftBucket)
 		r.Post("/upload-url", h.GetUploadURL)
 		r.Post("/download-url", h.GetDownloadURL)
+		r.Post("/draft-download-url", h.GetDraftDownloadURL)
 		r.Post("/confirm", h.ConfirmUpload)
+		r.Post("/cancel", h.CancelUpload)
 	})
 }
+
+// maxMessageSize bounds the request bodies read by decodeMessage.
+const maxMessageSize = 1 << 20
+
+// decodeMessage decodes the protobuf JSON request body into msg. Both proto
+// and JSON field names are accepted, and 64-bit integers may be numbers or
+// strings as in the OpenAPI spec. Bodies over maxMessageSize fail with an
+// *http.MaxBytesError.
+func decodeMessage(w http.ResponseWriter, r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, msg)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/go-chi/chi/v5"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/tenant"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// LinkPath is the path prefix of download links.
+const LinkPath = "/o/"
+
+// Query parameters of signed download links.
+const (
+	linkExpiresParam   = "expires"
+	linkSignatureParam = "signature"
+	linkTenantParam    = "tenant"
+)
+
+// LinkHandler serves download links that redirect to presigned URLs, so that
+// objects can be embedded in web pages.
+type LinkHandler struct {
+	draftService   draft.API
+	validator      protovalidate.Validator
+	allowAnonymous bool
+}
+
+func NewLinkHandler(draftService draft.API, validator protovalidate.Validator, allowAnonymous bool) *LinkHandler {
+	log := logger.GetServiceLogger("webapi-handler")
+
+	handler := &LinkHandler{
+		draftService:   draftService,
+		validator:      validator,
+		allowAnonymous: allowAnonymous,
+	}
+
+	log.Info().
+		Bool("allow_anonymous", allowAnonymous).
+		Msg("WebAPI link handler initialized")
+	return handler
+}
+
+// GetLink handles GET /o/{key}
+func (h *LinkHandler) GetLink(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", r.Method, "/o/{key}")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, chi.URLParam(r, "*"))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	var token draft.LinkToken
+	if signature := query.Get(linkSignatureParam); signature != "" {
+		expires, err := strconv.ParseInt(query.Get(linkExpiresParam), 10, 64)
+		if err != nil {
+			respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid %s: %q", linkExpiresParam, query.Get(linkExpiresParam)))
+			return
+		}
+		token = draft.LinkToken{Expires: time.Unix(expires, 0), Signature: signature}
+	}
+
+	// Embedded links cannot send the tenant header, so signed links carry the tenant
+	if id := query.Get(linkTenantParam); id != "" && tenant.IDFromContext(ctx) == "" {
+		ctx = tenant.WithID(ctx, id)
+	}
+
+	link, err := h.draftService.ResolveLink(ctx, key, draft.LinkOptions{
+		Token:          token,
+		AllowAnonymous: h.allowAnonymous,
+	})
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("object_name", key).
+			Msg("GetLink operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	// Browsers may reuse the redirect until the presigned URL is refreshed
+	maxAge := max(int64(time.Until(link.RefreshAt).Seconds()), 0)
+	if link.Public {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", maxAge))
+		w.Header().Set("Vary", "Authorization, Cookie")
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Int64("max_age", maxAge).
+		Msg("GetLink operation completed successfully")
+	http.Redirect(w, r, link.URL, http.StatusTemporaryRedirect)
+}
+
+// RegisterRoutes registers the download link routes
+func (h *LinkHandler) RegisterRoutes(r chi.Router) {
+	r.Get(LinkPath+"*", h.GetLink)
+	r.Head(LinkPath+"*", h.GetLink)
+}
+
+// signedLinkURL returns the path and query of a signed link to key.
+func signedLinkURL(key string, link draft.SignedLink) string {
+	query := url.Values{}
+	query.Set(linkExpiresParam, strconv.FormatInt(link.Expires.Unix(), 10))
+	query.Set(linkSignatureParam, link.Signature)
+	if link.Tenant != "" {
+		query.Set(linkTenantParam, link.Tenant)
+	}
+	return LinkPath + key + "?" + query.Encode()
+}
@@ -0,0 +1,457 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/go-chi/chi/v5"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	draftsPath  = "/api/v2/drafts/"
+	objectsPath = "/api/v2/objects/"
+	// confirmAction is appended to a draft key to confirm it.
+	confirmAction = ":confirm"
+	// linkAction is appended to an object key to sign a download link to it.
+	linkAction = ":link"
+)
+
+// ObjectHandler serves drafts and objects as resources under /api/v2. Keys
+// may contain slashes and are taken from the rest of the path.
+type ObjectHandler struct {
+	draftService draft.API
+	validator    protovalidate.Validator
+}
+
+func NewObjectHandler(draftService draft.API, validator protovalidate.Validator) *ObjectHandler {
+	log := logger.GetServiceLogger("webapi-handler")
+
+	handler := &ObjectHandler{
+		draftService: draftService,
+		validator:    validator,
+	}
+
+	log.Info().Msg("WebAPI object handler initialized")
+	return handler
+}
+
+// CreateDraft handles POST /api/v2/drafts
+func (h *ObjectHandler) CreateDraft(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v2/drafts")
+	ctx := r.Context()
+
+	var req dto.CreateDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if err := h.validator.Validate(&dto.GetUploadURLRequest{ObjectName: req.Key, Size: req.Size}); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+	algorithm, err := parseChecksumAlgorithm(req.ChecksumAlgorithm)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", req.Key).
+		Msg("Handling CreateDraft request")
+
+	uploadURL, err := h.draftService.GetUploadURL(ctx, req.Key, draft.UploadURLOptions{
+		Checksum: storage.Checksum{Algorithm: algorithm, Value: req.Checksum},
+		Size:     req.Size,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", req.Key).
+			Msg("CreateDraft operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.CreateDraftResponse{
+		Key:             uploadURL.ObjectName,
+		UploadURL:       uploadURL.URL,
+		RequiredHeaders: uploadURL.RequiredHeaders,
+	}
+
+	log.Info().
+		Str("object_name", uploadURL.ObjectName).
+		Msg("CreateDraft operation completed successfully")
+	w.Header().Set("Location", draftsPath+escapeKey(uploadURL.ObjectName))
+	respond.JSON(w, http.StatusCreated, response)
+}
+
+// GetDraft handles GET /api/v2/drafts/{key}
+func (h *ObjectHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", r.Method, "/api/v2/drafts/{key}")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, chi.URLParam(r, "*"))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("Handling GetDraft request")
+
+	info, err := h.draftService.StatDraft(ctx, key)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("GetDraft operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	// The metadata changes with the draft content, so its ETag is reused
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if info.ETag != "" {
+		etag := `"` + strings.Trim(info.ETag, `"`) + `"`
+		w.Header().Set("ETag", etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			log.Info().
+				Str("object_name", key).
+				Msg("Draft not modified")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	response := &dto.Draft{
+		Key:            info.ObjectName,
+		Size:           info.Size,
+		ContentType:    info.ContentType,
+		LastModified:   info.LastModified,
+		ChecksumSHA256: info.ChecksumSHA256,
+		ChecksumCRC32C: info.ChecksumCRC32C,
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("GetDraft operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// ConfirmDraft handles POST /api/v2/drafts/{key}:confirm
+func (h *ObjectHandler) ConfirmDraft(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v2/drafts/{key}:confirm")
+	ctx := r.Context()
+
+	path, ok := strings.CutSuffix(chi.URLParam(r, "*"), confirmAction)
+	if !ok {
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		respond.Problem(w, r, http.StatusMethodNotAllowed, dto.ErrorTypeInvalidRequest, "drafts are confirmed with POST "+draftsPath+"{key}"+confirmAction)
+		return
+	}
+	key, err := objectKey(h.validator, path)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	// The body is optional and only declares the expected checksum
+	var req dto.ConfirmDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	algorithm, err := parseChecksumAlgorithm(req.ChecksumAlgorithm)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("Handling ConfirmDraft request")
+
+	destName, err := h.draftService.ConfirmUpload(ctx, key, draft.ConfirmUploadOptions{
+		Checksum: storage.Checksum{Algorithm: algorithm, Value: req.Checksum},
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("ConfirmDraft operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.ConfirmDraftResponse{
+		Key: destName,
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Str("dest_object_name", destName).
+		Msg("ConfirmDraft operation completed successfully")
+	w.Header().Set("Location", objectsPath+escapeKey(destName))
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// CancelDraft handles DELETE /api/v2/drafts/{key}
+func (h *ObjectHandler) CancelDraft(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "DELETE", "/api/v2/drafts/{key}")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, chi.URLParam(r, "*"))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("Handling CancelDraft request")
+
+	if err := h.draftService.CancelUpload(ctx, key); err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("CancelDraft operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("CancelDraft operation completed successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetObject handles GET /api/v2/objects/{key} by redirecting to a presigned
+// download URL. The variant, ttl_seconds, response_content_disposition,
+// response_content_type and response_cache_control query parameters
+// customize the URL.
+func (h *ObjectHandler) GetObject(w http.ResponseWriter, r *http.Request) {
+	if path, ok := strings.CutSuffix(chi.URLParam(r, "*"), contentAction); ok {
+		h.GetObjectContent(w, r, path)
+		return
+	}
+
+	log := logger.GetHandlerLogger(r.Context(), "http", "GET", "/api/v2/objects/{key}")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, chi.URLParam(r, "*"))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	req := dto.GetDownloadURLRequest{
+		ObjectName:                 key,
+		Variant:                    query.Get("variant"),
+		ResponseContentDisposition: query.Get("response_content_disposition"),
+		ResponseContentType:        query.Get("response_content_type"),
+		ResponseCacheControl:       query.Get("response_cache_control"),
+	}
+	if ttl := query.Get("ttl_seconds"); ttl != "" {
+		req.TtlSeconds, err = strconv.ParseInt(ttl, 10, 64)
+		if err != nil {
+			respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid ttl_seconds: %q", ttl))
+			return
+		}
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("Handling GetObject request")
+
+	url, err := h.draftService.GetDownloadURL(ctx, key, draft.DownloadURLOptions{
+		TTL:                        time.Duration(req.TtlSeconds) * time.Second,
+		Variant:                    req.Variant,
+		ResponseContentDisposition: req.ResponseContentDisposition,
+		ResponseContentType:        req.ResponseContentType,
+		ResponseCacheControl:       req.ResponseCacheControl,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("GetObject operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("GetObject operation completed successfully")
+	// Presigned URLs expire, so the redirect itself must not be cached
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// CreateLink handles POST /api/v2/objects/{key}:link
+func (h *ObjectHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", "/api/v2/objects/{key}:link")
+	ctx := r.Context()
+
+	path, ok := strings.CutSuffix(chi.URLParam(r, "*"), linkAction)
+	if !ok {
+		w.Header().Set("Allow", "GET")
+		respond.Problem(w, r, http.StatusMethodNotAllowed, dto.ErrorTypeInvalidRequest, "links are signed with POST "+objectsPath+"{key}"+linkAction)
+		return
+	}
+	key, err := objectKey(h.validator, path)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	// The body is optional and only shortens the link lifetime
+	var req dto.CreateLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error().
+			Err(err).
+			Msg("Failed to decode request body")
+		respond.InvalidBody(w, r, err)
+		return
+	}
+	if req.TTLSeconds < 0 {
+		respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid ttl_seconds: %d", req.TTLSeconds))
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("Handling CreateLink request")
+
+	link, err := h.draftService.SignLink(ctx, key, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("CreateLink operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.Link{
+		URL:       signedLinkURL(key, link),
+		ExpiresAt: link.Expires,
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("CreateLink operation completed successfully")
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// RegisterRoutes registers all resource routes
+func (h *ObjectHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Post("/drafts", h.CreateDraft)
+		r.Get("/drafts/*", h.GetDraft)
+		r.Head("/drafts/*", h.GetDraft)
+		r.Put("/drafts/*", h.UploadDraft)
+		r.Post("/drafts/*", h.ConfirmDraft)
+		r.Delete("/drafts/*", h.CancelDraft)
+		r.Get("/objects/*", h.GetObject)
+		r.Head("/objects/*", h.GetObject)
+		r.Post("/objects/*", h.CreateLink)
+	})
+}
+
+// objectKey unescapes a key taken from the request path and validates it
+// against the object_key rules.
+func objectKey(validator protovalidate.Validator, path string) (string, error) {
+	key, err := url.PathUnescape(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", draft.ErrInvalidObjectName, err)
+	}
+	if err := validator.Validate(&dto.CancelUploadRequest{ObjectName: key}); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// escapeKey escapes every segment of key for use in a resource path, keeping
+// the slashes between them.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// parseChecksumAlgorithm converts a checksum algorithm name. An empty name
+// declares no checksum.
+func parseChecksumAlgorithm(name string) (storage.ChecksumAlgorithm, error) {
+	switch algorithm := storage.ChecksumAlgorithm(strings.ToUpper(name)); algorithm {
+	case storage.ChecksumAlgorithmNone, storage.ChecksumAlgorithmSHA256, storage.ChecksumAlgorithmCRC32C, storage.ChecksumAlgorithmMD5:
+		return algorithm, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported algorithm %q", draft.ErrInvalidChecksum, name)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison of RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
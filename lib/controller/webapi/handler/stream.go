@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// contentAction is appended to an object key to stream its content.
+	contentAction = ":content"
+	// Headers declaring the checksum of a streamed upload.
+	checksumAlgorithmHeader = "X-Checksum-Algorithm"
+	checksumHeader          = "X-Checksum"
+)
+
+// inlineContentTypes are rendered inline by GetObjectContent. Every other
+// type is served as an attachment, so that uploaded HTML or SVG never renders
+// on the API origin, where requests may carry the link cookie.
+var inlineContentTypes = []string{
+	"image/avif",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"audio/*",
+	"video/*",
+	"text/plain",
+}
+
+// IsStream reports whether r streams file content through the server, so
+// that it can be exempted from the request timeout.
+func IsStream(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPatch:
+		return strings.HasPrefix(r.URL.Path, UploadsPath+"/")
+	case http.MethodPost:
+		return r.URL.Path == UploadsPath && r.Header.Get("Content-Type") == tusContentType
+	case http.MethodPut:
+		return strings.HasPrefix(r.URL.Path, draftsPath)
+	case http.MethodGet, http.MethodHead:
+		return strings.HasPrefix(r.URL.Path, objectsPath) && strings.HasSuffix(r.URL.Path, contentAction)
+	default:
+		return false
+	}
+}
+
+// UploadDraft handles PUT /api/v2/drafts/{key} by streaming the body into
+// the draft. The body must declare its size with Content-Length.
+func (h *ObjectHandler) UploadDraft(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "PUT", "/api/v2/drafts/{key}")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, chi.URLParam(r, "*"))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+	if r.ContentLength < 0 {
+		respond.Problem(w, r, http.StatusLengthRequired, dto.ErrorTypeInvalidRequest, "drafts are uploaded with a Content-Length")
+		return
+	}
+	algorithm, err := parseChecksumAlgorithm(r.Header.Get(checksumAlgorithmHeader))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Int64("size", r.ContentLength).
+		Msg("Handling UploadDraft request")
+
+	// The size and checksum are checked before the body is read, so rejected
+	// clients waiting for 100 Continue never send it
+	draftName, err := h.draftService.UploadDraft(ctx, key, r.Body, r.ContentLength, draft.UploadOptions{
+		ContentType: r.Header.Get("Content-Type"),
+		Checksum:    storage.Checksum{Algorithm: algorithm, Value: r.Header.Get(checksumHeader)},
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("UploadDraft operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	response := &dto.UploadDraftResponse{
+		Key:  draftName,
+		Size: r.ContentLength,
+	}
+
+	log.Info().
+		Str("object_name", draftName).
+		Msg("UploadDraft operation completed successfully")
+	w.Header().Set("Location", draftsPath+escapeKey(draftName))
+	respond.JSON(w, http.StatusCreated, response)
+}
+
+// GetObjectContent handles GET /api/v2/objects/{key}:content by streaming
+// the object. Range and conditional requests are served by http.ServeContent.
+func (h *ObjectHandler) GetObjectContent(w http.ResponseWriter, r *http.Request, path string) {
+	log := logger.GetHandlerLogger(r.Context(), "http", r.Method, "/api/v2/objects/{key}:content")
+	ctx := r.Context()
+
+	key, err := objectKey(h.validator, path)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+	variant := r.URL.Query().Get("variant")
+	if err := h.validator.Validate(&dto.DownloadObjectRequest{ObjectName: key, Variant: variant}); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Str("range", r.Header.Get("Range")).
+		Msg("Handling GetObjectContent request")
+
+	object, err := h.draftService.OpenObject(ctx, key, draft.OpenObjectOptions{
+		Variant: variant,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("GetObjectContent operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	content := &objectContent{ctx: ctx, object: object}
+	defer content.Close()
+
+	contentType := object.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if !inlineContentType(contentType) {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": key[strings.LastIndex(key, "/")+1:],
+		}))
+	}
+	if object.ETag != "" {
+		w.Header().Set("ETag", `"`+strings.Trim(object.ETag, `"`)+`"`)
+	}
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeContent(w, r, "", object.LastModified, content)
+
+	if content.err != nil {
+		log.Error().
+			Err(content.err).
+			Str("object_name", key).
+			Msg("GetObjectContent stream failed")
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Msg("GetObjectContent operation completed successfully")
+}
+
+// inlineContentType reports whether contentType is on inlineContentTypes.
+func inlineContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range inlineContentTypes {
+		if allowed == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// objectContent adapts an opened object to the io.ReadSeeker expected by
+// http.ServeContent. Every seek that moves the position reopens the object
+// at the new offset, so only the requested ranges are read from storage.
+type objectContent struct {
+	ctx    context.Context
+	object *draft.ObjectReader
+	offset int64
+	body   io.ReadCloser
+	err    error
+}
+
+func (c *objectContent) Read(p []byte) (int, error) {
+	if c.body == nil {
+		body, err := c.object.Range(c.ctx, c.offset, 0)
+		if err != nil {
+			c.err = err
+			return 0, err
+		}
+		c.body = body
+	}
+
+	n, err := c.body.Read(p)
+	c.offset += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+	return n, err
+}
+
+func (c *objectContent) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += c.offset
+	case io.SeekEnd:
+		offset += c.object.Size
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position: %d", offset)
+	}
+
+	if offset != c.offset {
+		c.Close()
+		c.offset = offset
+	}
+	return offset, nil
+}
+
+func (c *objectContent) Close() error {
+	if c.body == nil {
+		return nil
+	}
+	err := c.body.Close()
+	c.body = nil
+	return err
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"buf.build/go/protovalidate"
+	"github.com/go-chi/chi/v5"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// UploadsPath is the tus endpoint creating resumable uploads. Every upload is
+// served under it by ID.
+const UploadsPath = "/api/v2/uploads"
+
+const (
+	// tusVersion is the only tus protocol version served.
+	tusVersion = "1.0.0"
+	// tusExtensions are the tus extensions served besides the core protocol.
+	tusExtensions = "creation,creation-with-upload,termination,expiration"
+	// tusContentType is the content type of PATCH requests.
+	tusContentType = "application/offset+octet-stream"
+)
+
+// Upload-Metadata keys naming the draft and its content type. tus clients
+// commonly send filename and filetype, so they are accepted as fallbacks.
+var (
+	uploadKeyMetadata         = []string{"key", "filename"}
+	uploadContentTypeMetadata = []string{"content_type", "filetype"}
+)
+
+// UploadHandler serves resumable uploads with the tus 1.0 protocol. A
+// complete upload leaves a draft that is confirmed like any other.
+type UploadHandler struct {
+	draftService draft.API
+	validator    protovalidate.Validator
+	maxSize      int64
+}
+
+// NewUploadHandler creates the tus handler. maxSize is advertised to clients
+// as Tus-Max-Size when positive.
+func NewUploadHandler(draftService draft.API, validator protovalidate.Validator, maxSize int64) *UploadHandler {
+	log := logger.GetServiceLogger("webapi-handler")
+
+	handler := &UploadHandler{
+		draftService: draftService,
+		validator:    validator,
+		maxSize:      maxSize,
+	}
+
+	log.Info().
+		Int64("max_size", maxSize).
+		Msg("WebAPI upload handler initialized")
+	return handler
+}
+
+// Options handles OPTIONS /api/v2/uploads by describing the tus server.
+func (h *UploadHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	if h.maxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(h.maxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateUpload handles POST /api/v2/uploads. The draft key is taken from the
+// key or filename metadata. A body of type application/offset+octet-stream
+// is appended to the new upload.
+func (h *UploadHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "POST", UploadsPath)
+	ctx := r.Context()
+
+	if r.Header.Get("Upload-Defer-Length") != "" {
+		respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, "deferred upload lengths are not supported")
+		return
+	}
+	size, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid Upload-Length: %q", r.Header.Get("Upload-Length")))
+		return
+	}
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, err.Error())
+		return
+	}
+	key := firstMetadata(metadata, uploadKeyMetadata)
+	contentType := firstMetadata(metadata, uploadContentTypeMetadata)
+	if err := h.validator.Validate(&dto.UploadDraftMetadata{ObjectName: key, ContentType: contentType, Size: size}); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected invalid request")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("object_name", key).
+		Int64("size", size).
+		Msg("Handling CreateUpload request")
+
+	upload, err := h.draftService.CreateUpload(ctx, key, size, draft.CreateUploadOptions{
+		ContentType: contentType,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("object_name", key).
+			Msg("CreateUpload operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", UploadsPath+"/"+upload.ID)
+	if r.Header.Get("Content-Type") == tusContentType && r.ContentLength != 0 {
+		id := upload.ID
+		upload, err = h.draftService.WriteUpload(ctx, id, 0, r.Body)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("upload_id", id).
+				Msg("CreateUpload operation failed to write content")
+			respond.Error(w, r, err)
+			return
+		}
+		w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	}
+
+	log.Info().
+		Str("upload_id", upload.ID).
+		Str("object_name", upload.ObjectName).
+		Msg("CreateUpload operation completed successfully")
+	setUploadExpires(w, upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// GetUpload handles HEAD /api/v2/uploads/{id} by reporting the offset the
+// client resumes from.
+func (h *UploadHandler) GetUpload(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "HEAD", UploadsPath+"/{id}")
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	upload, err := h.draftService.GetUpload(ctx, id)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("upload_id", id).
+			Msg("GetUpload operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Size, 10))
+	if len(upload.Metadata) > 0 {
+		w.Header().Set("Upload-Metadata", formatUploadMetadata(upload.Metadata))
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	setUploadExpires(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+// WriteUpload handles PATCH /api/v2/uploads/{id} by appending the body at
+// the offset given by Upload-Offset.
+func (h *UploadHandler) WriteUpload(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "PATCH", UploadsPath+"/{id}")
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	if r.Header.Get("Content-Type") != tusContentType {
+		respond.Problem(w, r, http.StatusUnsupportedMediaType, dto.ErrorTypeInvalidRequest, "uploads are appended with Content-Type "+tusContentType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		respond.Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid Upload-Offset: %q", r.Header.Get("Upload-Offset")))
+		return
+	}
+
+	log.Info().
+		Str("upload_id", id).
+		Int64("offset", offset).
+		Msg("Handling WriteUpload request")
+
+	upload, err := h.draftService.WriteUpload(ctx, id, offset, r.Body)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("upload_id", id).
+			Msg("WriteUpload operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("upload_id", id).
+		Int64("upload_offset", upload.Offset).
+		Bool("complete", upload.Complete()).
+		Msg("WriteUpload operation completed successfully")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	setUploadExpires(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TerminateUpload handles DELETE /api/v2/uploads/{id}
+func (h *UploadHandler) TerminateUpload(w http.ResponseWriter, r *http.Request) {
+	log := logger.GetHandlerLogger(r.Context(), "http", "DELETE", UploadsPath+"/{id}")
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	log.Info().
+		Str("upload_id", id).
+		Msg("Handling TerminateUpload request")
+
+	if err := h.draftService.TerminateUpload(ctx, id); err != nil {
+		log.Error().
+			Err(err).
+			Str("upload_id", id).
+			Msg("TerminateUpload operation failed")
+		respond.Error(w, r, err)
+		return
+	}
+
+	log.Info().
+		Str("upload_id", id).
+		Msg("TerminateUpload operation completed successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegisterRoutes registers the tus routes. They are registered by full path,
+// since /api/v2 is routed by the ObjectHandler.
+func (h *UploadHandler) RegisterRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(tusResumable)
+		r.Options(UploadsPath, h.Options)
+		r.Post(UploadsPath, h.CreateUpload)
+		r.Options(UploadsPath+"/{id}", h.Options)
+		r.Head(UploadsPath+"/{id}", h.GetUpload)
+		r.Patch(UploadsPath+"/{id}", h.WriteUpload)
+		r.Delete(UploadsPath+"/{id}", h.TerminateUpload)
+	})
+}
+
+// tusResumable answers every tus request with the protocol version and
+// rejects requests for other versions. OPTIONS requests need not name one.
+func tusResumable(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+		if r.Method != http.MethodOptions && r.Header.Get("Tus-Resumable") != tusVersion {
+			w.Header().Set("Tus-Version", tusVersion)
+			respond.Problem(w, r, http.StatusPreconditionFailed, dto.ErrorTypeInvalidRequest, fmt.Sprintf("unsupported Tus-Resumable version %q", r.Header.Get("Tus-Resumable")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setUploadExpires sets the Upload-Expires header of the expiration extension.
+func setUploadExpires(w http.ResponseWriter, upload draft.ResumableUpload) {
+	if !upload.Complete() {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// parseUploadMetadata decodes an Upload-Metadata header: comma separated
+// pairs of a key and a base64 encoded value, which may be omitted.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("invalid Upload-Metadata: empty key")
+		}
+		if _, ok := metadata[key]; ok {
+			return nil, fmt.Errorf("invalid Upload-Metadata: duplicate key %q", key)
+		}
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata value of %q: %w", key, err)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// formatUploadMetadata encodes metadata as an Upload-Metadata header.
+func formatUploadMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		if value == "" {
+			pairs = append(pairs, key)
+			continue
+		}
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// firstMetadata returns the value of the first of keys present in metadata.
+func firstMetadata(metadata map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value, ok := metadata[key]; ok {
+			return value
+		}
+	}
+	return ""
+}
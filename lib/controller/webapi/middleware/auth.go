@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/respond"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// Authenticate resolves the caller with authenticator and stores the
+// principal in the request context. Requests without credentials are
+// rejected unless allowAnonymous is set.
+func Authenticate(authenticator auth.Authenticator, allowAnonymous bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := logger.GetHandlerLogger(r.Context(), "http", r.Method, r.URL.Path)
+
+			if r.TLS != nil {
+				r = r.WithContext(auth.WithTLSState(r.Context(), r.TLS))
+			}
+
+			principal, err := authenticator.Authenticate(r.Context(), r.Header)
+			switch {
+			case err == nil:
+				ctx := auth.WithPrincipal(r.Context(), principal)
+				r = r.WithContext(logger.WithFields(ctx, principal.LogFields()))
+			case errors.Is(err, auth.ErrNoCredentials) && allowAnonymous:
+			default:
+				log.Warn().
+					Err(err).
+					Msg("Rejected unauthenticated request")
+				writeUnauthenticated(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeUnauthenticated(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	respond.Problem(w, r, http.StatusUnauthorized, dto.ErrorTypeAccessDenied, "The request is not authenticated.")
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import "net/http"
+
+// CookieCredentials passes the bearer token stored in the named cookie to
+// the authenticators for requests without an Authorization header, so that
+// browsers can load objects through plain links.
+func CookieCredentials(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+					r = r.Clone(r.Context())
+					r.Header.Set("Authorization", "Bearer "+cookie.Value)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
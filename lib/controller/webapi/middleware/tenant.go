@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/snowmerak/DraftStore/lib/tenant"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// Tenant stores the tenant named by the tenant header in the request context.
+func Tenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get(tenant.Header); id != "" {
+			ctx := tenant.WithID(r.Context(), id)
+			r = r.WithContext(logger.WithFields(ctx, map[string]string{"tenant": id}))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Timeout cancels requests after timeout. Requests for which isStream
+// reports true carry file content and get streamTimeout instead, which also
+// replaces the server's read and write timeouts for their connection.
+func Timeout(timeout, streamTimeout time.Duration, isStream func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := middleware.Timeout(timeout)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isStream(r) {
+				limited.ServeHTTP(w, r)
+				return
+			}
+
+			deadline := time.Now().Add(streamTimeout)
+			controller := http.NewResponseController(w)
+			controller.SetReadDeadline(deadline)
+			controller.SetWriteDeadline(deadline)
+
+			ctx, cancel := context.WithDeadline(r.Context(), deadline)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	swaggerFiles "github.com/swaggo/files/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// spec is generated from proto/draft/v1/draft.proto by protoc-gen-openapi.
+//
+//go:embed openapi.yaml
+var spec []byte
+
+// initializer points the explorer at the served spec instead of the petstore.
+//
+//go:embed swagger-initializer.js
+var initializer []byte
+
+const (
+	// SpecPath is where the OpenAPI document is served.
+	SpecPath = "/api/v1/openapi.json"
+	// DocsPath is where the API explorer is served.
+	DocsPath = "/api/v1/docs"
+)
+
+// Handler serves the OpenAPI document of the REST API and an API explorer
+// for it. Both are embedded in the binary.
+type Handler struct {
+	document map[string]any
+	body     []byte
+}
+
+func NewHandler() (*Handler, error) {
+	log := logger.GetServiceLogger("openapi-handler")
+
+	var document map[string]any
+	if err := yaml.Unmarshal(spec, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	addProblemResponses(document)
+	addSecuritySchemes(document)
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	handler := &Handler{
+		document: document,
+		body:     body,
+	}
+
+	log.Info().Msg("OpenAPI handler initialized")
+	return handler, nil
+}
+
+// ServeSpec handles GET /api/v1/openapi.json
+func (h *Handler) ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.body)
+}
+
+// ServeInitializer serves the explorer configuration that loads the spec.
+func (h *Handler) ServeInitializer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(initializer)
+}
+
+// RegisterRoutes registers the spec and explorer routes
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get(SpecPath, h.ServeSpec)
+	r.Get(DocsPath, http.RedirectHandler(DocsPath+"/", http.StatusMovedPermanently).ServeHTTP)
+	r.Get(DocsPath+"/swagger-initializer.js", h.ServeInitializer)
+	r.Get(DocsPath+"/*", http.StripPrefix(DocsPath+"/", http.FileServerFS(swaggerFiles.FS)).ServeHTTP)
+}
+
+// CheckRoutes reports operations of the spec that routes does not serve and
+// routes under /api/v1/draft that the spec does not document.
+func (h *Handler) CheckRoutes(routes chi.Routes) error {
+	var problems []string
+
+	documented := make(map[string]bool)
+	paths, _ := h.document["paths"].(map[string]any)
+	for path, item := range paths {
+		operations, _ := item.(map[string]any)
+		for method := range operations {
+			method = strings.ToUpper(method)
+			documented[method+" "+path] = true
+			if !routes.Match(chi.NewRouteContext(), method, path) {
+				problems = append(problems, fmt.Sprintf("%s %s is documented but not served", method, path))
+			}
+		}
+	}
+
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, "/api/v1/draft/") && !documented[method+" "+route] {
+			problems = append(problems, fmt.Sprintf("%s %s is served but not documented", method, route))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk routes: %w", err)
+	}
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("OpenAPI document does not match routes: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// addProblemResponses documents the problem details body that every
+// operation returns on failure, which protoc-gen-openapi cannot express.
+func addProblemResponses(document map[string]any) {
+	components := child(document, "components")
+	child(components, "schemas")["Problem"] = map[string]any{
+		"type":        "object",
+		"description": "RFC 9457 problem details",
+		"properties": map[string]any{
+			"type":       map[string]any{"type": "string"},
+			"title":      map[string]any{"type": "string"},
+			"status":     map[string]any{"type": "integer", "format": "int32"},
+			"detail":     map[string]any{"type": "string"},
+			"instance":   map[string]any{"type": "string"},
+			"error_type": map[string]any{"type": "string"},
+			"retryable":  map[string]any{"type": "boolean"},
+			"request_id": map[string]any{"type": "string"},
+		},
+	}
+
+	paths, _ := document["paths"].(map[string]any)
+	for _, item := range paths {
+		operations, _ := item.(map[string]any)
+		for _, operation := range operations {
+			operation, ok := operation.(map[string]any)
+			if !ok {
+				continue
+			}
+			child(operation, "responses")["default"] = map[string]any{
+				"description": "Error",
+				"content": map[string]any{
+					dto.ProblemContentType: map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/Problem"},
+					},
+				},
+			}
+		}
+	}
+}
+
+// addSecuritySchemes documents the bearer token and API key credentials
+// accepted by the authentication middleware.
+func addSecuritySchemes(document map[string]any) {
+	components := child(document, "components")
+	components["securitySchemes"] = map[string]any{
+		"bearer": map[string]any{
+			"type":   "http",
+			"scheme": "bearer",
+		},
+		"apiKey": map[string]any{
+			"type": "apiKey",
+			"in":   "header",
+			"name": "X-API-Key",
+		},
+	}
+	document["security"] = []any{
+		map[string]any{"bearer": []any{}},
+		map[string]any{"apiKey": []any{}},
+	}
+}
+
+// child returns the object stored under key, creating it when missing.
+func child(parent map[string]any, key string) map[string]any {
+	if m, ok := parent[key].(map[string]any); ok {
+		return m
+	}
+	m := make(map[string]any)
+	parent[key] = m
+	return m
+}
@@ -0,0 +1,38 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"buf.build/go/protovalidate"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/snowmerak/DraftStore/lib/controller/webapi"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/openapi"
+)
+
+// TestCheckRoutes fails when the REST routes drift from the embedded
+// document, e.g. after changing the google.api.http annotations of
+// draft.proto without running buf generate.
+func TestCheckRoutes(t *testing.T) {
+	validator, err := protovalidate.New()
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+	handler, err := openapi.NewHandler()
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI document: %v", err)
+	}
+
+	router := chi.NewRouter()
+	handler.RegisterRoutes(router)
+	webapi.NewServer(webapi.ServerOptions{
+		Router:           router,
+		Validator:        validator,
+		LinkRouter:       router.With(),
+		ResumableUploads: true,
+	})
+
+	if err := handler.CheckRoutes(router); err != nil {
+		t.Fatal(err)
+	}
+}
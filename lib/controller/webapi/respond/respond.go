@@ -0,0 +1,113 @@
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/snowmerak/DraftStore/lib/controller/errormap"
+	"github.com/snowmerak/DraftStore/lib/controller/webapi/dto"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// details are the problem details of failed service calls by status code.
+// Service errors may name buckets and storage keys, so they are only logged.
+var details = map[int]string{
+	http.StatusBadRequest:            "The request is invalid.",
+	http.StatusForbidden:             "The request is not permitted.",
+	http.StatusNotFound:              "The requested resource does not exist.",
+	http.StatusConflict:              "The request conflicts with the current state of the resource.",
+	http.StatusRequestEntityTooLarge: "The object is too large.",
+	http.StatusLocked:                "The upload is in use by another request.",
+	http.StatusTooManyRequests:       "The storage quota is exceeded.",
+	http.StatusServiceUnavailable:    "The storage backend is unavailable.",
+	http.StatusInternalServerError:   "The request could not be completed.",
+}
+
+// JSON writes v as a JSON body with the given status code.
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Message writes msg as a protobuf JSON body with the given status code,
+// using proto field names and enum numbers as documented by the OpenAPI spec.
+func Message(w http.ResponseWriter, status int, msg proto.Message) {
+	body, err := protojson.MarshalOptions{UseProtoNames: true, UseEnumNumbers: true}.Marshal(msg)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(map[string]string{"error": err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// Error writes err as a problem details body whose status code is derived
+// from its ErrorType. The detail is fixed per status code and err is logged
+// under the request ID instead.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	status := errormap.MapToHTTPStatus(err)
+	errorType := errormap.MapToErrorType(err)
+
+	log := logger.GetHandlerLogger(r.Context(), "http", r.Method, r.URL.Path)
+	log.Warn().
+		Err(err).
+		Str("request_id", middleware.GetReqID(r.Context())).
+		Int("status", status).
+		Str("error_type", errorType.String()).
+		Msg("Request failed")
+
+	detail, ok := details[status]
+	if !ok {
+		detail = http.StatusText(status)
+	}
+	Problem(w, r, status, errorType, detail)
+}
+
+// InvalidBody writes the problem details body for a request body that could
+// not be decoded. Bodies cut off by http.MaxBytesReader are reported as too
+// large.
+func InvalidBody(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		Problem(w, r, http.StatusRequestEntityTooLarge, dto.ErrorTypeInvalidRequest, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	Problem(w, r, http.StatusBadRequest, dto.ErrorTypeInvalidRequest, fmt.Sprintf("invalid request body: %v", err))
+}
+
+// Problem writes an RFC 9457 problem details body for errorType. The body
+// carries the request ID assigned by the RequestID middleware, if any.
+func Problem(w http.ResponseWriter, r *http.Request, status int, errorType dto.ErrorType, detail string) {
+	retryable := errormap.IsRetryable(errorType)
+	requestID := middleware.GetReqID(r.Context())
+
+	problem := &dto.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		ErrorType: errorType.String(),
+		Retryable: retryable,
+		RequestID: requestID,
+	}
+
+	w.Header().Set("Content-Type", dto.ProblemContentType)
+	if requestID != "" {
+		w.Header().Set(middleware.RequestIDHeader, requestID)
+	}
+	if retryable {
+		w.Header().Set("Retry-After", strconv.Itoa(int(errormap.DefaultRetryDelay.Seconds())))
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem)
+}
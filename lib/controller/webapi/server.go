@@ -1,36 +1,74 @@
 package webapi
 
 import (
+	"buf.build/go/protovalidate"
 	"github.com/go-chi/chi/v5"
 	"github.com/snowmerak/DraftStore/lib/controller/webapi/handler"
+	"github.com/snowmerak/DraftStore/lib/service/apikey"
 	"github.com/snowmerak/DraftStore/lib/service/draft"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
 )
 
 type Server struct {
-	router       chi.Router
-	address      string
-	draftHandler *handler.DraftHandler
+	router        chi.Router
+	address       string
+	draftHandler  *handler.DraftHandler
+	objectHandler *handler.ObjectHandler
+	adminHandler  *handler.AdminHandler
+	linkHandler   *handler.LinkHandler
+	uploadHandler *handler.UploadHandler
 }
 
 type ServerOptions struct {
 	Router       chi.Router
 	Address      string
-	DraftService *draft.Service
+	DraftService draft.API
+	// Validator enforces the protovalidate rules of decoded requests.
+	Validator protovalidate.Validator
+	// APIKeyService enables the admin routes when set.
+	APIKeyService *apikey.Service
+	// LinkRouter, when set, serves download links. Its middleware must let
+	// callers without credentials through, since links may be public or signed.
+	LinkRouter chi.Router
+	// LinkAllowAnonymous lets links outside the public prefixes be resolved
+	// without credentials.
+	LinkAllowAnonymous bool
+	// ResumableUploads enables the tus endpoint for resumable uploads.
+	ResumableUploads bool
+	// MaxUploadSize is advertised to tus clients when positive.
+	MaxUploadSize int64
 }
 
 func NewServer(option ServerOptions) *Server {
 	log := logger.GetServiceLogger("webapi-controller")
 
-	draftHandler := handler.NewDraftHandler(option.DraftService)
+	draftHandler := handler.NewDraftHandler(option.DraftService, option.Validator)
+	objectHandler := handler.NewObjectHandler(option.DraftService, option.Validator)
 
 	// Register routes
 	draftHandler.RegisterRoutes(option.Router)
+	objectHandler.RegisterRoutes(option.Router)
 
 	server := &Server{
-		router:       option.Router,
-		address:      option.Address,
-		draftHandler: draftHandler,
+		router:        option.Router,
+		address:       option.Address,
+		draftHandler:  draftHandler,
+		objectHandler: objectHandler,
+	}
+
+	if option.APIKeyService != nil {
+		server.adminHandler = handler.NewAdminHandler(option.APIKeyService)
+		server.adminHandler.RegisterRoutes(option.Router)
+	}
+
+	if option.ResumableUploads {
+		server.uploadHandler = handler.NewUploadHandler(option.DraftService, option.Validator, option.MaxUploadSize)
+		server.uploadHandler.RegisterRoutes(option.Router)
+	}
+
+	if option.LinkRouter != nil {
+		server.linkHandler = handler.NewLinkHandler(option.DraftService, option.Validator, option.LinkAllowAnonymous)
+		server.linkHandler.RegisterRoutes(option.LinkRouter)
 	}
 
 	log.Info().
@@ -0,0 +1,300 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// AllocFunction is exported by plugins to reserve size bytes of linear
+	// memory for the input payload: alloc(size i32) -> ptr i32.
+	AllocFunction = "alloc"
+	// EvaluateFunction is exported by plugins to evaluate a payload:
+	// evaluate(ptr i32, len i32) -> i64, the result's ptr<<32 | len.
+	EvaluateFunction = "evaluate"
+	// MemoryExport is the linear memory plugins export for payloads and
+	// results.
+	MemoryExport = "memory"
+
+	DefaultTimeout = time.Second
+	// DefaultMemoryLimitPages caps plugin memory at 16 MiB (64 KiB pages).
+	DefaultMemoryLimitPages = 256
+
+	DecisionAllow   = "allow"
+	DecisionDeny    = "deny"
+	DecisionRewrite = "rewrite"
+
+	OperationUpload  = "upload"
+	OperationConfirm = "confirm"
+)
+
+var (
+	_ draft.PreUploadHook  = (*Host)(nil)
+	_ draft.PreConfirmHook = (*Host)(nil)
+)
+
+// Host runs WebAssembly policy plugins as draft service hooks.
+type Host struct {
+	runtime wazero.Runtime
+	plugins []plugin
+	timeout time.Duration
+}
+
+type plugin struct {
+	name     string
+	compiled wazero.CompiledModule
+}
+
+type HostOptions struct {
+	// Directory is scanned for *.wasm modules, which run in file name order.
+	Directory string
+	// Timeout bounds a single plugin call, including instantiation.
+	Timeout time.Duration
+	// MemoryLimitPages caps the linear memory of each plugin instance.
+	MemoryLimitPages uint32
+}
+
+// Payload is the JSON document passed to a plugin.
+type Payload struct {
+	Operation   string            `json:"operation"`
+	Key         string            `json:"key"`
+	DraftKey    string            `json:"draft_key,omitempty"`
+	Size        int64             `json:"size"`
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Principal   Principal         `json:"principal"`
+}
+
+type Principal struct {
+	Subject    string            `json:"sub,omitempty"`
+	Method     string            `json:"method,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Decision is the JSON document returned by a plugin.
+type Decision struct {
+	Decision string `json:"decision"`
+	// Key is the new object key of a rewrite decision.
+	Key    string `json:"key,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func NewHost(ctx context.Context, opts HostOptions) (*Host, error) {
+	log := logger.GetServiceLogger("wasm-plugin-host")
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MemoryLimitPages == 0 {
+		opts.MemoryLimitPages = DefaultMemoryLimitPages
+	}
+
+	paths, err := filepath.Glob(filepath.Join(opts.Directory, "*.wasm"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugins: %w", err)
+	}
+	sort.Strings(paths)
+
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(opts.MemoryLimitPages).
+		WithCloseOnContextDone(true))
+
+	// Plugins built for WASI get a sandbox without filesystem or network access
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("failed to instantiate WASI: %w", err)
+	}
+
+	host := &Host{
+		runtime: runtime,
+		timeout: opts.Timeout,
+	}
+
+	for _, path := range paths {
+		binary, err := os.ReadFile(path)
+		if err != nil {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("failed to read plugin %s: %w", path, err)
+		}
+
+		compiled, err := runtime.CompileModule(ctx, binary)
+		if err != nil {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("failed to compile plugin %s: %w", path, err)
+		}
+
+		exports := compiled.ExportedFunctions()
+		for _, name := range []string{AllocFunction, EvaluateFunction} {
+			if _, ok := exports[name]; !ok {
+				runtime.Close(ctx)
+				return nil, fmt.Errorf("plugin %s does not export %s", path, name)
+			}
+		}
+		if _, ok := compiled.ExportedMemories()[MemoryExport]; !ok {
+			runtime.Close(ctx)
+			return nil, fmt.Errorf("plugin %s does not export %s", path, MemoryExport)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".wasm")
+		host.plugins = append(host.plugins, plugin{name: name, compiled: compiled})
+
+		log.Info().
+			Str("plugin", name).
+			Str("path", path).
+			Msg("Loaded WebAssembly plugin")
+	}
+
+	log.Info().
+		Str("directory", opts.Directory).
+		Int("plugins", len(host.plugins)).
+		Dur("timeout", host.timeout).
+		Uint32("memory_limit_pages", opts.MemoryLimitPages).
+		Msg("WebAssembly plugin host initialized")
+
+	return host, nil
+}
+
+// Close releases the runtime and all compiled plugins.
+func (h *Host) Close(ctx context.Context) error {
+	return h.runtime.Close(ctx)
+}
+
+// PreUpload implements draft.PreUploadHook.
+func (h *Host) PreUpload(ctx context.Context, req *draft.HookRequest) error {
+	return h.evaluate(ctx, OperationUpload, req)
+}
+
+// PreConfirm implements draft.PreConfirmHook.
+func (h *Host) PreConfirm(ctx context.Context, req *draft.HookRequest) error {
+	return h.evaluate(ctx, OperationConfirm, req)
+}
+
+// evaluate runs every plugin in order. A deny stops evaluation and a rewrite
+// is visible to the plugins that follow.
+func (h *Host) evaluate(ctx context.Context, operation string, req *draft.HookRequest) error {
+	log := logger.GetServiceLogger("wasm-plugin-host").With().
+		Str("operation", operation).
+		Str("object_name", req.ObjectName).
+		Logger()
+
+	for _, p := range h.plugins {
+		input, err := json.Marshal(Payload{
+			Operation:   operation,
+			Key:         req.ObjectName,
+			DraftKey:    req.DraftName,
+			Size:        req.Size,
+			ContentType: req.ContentType,
+			Metadata:    req.Metadata,
+			Principal: Principal{
+				Subject:    req.Principal.Subject,
+				Method:     req.Principal.Method,
+				Attributes: req.Principal.Attributes,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode plugin payload: %w", err)
+		}
+
+		decision, err := h.call(ctx, p, input)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("plugin", p.name).
+				Msg("Plugin call failed")
+			return fmt.Errorf("plugin %s failed: %w", p.name, err)
+		}
+
+		switch decision.Decision {
+		case DecisionAllow:
+		case DecisionDeny:
+			log.Info().
+				Str("plugin", p.name).
+				Str("reason", decision.Reason).
+				Msg("Plugin denied request")
+			return fmt.Errorf("%w: plugin %s: %s", draft.ErrRejected, p.name, decision.Reason)
+		case DecisionRewrite:
+			if decision.Key == "" {
+				return fmt.Errorf("plugin %s returned a rewrite without a key", p.name)
+			}
+			log.Info().
+				Str("plugin", p.name).
+				Str("rewritten_object_name", decision.Key).
+				Msg("Plugin rewrote object name")
+			req.ObjectName = decision.Key
+		default:
+			return fmt.Errorf("plugin %s returned unknown decision %q", p.name, decision.Decision)
+		}
+	}
+
+	return nil
+}
+
+// call evaluates input on a fresh instance of the plugin, so no state leaks
+// between requests.
+func (h *Host) call(ctx context.Context, p plugin, input []byte) (Decision, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	module, err := h.runtime.InstantiateModule(ctx, p.compiled, wazero.NewModuleConfig().
+		WithName("").
+		WithStartFunctions("_initialize"))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to instantiate plugin: %w", err)
+	}
+	defer module.Close(ctx)
+
+	memory := module.ExportedMemory(MemoryExport)
+	if memory == nil {
+		return Decision{}, fmt.Errorf("plugin does not export %s", MemoryExport)
+	}
+
+	results, err := module.ExportedFunction(AllocFunction).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to allocate plugin memory: %w", err)
+	}
+	ptr := uint32(results[0])
+
+	if !memory.Write(ptr, input) {
+		return Decision{}, errors.New("plugin allocation is out of memory bounds")
+	}
+
+	results, err = module.ExportedFunction(EvaluateFunction).Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to evaluate plugin: %w", err)
+	}
+
+	output, err := readResult(memory, results[0])
+	if err != nil {
+		return Decision{}, err
+	}
+
+	var decision Decision
+	if err := json.Unmarshal(output, &decision); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode plugin decision: %w", err)
+	}
+	return decision, nil
+}
+
+func readResult(memory api.Memory, packed uint64) ([]byte, error) {
+	ptr, length := uint32(packed>>32), uint32(packed)
+	output, ok := memory.Read(ptr, length)
+	if !ok {
+		return nil, errors.New("plugin result is out of memory bounds")
+	}
+	// Copy out of the instance memory, which is released on close
+	return append([]byte(nil), output...), nil
+}
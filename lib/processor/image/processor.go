@@ -0,0 +1,268 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/snowmerak/DraftStore/lib/processor"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+
+	// VariantClean is the full size re-encoded image without metadata.
+	VariantClean = "clean"
+
+	DefaultJPEGQuality = 85
+	// DefaultMaxPixels guards against decompression bombs.
+	DefaultMaxPixels = 40_000_000
+	// DefaultMaxBytes is the largest encoded image that is read.
+	DefaultMaxBytes = 64 << 20
+)
+
+var _ processor.Processor = (*Processor)(nil)
+
+type Processor struct {
+	widths      []int
+	format      string
+	jpegQuality int
+	maxPixels   int
+	maxBytes    int64
+}
+
+type ProcessorOptions struct {
+	// Widths lists the thumbnail widths to generate. Images are never upscaled.
+	Widths []int
+	// Format forces the output encoding. Empty keeps PNG and GIF sources as
+	// PNG and encodes everything else as JPEG.
+	Format      string
+	JPEGQuality int
+	MaxPixels   int
+	// MaxBytes caps how much of the encoded image is read. Defaults to
+	// DefaultMaxBytes.
+	MaxBytes int64
+}
+
+func NewProcessor(opts ProcessorOptions) (*Processor, error) {
+	log := logger.GetServiceLogger("image-processor")
+
+	switch opts.Format {
+	case "", FormatJPEG, FormatPNG:
+	default:
+		return nil, fmt.Errorf("unsupported image format: %s", opts.Format)
+	}
+	for _, width := range opts.Widths {
+		if width <= 0 {
+			return nil, fmt.Errorf("invalid thumbnail width: %d", width)
+		}
+	}
+
+	p := &Processor{
+		widths:      opts.Widths,
+		format:      opts.Format,
+		jpegQuality: opts.JPEGQuality,
+		maxPixels:   opts.MaxPixels,
+		maxBytes:    opts.MaxBytes,
+	}
+	if p.jpegQuality <= 0 {
+		p.jpegQuality = DefaultJPEGQuality
+	}
+	if p.maxPixels <= 0 {
+		p.maxPixels = DefaultMaxPixels
+	}
+	if p.maxBytes <= 0 {
+		p.maxBytes = DefaultMaxBytes
+	}
+
+	log.Info().
+		Ints("widths", p.widths).
+		Str("format", p.format).
+		Int("jpeg_quality", p.jpegQuality).
+		Int("max_pixels", p.maxPixels).
+		Int64("max_bytes", p.maxBytes).
+		Msg("Image processor initialized")
+
+	return p, nil
+}
+
+// Name implements processor.Processor.
+func (p *Processor) Name() string {
+	return "image"
+}
+
+// Accepts implements processor.Processor.
+func (p *Processor) Accepts(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// Process implements processor.Processor. Decoding and re-encoding drops
+// EXIF and any other metadata embedded in the source.
+func (p *Processor) Process(ctx context.Context, src io.Reader) ([]processor.Derivative, error) {
+	limited := &io.LimitedReader{R: src, N: p.maxBytes}
+	body := bufio.NewReader(limited)
+
+	// Only the header is read before the dimensions are checked. It is
+	// kept to decode the image from the start afterwards.
+	var header bytes.Buffer
+	config, sourceFormat, err := image.DecodeConfig(io.TeeReader(body, &header))
+	if err != nil {
+		return nil, p.readError("failed to decode image header", limited, err)
+	}
+	if config.Width*config.Height > p.maxPixels {
+		return nil, fmt.Errorf("image of %dx%d exceeds %d pixels", config.Width, config.Height, p.maxPixels)
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, body))
+	if err != nil {
+		return nil, p.readError("failed to decode image", limited, err)
+	}
+
+	format := p.outputFormat(sourceFormat)
+
+	clean, err := p.encode(img, format)
+	if err != nil {
+		return nil, err
+	}
+	derivatives := []processor.Derivative{clean.named(VariantClean)}
+
+	// Thumbnails are all scaled from one NRGBA copy of the source
+	var source *image.NRGBA
+	for _, width := range p.widths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var thumbnail image.Image = img
+		if width < img.Bounds().Dx() {
+			if source == nil {
+				source = toNRGBA(img)
+			}
+			height := max(1, img.Bounds().Dy()*width/img.Bounds().Dx())
+			thumbnail = resize(source, width, height)
+		}
+
+		encoded, err := p.encode(thumbnail, format)
+		if err != nil {
+			return nil, err
+		}
+		derivatives = append(derivatives, encoded.named("w"+strconv.Itoa(width)))
+	}
+
+	return derivatives, nil
+}
+
+// readError reports images cut off at the size limit as too large rather
+// than as malformed.
+func (p *Processor) readError(msg string, limited *io.LimitedReader, err error) error {
+	if limited.N <= 0 {
+		return fmt.Errorf("image exceeds %d bytes", p.maxBytes)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func (p *Processor) outputFormat(sourceFormat string) string {
+	if p.format != "" {
+		return p.format
+	}
+	if sourceFormat == "png" || sourceFormat == "gif" {
+		return FormatPNG
+	}
+	return FormatJPEG
+}
+
+type encoded struct {
+	contentType string
+	data        []byte
+}
+
+func (e encoded) named(variant string) processor.Derivative {
+	return processor.Derivative{
+		Variant:     variant,
+		ContentType: e.contentType,
+		Data:        e.data,
+	}
+}
+
+func (p *Processor) encode(img image.Image, format string) (encoded, error) {
+	var buf bytes.Buffer
+	switch format {
+	case FormatPNG:
+		if err := png.Encode(&buf, img); err != nil {
+			return encoded{}, fmt.Errorf("failed to encode png: %w", err)
+		}
+		return encoded{contentType: "image/png", data: buf.Bytes()}, nil
+	case FormatJPEG:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.jpegQuality}); err != nil {
+			return encoded{}, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+		return encoded{contentType: "image/jpeg", data: buf.Bytes()}, nil
+	default:
+		return encoded{}, errors.New("unsupported image format: " + format)
+	}
+}
+
+// toNRGBA copies img into an NRGBA image whose bounds start at the origin.
+func toNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// resize downscales src with a box filter, averaging every source pixel
+// that falls into a destination pixel.
+func resize(src *image.NRGBA, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := max(y0+1, (y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := max(x0+1, (x+1)*srcW/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					// Weight colour by alpha so transparent pixels do not darken edges
+					alpha := uint64(px[3])
+					r += uint64(px[0]) * alpha
+					g += uint64(px[1]) * alpha
+					b += uint64(px[2]) * alpha
+					a += alpha
+					n++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			if a > 0 {
+				dst.Pix[offset] = uint8(r / a)
+				dst.Pix[offset+1] = uint8(g / a)
+				dst.Pix[offset+2] = uint8(b / a)
+			}
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,23 @@
+package processor
+
+import (
+	"context"
+	"io"
+)
+
+// Processor derives additional objects from a confirmed object.
+type Processor interface {
+	// Name identifies the processor in logs.
+	Name() string
+	// Accepts reports whether the processor handles objects of the given content type.
+	Accepts(contentType string) bool
+	Process(ctx context.Context, src io.Reader) ([]Derivative, error)
+}
+
+// Derivative is an object generated from a confirmed object.
+type Derivative struct {
+	// Variant names the derivative, e.g. "w256". It is unique per processor.
+	Variant     string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var _ Repository = (*MemoryRepository)(nil)
+
+// MemoryRepository keeps records in process memory. It suits single replica
+// deployments; records are lost on restart.
+type MemoryRepository struct {
+	mu      sync.Mutex
+	ledgers map[string]*ledger
+	// owners maps draft keys to the owner that last reserved them
+	owners  map[string]string
+	uploads map[string]Upload
+	// version numbers the saved upload states
+	version uint64
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		ledgers: make(map[string]*ledger),
+		owners:  make(map[string]string),
+		uploads: make(map[string]Upload),
+	}
+}
+
+func (r *MemoryRepository) ledger(owner string) *ledger {
+	l, ok := r.ledgers[owner]
+	if !ok {
+		l = &ledger{Owner: owner}
+		r.ledgers[owner] = l
+	}
+	return l
+}
+
+// Usage implements Repository.
+func (r *MemoryRepository) Usage(ctx context.Context, owner string) (Usage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ledger(owner).usage(time.Now()), nil
+}
+
+// ReserveDraft implements Repository.
+func (r *MemoryRepository) ReserveDraft(ctx context.Context, owner string, draft Draft, quota Quota) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.ledger(owner).reserve(draft, quota, time.Now()); err != nil {
+		return err
+	}
+	r.owners[draft.Key] = owner
+	return nil
+}
+
+// ReleaseDraft implements Repository.
+func (r *MemoryRepository) ReleaseDraft(ctx context.Context, owner, key string) (Draft, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	draft, ok := r.ledger(owner).release(key)
+	if !ok {
+		return Draft{}, ErrNotFound
+	}
+	r.forgetOwner(key, owner)
+	return draft, nil
+}
+
+// DraftOwner implements Repository.
+func (r *MemoryRepository) DraftOwner(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	owner, ok := r.owners[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return owner, nil
+}
+
+// forgetOwner drops the owner of key unless another owner reserved it since.
+func (r *MemoryRepository) forgetOwner(key, owner string) {
+	if r.owners[key] == owner {
+		delete(r.owners, key)
+	}
+}
+
+// ConfirmObject implements Repository.
+func (r *MemoryRepository) ConfirmObject(ctx context.Context, owner, key string, size int64, quota Quota) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ledger(owner).confirmObject(key, size, quota)
+}
+
+// PruneDrafts implements Repository.
+func (r *MemoryRepository) PruneDrafts(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pruned := 0
+	for owner, l := range r.ledgers {
+		drafts := l.prune(now)
+		for _, draft := range drafts {
+			r.forgetOwner(draft.Key, owner)
+		}
+		pruned += len(drafts)
+	}
+	return pruned, nil
+}
+
+// SaveUpload implements Repository.
+func (r *MemoryRepository) SaveUpload(ctx context.Context, upload *Upload) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.uploads[upload.ID]; ok != (upload.Version != "") || stored.Version != upload.Version {
+		return fmt.Errorf("%w: upload %s", ErrConflict, upload.ID)
+	}
+	r.version++
+	upload.Version = strconv.FormatUint(r.version, 10)
+	r.uploads[upload.ID] = cloneUpload(*upload)
+	return nil
+}
+
+// GetUpload implements Repository.
+func (r *MemoryRepository) GetUpload(ctx context.Context, id string) (Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upload, ok := r.uploads[id]
+	if !ok {
+		return Upload{}, ErrNotFound
+	}
+	return cloneUpload(upload), nil
+}
+
+// DeleteUpload implements Repository.
+func (r *MemoryRepository) DeleteUpload(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.uploads[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.uploads, id)
+	return nil
+}
+
+// PruneUploads implements Repository.
+func (r *MemoryRepository) PruneUploads(ctx context.Context, now time.Time) ([]Upload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pruned []Upload
+	for id, upload := range r.uploads {
+		if !upload.ExpiresAt.After(now) {
+			pruned = append(pruned, upload)
+			delete(r.uploads, id)
+		}
+	}
+	return pruned, nil
+}
+
+// cloneUpload copies an upload, so that callers never share its parts or
+// metadata with the stored one.
+func cloneUpload(upload Upload) Upload {
+	upload.Parts = slices.Clone(upload.Parts)
+	upload.Metadata = maps.Clone(upload.Metadata)
+	return upload
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the requested record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrQuotaExceeded is returned when a reservation would exceed the owner's quota.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+	// ErrConflict is returned when a record kept changing concurrently and
+	// an update could not be applied.
+	ErrConflict = errors.New("record was modified concurrently")
+)
+
+// Repository keeps the metadata of drafts and the usage of their owners.
+// Owners are opaque strings such as a principal or tenant identifier.
+type Repository interface {
+	// Usage returns the owner's usage. Expired drafts are not counted.
+	Usage(ctx context.Context, owner string) (Usage, error)
+	// ReserveDraft records a draft for the owner if the owner stays within
+	// quota, replacing any draft under the same key. It returns an error
+	// wrapping ErrQuotaExceeded otherwise.
+	ReserveDraft(ctx context.Context, owner string, draft Draft, quota Quota) error
+	// ReleaseDraft removes the owner's draft under key and returns it. It
+	// returns ErrNotFound when the owner has no such draft.
+	ReleaseDraft(ctx context.Context, owner, key string) (Draft, error)
+	// DraftOwner returns the owner that last reserved the draft under key,
+	// so that it can be released by another caller. It returns ErrNotFound
+	// when no owner holds a draft under key.
+	DraftOwner(ctx context.Context, key string) (string, error)
+	// ConfirmObject records size as the confirmed size of the owner's object
+	// key and returns the size it was confirmed with before, zero if none.
+	// When the owner's confirmed bytes would grow beyond quota it changes
+	// nothing and returns an error wrapping ErrQuotaExceeded.
+	ConfirmObject(ctx context.Context, owner, key string, size int64, quota Quota) (int64, error)
+	// PruneDrafts removes drafts that expired before now and returns how many
+	// were removed.
+	PruneDrafts(ctx context.Context, now time.Time) (int, error)
+	// SaveUpload stores the state of a resumable upload. A new upload, with
+	// an empty Version, must not exist yet and an existing one must still be
+	// at upload.Version; otherwise it returns an error wrapping ErrConflict.
+	// It sets upload.Version to the stored state.
+	SaveUpload(ctx context.Context, upload *Upload) error
+	// GetUpload returns the resumable upload with the given ID. It returns
+	// ErrNotFound when there is no such upload.
+	GetUpload(ctx context.Context, id string) (Upload, error)
+	// DeleteUpload removes the resumable upload with the given ID. It
+	// returns ErrNotFound when there is no such upload.
+	DeleteUpload(ctx context.Context, id string) error
+	// PruneUploads removes resumable uploads that expired before now and
+	// returns them, so that the caller can discard their stored parts.
+	PruneUploads(ctx context.Context, now time.Time) ([]Upload, error)
+}
+
+// Draft is an outstanding upload charged to its owner.
+type Draft struct {
+	Key string `json:"key"`
+	// Size is the declared size of the upload. It is zero when undeclared.
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is when the draft is cleaned up if it is not confirmed.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Upload is the state of a resumable upload into a draft. Its content is
+// stored as the parts of a multipart upload and, for the bytes that do not
+// fill a part yet, a pending object.
+type Upload struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+	// Key is the draft the upload completes into.
+	Key string `json:"key"`
+	// Size is the declared length of the upload; Offset is how many bytes
+	// of it were received.
+	Size        int64             `json:"size"`
+	Offset      int64             `json:"offset"`
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// MultipartID is the storage multipart upload. It is empty once the
+	// upload is complete.
+	MultipartID string       `json:"multipart_id,omitempty"`
+	Parts       []UploadPart `json:"parts,omitempty"`
+	// NextPart is the lowest part number no writer has claimed yet.
+	NextPart int `json:"next_part,omitempty"`
+	// Pending tells apart the objects that held the received bytes that do
+	// not fill a part yet; see PendingName.
+	Pending   string    `json:"pending,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is when the upload is discarded if it is not complete.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Version identifies the stored state the upload was read as. It is
+	// maintained by the repository and empty for uploads not saved yet.
+	Version string `json:"-"`
+}
+
+// Complete reports whether every byte of the upload was received.
+func (u Upload) Complete() bool {
+	return u.Offset == u.Size && u.MultipartID == ""
+}
+
+// PendingName returns the name of the object holding the pending bytes of
+// the upload. Every write of them uses a new name, so that a racing writer
+// never replaces the bytes another one recorded.
+func (u Upload) PendingName() string {
+	if u.Pending == "" {
+		return u.ID
+	}
+	return u.ID + "." + u.Pending
+}
+
+// NextPartNumber returns the part number the next part is stored as.
+// Numbers are never handed out twice, so parts stored by racing writers do
+// not replace each other.
+func (u Upload) NextPartNumber() int {
+	if u.NextPart > 0 {
+		return u.NextPart
+	}
+	return len(u.Parts) + 1
+}
+
+// UploadPart is a stored part of a resumable upload.
+type UploadPart struct {
+	Number int    `json:"number"`
+	ETag   string `json:"etag"`
+	Size   int64  `json:"size"`
+}
+
+// Usage is what an owner currently consumes.
+type Usage struct {
+	Drafts         int64 `json:"drafts"`
+	DraftBytes     int64 `json:"draft_bytes"`
+	ConfirmedBytes int64 `json:"confirmed_bytes"`
+}
+
+// Quota limits an owner's usage. Zero fields are unlimited.
+type Quota struct {
+	MaxDrafts         int64 `json:"max_drafts"`
+	MaxDraftBytes     int64 `json:"max_draft_bytes"`
+	MaxConfirmedBytes int64 `json:"max_confirmed_bytes"`
+}
+
+// Enabled reports whether the quota limits anything.
+func (q Quota) Enabled() bool {
+	return q.MaxDrafts > 0 || q.MaxDraftBytes > 0 || q.MaxConfirmedBytes > 0
+}
+
+// Check returns an error wrapping ErrQuotaExceeded when usage exceeds the quota.
+func (q Quota) Check(usage Usage) error {
+	switch {
+	case q.MaxDrafts > 0 && usage.Drafts > q.MaxDrafts:
+		return fmt.Errorf("%w: %d of %d drafts", ErrQuotaExceeded, usage.Drafts, q.MaxDrafts)
+	case q.MaxDraftBytes > 0 && usage.DraftBytes > q.MaxDraftBytes:
+		return fmt.Errorf("%w: %d of %d draft bytes", ErrQuotaExceeded, usage.DraftBytes, q.MaxDraftBytes)
+	case q.MaxConfirmedBytes > 0 && usage.ConfirmedBytes > q.MaxConfirmedBytes:
+		return fmt.Errorf("%w: %d of %d confirmed bytes", ErrQuotaExceeded, usage.ConfirmedBytes, q.MaxConfirmedBytes)
+	}
+	return nil
+}
+
+// ledger is the state of a single owner, shared by the implementations.
+type ledger struct {
+	Owner          string `json:"owner"`
+	ConfirmedBytes int64  `json:"confirmed_bytes"`
+	// Objects holds the confirmed size of every object key, so that an
+	// overwrite is charged the difference to the previous content.
+	Objects map[string]int64 `json:"objects,omitempty"`
+	Drafts  []Draft          `json:"drafts"`
+}
+
+func (l *ledger) usage(now time.Time) Usage {
+	usage := Usage{ConfirmedBytes: l.ConfirmedBytes}
+	for _, draft := range l.Drafts {
+		if draft.ExpiresAt.After(now) {
+			usage.Drafts++
+			usage.DraftBytes += draft.Size
+		}
+	}
+	return usage
+}
+
+func (l *ledger) reserve(draft Draft, quota Quota, now time.Time) error {
+	usage := l.usage(now)
+	for _, existing := range l.Drafts {
+		if existing.Key == draft.Key && existing.ExpiresAt.After(now) {
+			usage.Drafts--
+			usage.DraftBytes -= existing.Size
+		}
+	}
+
+	// The draft will be confirmed eventually, so it must fit the confirmed quota too
+	usage.Drafts++
+	usage.DraftBytes += draft.Size
+	usage.ConfirmedBytes += draft.Size
+	if err := quota.Check(usage); err != nil {
+		return err
+	}
+
+	l.release(draft.Key)
+	l.Drafts = append(l.Drafts, draft)
+	return nil
+}
+
+func (l *ledger) release(key string) (Draft, bool) {
+	for i, draft := range l.Drafts {
+		if draft.Key == key {
+			l.Drafts = slices.Delete(l.Drafts, i, i+1)
+			return draft, true
+		}
+	}
+	return Draft{}, false
+}
+
+func (l *ledger) confirmObject(key string, size int64, quota Quota) (int64, error) {
+	previous := l.Objects[key]
+	confirmed := l.ConfirmedBytes - previous + size
+	if size > previous {
+		if err := quota.Check(Usage{ConfirmedBytes: confirmed}); err != nil {
+			return 0, err
+		}
+	}
+
+	if l.Objects == nil {
+		l.Objects = make(map[string]int64)
+	}
+	if size > 0 {
+		l.Objects[key] = size
+	} else {
+		delete(l.Objects, key)
+	}
+	l.ConfirmedBytes = confirmed
+	return previous, nil
+}
+
+// prune removes the drafts that expired before now and returns them.
+func (l *ledger) prune(now time.Time) []Draft {
+	var pruned []Draft
+	l.Drafts = slices.DeleteFunc(l.Drafts, func(draft Draft) bool {
+		if draft.ExpiresAt.After(now) {
+			return false
+		}
+		pruned = append(pruned, draft)
+		return true
+	})
+	return pruned
+}
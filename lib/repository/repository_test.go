@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+// fakeStorage keeps objects in memory and honors the conditions the
+// StorageRepository writes with. Other storage methods are not used.
+type fakeStorage struct {
+	storage.Storage
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	version int
+}
+
+type fakeObject struct {
+	data []byte
+	etag string
+}
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{objects: make(map[string]fakeObject)}
+}
+
+func (f *fakeStorage) StatObject(ctx context.Context, bucketName, objectName string) (storage.ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[objectName]
+	if !ok {
+		return storage.ObjectInfo{}, fmt.Errorf("%w: %s", storage.ErrObjectNotFound, objectName)
+	}
+	return storage.ObjectInfo{Key: objectName, Size: int64(len(object.data)), ETag: object.etag}, nil
+}
+
+func (f *fakeStorage) GetObject(ctx context.Context, bucketName, objectName string, opts storage.GetObjectOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[objectName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", storage.ErrObjectNotFound, objectName)
+	}
+	if opts.IfMatch != "" && opts.IfMatch != object.etag {
+		return nil, fmt.Errorf("%w: %s", storage.ErrPreconditionFailed, objectName)
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+func (f *fakeStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts storage.PutObjectOptions) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing, ok := f.objects[objectName]
+	if (opts.IfNotExists && ok) || (opts.IfMatch != "" && (!ok || existing.etag != opts.IfMatch)) {
+		return "", fmt.Errorf("%w: %s", storage.ErrPreconditionFailed, objectName)
+	}
+	f.version++
+	etag := strconv.Itoa(f.version)
+	f.objects[objectName] = fakeObject{data: data, etag: etag}
+	return etag, nil
+}
+
+func (f *fakeStorage) ListObjects(ctx context.Context, bucketName, prefix string) ([]storage.ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var objects []storage.ObjectInfo
+	for name, object := range f.objects {
+		if strings.HasPrefix(name, prefix) {
+			objects = append(objects, storage.ObjectInfo{Key: name, Size: int64(len(object.data)), ETag: object.etag})
+		}
+	}
+	slices.SortFunc(objects, func(a, b storage.ObjectInfo) int { return strings.Compare(a.Key, b.Key) })
+	return objects, nil
+}
+
+func (f *fakeStorage) DeleteObject(ctx context.Context, bucketName, objectName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.objects[objectName]; !ok {
+		return fmt.Errorf("%w: %s", storage.ErrObjectNotFound, objectName)
+	}
+	delete(f.objects, objectName)
+	return nil
+}
+
+func testRepositories(t *testing.T) map[string]Repository {
+	t.Helper()
+
+	storageRepository, err := NewStorageRepository(StorageRepositoryOptions{
+		Storage:    newFakeStorage(),
+		BucketName: "test",
+	})
+	if err != nil {
+		t.Fatalf("failed to create storage repository: %v", err)
+	}
+	return map[string]Repository{
+		"memory":  NewMemoryRepository(),
+		"storage": storageRepository,
+	}
+}
+
+func TestRepositoryLedger(t *testing.T) {
+	now := time.Now()
+	draft := func(key string, size int64) Draft {
+		return Draft{Key: key, Size: size, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	}
+	quota := Quota{MaxDrafts: 2, MaxDraftBytes: 100, MaxConfirmedBytes: 150}
+	owner := func(key, want string) func(ctx context.Context, r Repository) error {
+		return func(ctx context.Context, r Repository) error {
+			got, err := r.DraftOwner(ctx, key)
+			if err != nil {
+				return err
+			}
+			if got != want {
+				return fmt.Errorf("owner of %s = %q, want %q", key, got, want)
+			}
+			return nil
+		}
+	}
+
+	// Every step runs on the state the steps before it left; want is alice's
+	// usage afterwards
+	steps := []struct {
+		name    string
+		run     func(ctx context.Context, r Repository) error
+		wantErr error
+		want    Usage
+	}{
+		{
+			name: "reserve",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("a", 40), quota)
+			},
+			want: Usage{Drafts: 1, DraftBytes: 40},
+		},
+		{
+			name: "reserve another",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("b", 50), quota)
+			},
+			want: Usage{Drafts: 2, DraftBytes: 90},
+		},
+		{
+			name: "reserve beyond draft count",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("c", 10), quota)
+			},
+			wantErr: ErrQuotaExceeded,
+			want:    Usage{Drafts: 2, DraftBytes: 90},
+		},
+		{
+			name: "reserve same key again",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("a", 30), quota)
+			},
+			want: Usage{Drafts: 2, DraftBytes: 80},
+		},
+		{
+			name: "reserve same key beyond draft bytes",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("a", 70), quota)
+			},
+			wantErr: ErrQuotaExceeded,
+			want:    Usage{Drafts: 2, DraftBytes: 80},
+		},
+		{name: "owner recorded", run: owner("a", "alice"), want: Usage{Drafts: 2, DraftBytes: 80}},
+		{
+			name: "release as other owner",
+			run: func(ctx context.Context, r Repository) error {
+				_, err := r.ReleaseDraft(ctx, "bob", "a")
+				return err
+			},
+			wantErr: ErrNotFound,
+			want:    Usage{Drafts: 2, DraftBytes: 80},
+		},
+		{
+			name: "release",
+			run: func(ctx context.Context, r Repository) error {
+				released, err := r.ReleaseDraft(ctx, "alice", "a")
+				if err == nil && released.Size != 30 {
+					return fmt.Errorf("released %d bytes, want 30", released.Size)
+				}
+				return err
+			},
+			want: Usage{Drafts: 1, DraftBytes: 50},
+		},
+		{name: "owner forgotten on release", run: owner("a", ""), wantErr: ErrNotFound, want: Usage{Drafts: 1, DraftBytes: 50}},
+		{
+			name: "confirm",
+			run: func(ctx context.Context, r Repository) error {
+				_, err := r.ConfirmObject(ctx, "alice", "a", 30, quota)
+				return err
+			},
+			want: Usage{Drafts: 1, DraftBytes: 50, ConfirmedBytes: 30},
+		},
+		{
+			name: "confirm overwrite",
+			run: func(ctx context.Context, r Repository) error {
+				previous, err := r.ConfirmObject(ctx, "alice", "a", 100, quota)
+				if err == nil && previous != 30 {
+					return fmt.Errorf("previous size = %d, want 30", previous)
+				}
+				return err
+			},
+			want: Usage{Drafts: 1, DraftBytes: 50, ConfirmedBytes: 100},
+		},
+		{
+			name: "confirm beyond confirmed bytes",
+			run: func(ctx context.Context, r Repository) error {
+				_, err := r.ConfirmObject(ctx, "alice", "x", 60, quota)
+				return err
+			},
+			wantErr: ErrQuotaExceeded,
+			want:    Usage{Drafts: 1, DraftBytes: 50, ConfirmedBytes: 100},
+		},
+		{
+			name: "reserve beyond confirmed bytes once confirmed",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("c", 60), Quota{MaxConfirmedBytes: 150})
+			},
+			wantErr: ErrQuotaExceeded,
+			want:    Usage{Drafts: 1, DraftBytes: 50, ConfirmedBytes: 100},
+		},
+		{
+			name: "reserve within quota",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "alice", draft("c", 10), quota)
+			},
+			want: Usage{Drafts: 2, DraftBytes: 60, ConfirmedBytes: 100},
+		},
+		{
+			name: "reserve key as other owner",
+			run: func(ctx context.Context, r Repository) error {
+				return r.ReserveDraft(ctx, "bob", draft("c", 10), Quota{})
+			},
+			want: Usage{Drafts: 2, DraftBytes: 60, ConfirmedBytes: 100},
+		},
+		{name: "owner replaced", run: owner("c", "bob"), want: Usage{Drafts: 2, DraftBytes: 60, ConfirmedBytes: 100}},
+		{
+			name: "confirm removal",
+			run: func(ctx context.Context, r Repository) error {
+				_, err := r.ConfirmObject(ctx, "alice", "a", 0, quota)
+				return err
+			},
+			want: Usage{Drafts: 2, DraftBytes: 60},
+		},
+		{
+			name: "prune",
+			run: func(ctx context.Context, r Repository) error {
+				pruned, err := r.PruneDrafts(ctx, now.Add(2*time.Hour))
+				if err == nil && pruned != 3 {
+					return fmt.Errorf("pruned %d drafts, want 3", pruned)
+				}
+				return err
+			},
+			want: Usage{},
+		},
+		{name: "owner forgotten on prune", run: owner("c", ""), wantErr: ErrNotFound, want: Usage{}},
+	}
+
+	for name, repository := range testRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, step := range steps {
+				err := step.run(ctx, repository)
+				if step.wantErr == nil && err != nil {
+					t.Fatalf("%s: error = %v", step.name, err)
+				}
+				if step.wantErr != nil && !errors.Is(err, step.wantErr) {
+					t.Fatalf("%s: error = %v, want %v", step.name, err, step.wantErr)
+				}
+
+				usage, err := repository.Usage(ctx, "alice")
+				if err != nil {
+					t.Fatalf("%s: Usage() error = %v", step.name, err)
+				}
+				if usage != step.want {
+					t.Fatalf("%s: usage = %+v, want %+v", step.name, usage, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestStorageRepositoryConcurrentReplicas(t *testing.T) {
+	const reservations = 20
+
+	// Replicas share the bucket but not the lock that serializes local updates
+	fake := newFakeStorage()
+	var replicas []*StorageRepository
+	for range 2 {
+		replica, err := NewStorageRepository(StorageRepositoryOptions{Storage: fake, BucketName: "test"})
+		if err != nil {
+			t.Fatalf("failed to create storage repository: %v", err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, reservations)
+	for i := range reservations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- replicas[i%len(replicas)].ReserveDraft(ctx, "alice", Draft{
+				Key:       fmt.Sprintf("draft-%d", i),
+				Size:      1,
+				CreatedAt: now,
+				ExpiresAt: now.Add(time.Hour),
+			}, Quota{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	failed := 0
+	for err := range errs {
+		if err != nil {
+			if !errors.Is(err, ErrConflict) {
+				t.Fatalf("ReserveDraft() error = %v", err)
+			}
+			failed++
+		}
+	}
+
+	usage, err := replicas[0].Usage(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	if usage.Drafts != int64(reservations-failed) {
+		t.Errorf("usage counts %d drafts, want %d", usage.Drafts, reservations-failed)
+	}
+}
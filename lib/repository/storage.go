@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// DefaultUsagePrefix is where StorageRepository keeps one JSON document per
+// owner. The draft service rejects object names under it.
+const DefaultUsagePrefix = ".usage/"
+
+// uploadsDir holds one JSON document per resumable upload under the prefix,
+// and draftsDir one per draft key naming the owner that reserved it. Owner
+// documents never collide with them because their names are escaped.
+const (
+	uploadsDir = "uploads/"
+	draftsDir  = "drafts/"
+)
+
+const (
+	// maxUpdateAttempts bounds how often an update that lost a race with
+	// another writer is retried before it fails with ErrConflict.
+	maxUpdateAttempts = 8
+	// updateBackoff is the base delay between those attempts.
+	updateBackoff = 20 * time.Millisecond
+)
+
+// errUnchanged is returned by update functions that leave a document as is.
+var errUnchanged = errors.New("document unchanged")
+
+var _ Repository = (*StorageRepository)(nil)
+
+// StorageRepository keeps records as JSON documents in a bucket, so that
+// every replica and the cleanup job share them. Every update is a
+// read-modify-write conditioned on the ETag that was read, and is retried
+// when another replica wrote the document in between, so concurrent updates
+// are never lost.
+type StorageRepository struct {
+	storage storage.Storage
+	bucket  string
+	prefix  string
+	mu      sync.Mutex
+}
+
+type StorageRepositoryOptions struct {
+	Storage storage.Storage
+	// BucketName holds the documents. It should not be subject to draft cleanup.
+	BucketName string
+	// Prefix defaults to DefaultUsagePrefix.
+	Prefix string
+}
+
+func NewStorageRepository(opts StorageRepositoryOptions) (*StorageRepository, error) {
+	log := logger.GetServiceLogger("storage-repository")
+
+	if opts.Storage == nil || opts.BucketName == "" {
+		return nil, errors.New("storage and bucket name are required")
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultUsagePrefix
+	}
+
+	repository := &StorageRepository{
+		storage: opts.Storage,
+		bucket:  opts.BucketName,
+		prefix:  opts.Prefix,
+	}
+
+	log.Info().
+		Str("bucket", repository.bucket).
+		Str("prefix", repository.prefix).
+		Msg("Storage repository initialized")
+
+	return repository, nil
+}
+
+func (r *StorageRepository) documentKey(owner string) string {
+	return r.prefix + url.PathEscape(owner) + ".json"
+}
+
+func (r *StorageRepository) uploadKey(id string) string {
+	return r.prefix + uploadsDir + url.PathEscape(id) + ".json"
+}
+
+func (r *StorageRepository) draftKey(key string) string {
+	return r.prefix + draftsDir + url.PathEscape(key) + ".json"
+}
+
+// draftDocument records the owner that reserved a draft.
+type draftDocument struct {
+	Owner string `json:"owner"`
+}
+
+// read decodes the document key into v and returns its ETag. It returns
+// ErrNotFound when there is no such document.
+func (r *StorageRepository) read(ctx context.Context, key string, v any) (string, error) {
+	info, err := r.storage.StatObject(ctx, r.bucket, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to stat document %s: %w", key, err)
+	}
+
+	// The read is pinned to the ETag the next write is conditioned on
+	reader, err := r.storage.GetObject(ctx, r.bucket, key, storage.GetObjectOptions{IfMatch: info.ETag})
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read document %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read document %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return "", fmt.Errorf("failed to decode document %s: %w", key, err)
+	}
+	return info.ETag, nil
+}
+
+// write stores v as the document key, provided it still has etag, and
+// returns the new ETag. An empty etag requires that the document does not
+// exist yet.
+func (r *StorageRepository) write(ctx context.Context, key string, v any, etag string) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode document %s: %w", key, err)
+	}
+	etag, err = r.storage.PutObject(ctx, r.bucket, key, bytes.NewReader(data), int64(len(data)), storage.PutObjectOptions{
+		ContentType: "application/json",
+		IfMatch:     etag,
+		IfNotExists: etag == "",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write document %s: %w", key, err)
+	}
+	return etag, nil
+}
+
+// retry runs fn until it does not fail with storage.ErrPreconditionFailed,
+// at most maxUpdateAttempts times.
+func (r *StorageRepository) retry(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !errors.Is(err, storage.ErrPreconditionFailed) {
+			return err
+		}
+		if attempt == maxUpdateAttempts {
+			return fmt.Errorf("%w: %w", ErrConflict, err)
+		}
+
+		// Jitter keeps racing replicas from colliding again
+		delay := updateBackoff*time.Duration(attempt) + rand.N(updateBackoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *StorageRepository) load(ctx context.Context, key string) (*ledger, string, error) {
+	var l ledger
+	etag, err := r.read(ctx, key, &l)
+	if errors.Is(err, ErrNotFound) {
+		return &ledger{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return &l, etag, nil
+}
+
+// updateLedger applies fn to the ledger stored as key and writes it back
+// unless fn fails or returns errUnchanged. fn is applied again to the
+// current ledger when another writer got there first.
+func (r *StorageRepository) updateLedger(ctx context.Context, key string, fn func(l *ledger) error) error {
+	// Serializing local updates saves them from retrying against each other
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.retry(ctx, func() error {
+		l, etag, err := r.load(ctx, key)
+		if err != nil {
+			return err
+		}
+		if err := fn(l); err != nil {
+			if errors.Is(err, errUnchanged) {
+				return nil
+			}
+			return err
+		}
+		_, err = r.write(ctx, key, l, etag)
+		return err
+	})
+}
+
+// update applies fn to the owner's ledger and stores it when fn succeeds.
+func (r *StorageRepository) update(ctx context.Context, owner string, fn func(l *ledger) error) error {
+	return r.updateLedger(ctx, r.documentKey(owner), func(l *ledger) error {
+		l.Owner = owner
+		return fn(l)
+	})
+}
+
+// Usage implements Repository.
+func (r *StorageRepository) Usage(ctx context.Context, owner string) (Usage, error) {
+	l, _, err := r.load(ctx, r.documentKey(owner))
+	if err != nil {
+		return Usage{}, err
+	}
+	return l.usage(time.Now()), nil
+}
+
+// ReserveDraft implements Repository.
+func (r *StorageRepository) ReserveDraft(ctx context.Context, owner string, draft Draft, quota Quota) error {
+	err := r.update(ctx, owner, func(l *ledger) error {
+		return l.reserve(draft, quota, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	// The owner is recorded after the reservation, so that it never names an
+	// owner that was refused
+	if err := r.setDraftOwner(ctx, draft.Key, owner); err != nil {
+		if _, rErr := r.ReleaseDraft(ctx, owner, draft.Key); rErr != nil {
+			return errors.Join(err, rErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// ReleaseDraft implements Repository.
+func (r *StorageRepository) ReleaseDraft(ctx context.Context, owner, key string) (Draft, error) {
+	var released Draft
+	err := r.update(ctx, owner, func(l *ledger) error {
+		draft, ok := l.release(key)
+		if !ok {
+			return ErrNotFound
+		}
+		released = draft
+		return nil
+	})
+	if err != nil {
+		return Draft{}, err
+	}
+	if err := r.forgetDraftOwner(ctx, key, owner); err != nil {
+		return Draft{}, err
+	}
+	return released, nil
+}
+
+// DraftOwner implements Repository.
+func (r *StorageRepository) DraftOwner(ctx context.Context, key string) (string, error) {
+	var document draftDocument
+	if _, err := r.read(ctx, r.draftKey(key), &document); err != nil {
+		return "", err
+	}
+	return document.Owner, nil
+}
+
+// setDraftOwner records owner as the owner that reserved the draft key.
+func (r *StorageRepository) setDraftOwner(ctx context.Context, key, owner string) error {
+	documentKey := r.draftKey(key)
+	return r.retry(ctx, func() error {
+		var document draftDocument
+		etag, err := r.read(ctx, documentKey, &document)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if document.Owner == owner {
+			return nil
+		}
+		_, err = r.write(ctx, documentKey, draftDocument{Owner: owner}, etag)
+		return err
+	})
+}
+
+// forgetDraftOwner removes the record of the owner of the draft key unless
+// another owner reserved it since. Deletes are not conditional, so a
+// reservation that races with it may lose its record; its release then
+// falls back to the releasing caller.
+func (r *StorageRepository) forgetDraftOwner(ctx context.Context, key, owner string) error {
+	documentKey := r.draftKey(key)
+	var document draftDocument
+	if _, err := r.read(ctx, documentKey, &document); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if document.Owner != owner {
+		return nil
+	}
+	if err := r.storage.DeleteObject(ctx, r.bucket, documentKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return fmt.Errorf("failed to delete draft document %s: %w", documentKey, err)
+	}
+	return nil
+}
+
+// ConfirmObject implements Repository.
+func (r *StorageRepository) ConfirmObject(ctx context.Context, owner, key string, size int64, quota Quota) (int64, error) {
+	var previous int64
+	err := r.update(ctx, owner, func(l *ledger) error {
+		var err error
+		previous, err = l.confirmObject(key, size, quota)
+		return err
+	})
+	return previous, err
+}
+
+// PruneDrafts implements Repository.
+func (r *StorageRepository) PruneDrafts(ctx context.Context, now time.Time) (int, error) {
+	documents, err := r.storage.ListObjects(ctx, r.bucket, r.prefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list usage documents: %w", err)
+	}
+
+	pruned := 0
+	for _, document := range documents {
+		if !strings.HasSuffix(document.Key, ".json") ||
+			strings.HasPrefix(document.Key, r.prefix+uploadsDir) ||
+			strings.HasPrefix(document.Key, r.prefix+draftsDir) {
+			continue
+		}
+
+		var owner string
+		var drafts []Draft
+		err := r.updateLedger(ctx, document.Key, func(l *ledger) error {
+			owner = l.Owner
+			if drafts = l.prune(now); len(drafts) == 0 {
+				return errUnchanged
+			}
+			return nil
+		})
+		if err != nil {
+			return pruned, err
+		}
+		pruned += len(drafts)
+
+		for _, draft := range drafts {
+			if err := r.forgetDraftOwner(ctx, draft.Key, owner); err != nil {
+				return pruned, err
+			}
+		}
+	}
+	return pruned, nil
+}
+
+func (r *StorageRepository) loadUpload(ctx context.Context, key string) (Upload, error) {
+	var upload Upload
+	etag, err := r.read(ctx, key, &upload)
+	if err != nil {
+		return Upload{}, err
+	}
+	upload.Version = etag
+	return upload, nil
+}
+
+// SaveUpload implements Repository.
+func (r *StorageRepository) SaveUpload(ctx context.Context, upload *Upload) error {
+	etag, err := r.write(ctx, r.uploadKey(upload.ID), upload, upload.Version)
+	if errors.Is(err, storage.ErrPreconditionFailed) {
+		return fmt.Errorf("%w: upload %s: %w", ErrConflict, upload.ID, err)
+	}
+	if err != nil {
+		return err
+	}
+	upload.Version = etag
+	return nil
+}
+
+// GetUpload implements Repository.
+func (r *StorageRepository) GetUpload(ctx context.Context, id string) (Upload, error) {
+	return r.loadUpload(ctx, r.uploadKey(id))
+}
+
+// DeleteUpload implements Repository.
+func (r *StorageRepository) DeleteUpload(ctx context.Context, id string) error {
+	key := r.uploadKey(id)
+	if _, err := r.storage.StatObject(ctx, r.bucket, key); err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to stat upload document %s: %w", key, err)
+	}
+	if err := r.storage.DeleteObject(ctx, r.bucket, key); err != nil {
+		return fmt.Errorf("failed to delete upload document %s: %w", key, err)
+	}
+	return nil
+}
+
+// PruneUploads implements Repository.
+func (r *StorageRepository) PruneUploads(ctx context.Context, now time.Time) ([]Upload, error) {
+	documents, err := r.storage.ListObjects(ctx, r.bucket, r.prefix+uploadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upload documents: %w", err)
+	}
+
+	var pruned []Upload
+	for _, document := range documents {
+		if !strings.HasSuffix(document.Key, ".json") {
+			continue
+		}
+
+		upload, err := r.loadUpload(ctx, document.Key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return pruned, err
+		}
+		if upload.ExpiresAt.After(now) {
+			continue
+		}
+		if err := r.storage.DeleteObject(ctx, r.bucket, document.Key); err != nil {
+			return pruned, fmt.Errorf("failed to delete upload document %s: %w", document.Key, err)
+		}
+		pruned = append(pruned, upload)
+	}
+	return pruned, nil
+}
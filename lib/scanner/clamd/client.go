@@ -0,0 +1,166 @@
+package clamd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/scanner"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DefaultChunkSize is the size of each INSTREAM chunk sent to clamd.
+	DefaultChunkSize = 64 * 1024
+	// DefaultTimeout bounds a whole scan when neither the context nor the options set one.
+	DefaultTimeout = 60 * time.Second
+)
+
+var _ scanner.Scanner = (*Client)(nil)
+
+type Client struct {
+	address   string
+	timeout   time.Duration
+	chunkSize int
+	dialer    net.Dialer
+}
+
+type ClientOptions struct {
+	// Address is the clamd TCP address, e.g. "clamav:3310".
+	Address   string
+	Timeout   time.Duration
+	ChunkSize int
+}
+
+func NewClient(opts ClientOptions) (*Client, error) {
+	log := logger.GetServiceLogger("clamd-scanner")
+
+	if opts.Address == "" {
+		return nil, errors.New("clamd address is required")
+	}
+
+	client := &Client{
+		address:   opts.Address,
+		timeout:   opts.Timeout,
+		chunkSize: opts.ChunkSize,
+	}
+	if client.timeout <= 0 {
+		client.timeout = DefaultTimeout
+	}
+	if client.chunkSize <= 0 {
+		client.chunkSize = DefaultChunkSize
+	}
+
+	log.Info().
+		Str("address", client.address).
+		Dur("timeout", client.timeout).
+		Int("chunk_size", client.chunkSize).
+		Msg("clamd scanner client initialized")
+
+	return client, nil
+}
+
+// Scan implements scanner.Scanner using the clamd INSTREAM command.
+func (c *Client) Scan(ctx context.Context, r io.Reader) (scanner.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return scanner.Result{}, fmt.Errorf("%w: failed to dial clamd: %w", scanner.ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return scanner.Result{}, fmt.Errorf("failed to set clamd deadline: %w", err)
+		}
+	}
+
+	// Unblock pending I/O when the context is cancelled before the deadline
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return scanner.Result{}, connError("failed to send clamd command", err)
+	}
+
+	writer := bufio.NewWriterSize(conn, c.chunkSize+4)
+	buf := make([]byte, c.chunkSize)
+	var size [4]byte
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := writer.Write(size[:]); err != nil {
+				return scanner.Result{}, connError("failed to stream to clamd", err)
+			}
+			if _, err := writer.Write(buf[:n]); err != nil {
+				return scanner.Result{}, connError("failed to stream to clamd", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return scanner.Result{}, fmt.Errorf("failed to read object for scanning: %w", readErr)
+		}
+	}
+
+	// A zero-length chunk terminates the stream
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := writer.Write(size[:]); err != nil {
+		return scanner.Result{}, connError("failed to stream to clamd", err)
+	}
+	if err := writer.Flush(); err != nil {
+		return scanner.Result{}, connError("failed to stream to clamd", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && !(errors.Is(err, io.EOF) && len(reply) > 0) {
+		return scanner.Result{}, connError("failed to read clamd reply", err)
+	}
+
+	return parseReply(string(bytes.TrimRight(reply, "\x00\n")))
+}
+
+// connError wraps an error of the clamd connection. Only timeouts mark clamd
+// as unavailable: a connection clamd closes or resets mid-scan, e.g. after
+// "INSTREAM size limit exceeded", is a failed scan.
+func connError(msg string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%w: %s: %w", scanner.ErrUnavailable, msg, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// parseReply interprets replies such as "stream: OK" and
+// "stream: Eicar-Test-Signature FOUND".
+func parseReply(reply string) (scanner.Result, error) {
+	_, verdict, ok := strings.Cut(reply, ": ")
+	if !ok {
+		return scanner.Result{}, fmt.Errorf("unexpected clamd reply: %q", reply)
+	}
+
+	switch {
+	case verdict == "OK":
+		return scanner.Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return scanner.Result{
+			Infected:  true,
+			Signature: strings.TrimSuffix(verdict, " FOUND"),
+		}, nil
+	default:
+		return scanner.Result{}, fmt.Errorf("clamd scan error: %s", verdict)
+	}
+}
@@ -0,0 +1,183 @@
+package clamd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/scanner"
+)
+
+func TestParseReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    scanner.Result
+		wantErr bool
+	}{
+		{name: "clean", reply: "stream: OK", want: scanner.Result{}},
+		{name: "infected", reply: "stream: Eicar-Test-Signature FOUND", want: scanner.Result{Infected: true, Signature: "Eicar-Test-Signature"}},
+		{name: "size limit", reply: "INSTREAM size limit exceeded. ERROR", wantErr: true},
+		{name: "scan error", reply: "stream: Can't allocate memory ERROR", wantErr: true},
+		{name: "empty", reply: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReply(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseReply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeClamd serves one INSTREAM scan per connection with reply. It returns
+// the address it listens on and the content of the last scan.
+func fakeClamd(t *testing.T, reply func(conn net.Conn, content []byte)) (string, <-chan []byte) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	scanned := make(chan []byte, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				content, err := readInstream(conn)
+				if err != nil {
+					return
+				}
+				scanned <- content
+				reply(conn, content)
+			}()
+		}
+	}()
+	return listener.Addr().String(), scanned
+}
+
+// readInstream reads a zINSTREAM command and its chunks.
+func readInstream(conn net.Conn) ([]byte, error) {
+	reader := bufio.NewReader(conn)
+	command, err := reader.ReadString(0)
+	if err != nil {
+		return nil, err
+	}
+	if command != "zINSTREAM\x00" {
+		return nil, errors.New("unexpected command " + command)
+	}
+
+	var content bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return content.Bytes(), nil
+		}
+		if _, err := io.CopyN(&content, reader, int64(size)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func replyWith(reply string) func(conn net.Conn, content []byte) {
+	return func(conn net.Conn, content []byte) {
+		io.WriteString(conn, reply+"\x00")
+	}
+}
+
+func TestClientScan(t *testing.T) {
+	content := strings.Repeat("draft content ", 1000)
+
+	tests := []struct {
+		name            string
+		reply           func(conn net.Conn, content []byte)
+		want            scanner.Result
+		wantErr         bool
+		wantUnavailable bool
+	}{
+		{name: "clean", reply: replyWith("stream: OK"), want: scanner.Result{}},
+		{name: "infected", reply: replyWith("stream: Eicar-Test-Signature FOUND"), want: scanner.Result{Infected: true, Signature: "Eicar-Test-Signature"}},
+		{name: "size limit", reply: replyWith("INSTREAM size limit exceeded. ERROR"), wantErr: true},
+		{
+			name: "connection reset",
+			reply: func(conn net.Conn, content []byte) {
+				conn.(*net.TCPConn).SetLinger(0)
+			},
+			wantErr: true,
+		},
+		{
+			name: "timeout",
+			reply: func(conn net.Conn, content []byte) {
+				time.Sleep(time.Second)
+			},
+			wantErr:         true,
+			wantUnavailable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, scanned := fakeClamd(t, tt.reply)
+			client, err := NewClient(ClientOptions{
+				Address:   address,
+				Timeout:   200 * time.Millisecond,
+				ChunkSize: 1024,
+			})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			got, err := client.Scan(context.Background(), strings.NewReader(content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, scanner.ErrUnavailable) != tt.wantUnavailable {
+				t.Errorf("Scan() error = %v, want unavailable %v", err, tt.wantUnavailable)
+			}
+			if got != tt.want {
+				t.Errorf("Scan() = %+v, want %+v", got, tt.want)
+			}
+			if received := <-scanned; string(received) != content {
+				t.Errorf("clamd received %d bytes, want %d", len(received), len(content))
+			}
+		})
+	}
+}
+
+func TestClientScanUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	client, err := NewClient(ClientOptions{Address: address})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Scan(context.Background(), strings.NewReader("content")); !errors.Is(err, scanner.ErrUnavailable) {
+		t.Errorf("Scan() error = %v, want %v", err, scanner.ErrUnavailable)
+	}
+}
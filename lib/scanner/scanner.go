@@ -0,0 +1,22 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrUnavailable is returned, wrapped, when the scanner cannot be reached or
+// does not answer in time. Any other error means the scan itself failed.
+var ErrUnavailable = errors.New("scanner unavailable")
+
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// Result is the verdict of a malware scan.
+type Result struct {
+	Infected bool
+	// Signature names the detected malware when Infected is true.
+	Signature string
+}
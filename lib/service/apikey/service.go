@@ -0,0 +1,238 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/auth/apikey"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// Service manages API keys. Every operation requires a caller explicitly
+// granted authz.OperationAdmin: an admin scoped API key, a configured admin
+// or a caller allowed by the authorizer.
+type Service struct {
+	store      apikey.Store
+	admins     []string
+	authorizer authz.Authorizer
+}
+
+type ServiceOptions struct {
+	Store apikey.Store
+	// Admins lists the callers other than API keys that may administer
+	// keys, as "method:subject", e.g. "jwt:alice".
+	Admins []string
+	// Authorizer decides which other callers may administer keys. It also
+	// applies to admin scoped API keys. Without it, only Admins and admin
+	// scoped keys are allowed.
+	Authorizer authz.Authorizer
+}
+
+// CreateKeyOptions describes a new API key.
+type CreateKeyOptions struct {
+	Name   string
+	Scopes []string
+	// Prefix restricts the object keys the key may act on.
+	Prefix string
+	// TTL makes the key expire. Zero creates a key that never expires.
+	TTL time.Duration
+}
+
+func NewService(opts ServiceOptions) (*Service, error) {
+	log := logger.GetServiceLogger("apikey-service")
+
+	if opts.Store == nil {
+		return nil, errors.New("api key store is required")
+	}
+
+	service := &Service{
+		store:      opts.Store,
+		admins:     opts.Admins,
+		authorizer: opts.Authorizer,
+	}
+
+	log.Info().
+		Strs("admins", service.admins).
+		Bool("authorizer_enabled", opts.Authorizer != nil).
+		Msg("API key service initialized")
+
+	return service, nil
+}
+
+func (s *Service) authorize(ctx context.Context) error {
+	principal := auth.FromContext(ctx)
+	if principal.Anonymous() {
+		return fmt.Errorf("%w: api key management requires an authenticated caller", authz.ErrAccessDenied)
+	}
+
+	req := authz.Request{
+		Operation: authz.OperationAdmin,
+		Principal: principal,
+	}
+
+	// Admin rights are never implied: keys need the admin scope and other
+	// callers need to be listed or allowed by the authorizer
+	var allowed bool
+	var err error
+	switch {
+	case principal.Method == auth.MethodAPIKey:
+		authorizer := authz.All{apikey.ScopeAuthorizer{}}
+		if s.authorizer != nil {
+			authorizer = append(authorizer, s.authorizer)
+		}
+		allowed, err = authorizer.Authorize(ctx, req)
+	case slices.Contains(s.admins, principal.Method+":"+principal.Subject):
+		allowed = true
+	case s.authorizer != nil:
+		allowed, err = s.authorizer.Authorize(ctx, req)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to authorize: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s may not manage api keys", authz.ErrAccessDenied, principal.Subject)
+	}
+	return nil
+}
+
+// CreateKey creates a key and returns it with its secret. The secret cannot
+// be recovered later.
+func (s *Service) CreateKey(ctx context.Context, opts CreateKeyOptions) (apikey.Key, string, error) {
+	log := logger.GetServiceLogger("apikey-service").With().
+		Str("operation", "create_key").
+		Str("name", opts.Name).
+		Strs("scopes", opts.Scopes).
+		Str("prefix", opts.Prefix).
+		Logger()
+
+	if err := s.authorize(ctx); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	if err := apikey.ValidateScopes(opts.Scopes); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	// A prefix restricted caller cannot mint keys reaching beyond its prefix
+	if prefix := auth.FromContext(ctx).Attributes[apikey.AttributePrefix]; prefix != "" && !strings.HasPrefix(opts.Prefix, prefix) {
+		return apikey.Key{}, "", fmt.Errorf("failed to create api key: %w: prefix must be within %q", authz.ErrAccessDenied, prefix)
+	}
+
+	id, secret, err := apikey.Generate()
+	if err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	key := apikey.Key{
+		ID:        id,
+		Name:      opts.Name,
+		Hash:      apikey.Hash(secret),
+		Scopes:    opts.Scopes,
+		Prefix:    opts.Prefix,
+		CreatedAt: now,
+	}
+	if opts.TTL > 0 {
+		expiresAt := now.Add(opts.TTL)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.store.Put(ctx, key); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to store api key")
+		return apikey.Key{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	logger.LogStateChange("create", "api_key", id, nil, map[string]interface{}{
+		"name":       key.Name,
+		"scopes":     key.Scopes,
+		"prefix":     key.Prefix,
+		"expires_at": key.ExpiresAt,
+		"created_by": auth.FromContext(ctx).Subject,
+	})
+
+	return key, secret, nil
+}
+
+func (s *Service) ListKeys(ctx context.Context) ([]apikey.Key, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeKey disables a key immediately.
+func (s *Service) RevokeKey(ctx context.Context, id string) error {
+	if err := s.authorize(ctx); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	key, err := s.store.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+	if err := s.store.Put(ctx, key); err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	logger.LogStateChange("revoke", "api_key", id, nil, map[string]interface{}{
+		"revoked_at": now,
+		"revoked_by": auth.FromContext(ctx).Subject,
+	})
+	return nil
+}
+
+// RotateKey creates a key with the same name, scopes and prefix and revokes
+// the old one after gracePeriod, giving callers time to switch.
+func (s *Service) RotateKey(ctx context.Context, id string, gracePeriod time.Duration) (apikey.Key, string, error) {
+	if err := s.authorize(ctx); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to rotate api key: %w", err)
+	}
+
+	old, err := s.store.Get(ctx, id)
+	if err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to rotate api key: %w", err)
+	}
+
+	opts := CreateKeyOptions{
+		Name:   old.Name,
+		Scopes: old.Scopes,
+		Prefix: old.Prefix,
+	}
+	if old.ExpiresAt != nil {
+		opts.TTL = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+
+	key, secret, err := s.CreateKey(ctx, opts)
+	if err != nil {
+		return apikey.Key{}, "", err
+	}
+
+	revokeAt := time.Now().UTC().Add(gracePeriod)
+	if old.RevokedAt == nil || old.RevokedAt.After(revokeAt) {
+		old.RevokedAt = &revokeAt
+	}
+	if err := s.store.Put(ctx, old); err != nil {
+		return apikey.Key{}, "", fmt.Errorf("failed to revoke rotated api key: %w", err)
+	}
+
+	logger.LogStateChange("rotate", "api_key", id, nil, map[string]interface{}{
+		"replaced_by": key.ID,
+		"revoked_at":  old.RevokedAt,
+	})
+
+	return key, secret, nil
+}
@@ -0,0 +1,81 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	authapikey "github.com/snowmerak/DraftStore/lib/auth/apikey"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/service/apikey"
+)
+
+// subjectAuthorizer allows the admin operation to one subject, like a rule
+// such as principal.sub == "ops" would.
+type subjectAuthorizer string
+
+func (a subjectAuthorizer) Authorize(ctx context.Context, req authz.Request) (bool, error) {
+	return req.Principal.Subject == string(a), nil
+}
+
+func TestServiceAdminGrant(t *testing.T) {
+	jwt := func(subject string) auth.Principal {
+		return auth.Principal{Subject: subject, Method: auth.MethodJWT}
+	}
+	mtls := auth.Principal{Subject: "CN=ops", Method: auth.MethodMTLS}
+	key := func(scopes string) auth.Principal {
+		return auth.Principal{
+			Subject:    "3f2a9c1d7b6e5a40",
+			Method:     auth.MethodAPIKey,
+			Attributes: map[string]string{authapikey.AttributeScopes: scopes},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		admins     []string
+		authorizer authz.Authorizer
+		principal  auth.Principal
+		want       bool
+	}{
+		{name: "anonymous caller", principal: auth.Principal{}, want: false},
+		{name: "token without grant", principal: jwt("alice"), want: false},
+		{name: "certificate without grant", principal: mtls, want: false},
+		{name: "listed token", admins: []string{"jwt:alice"}, principal: jwt("alice"), want: true},
+		{name: "listed certificate", admins: []string{"mtls:CN=ops"}, principal: mtls, want: true},
+		{name: "subject listed for another method", admins: []string{"mtls:alice"}, principal: jwt("alice"), want: false},
+		{name: "token allowed by rules", authorizer: subjectAuthorizer("alice"), principal: jwt("alice"), want: true},
+		{name: "token denied by rules", authorizer: subjectAuthorizer("bob"), principal: jwt("alice"), want: false},
+		{name: "admin scoped key", principal: key("admin"), want: true},
+		{name: "key without admin scope", principal: key("upload confirm"), want: false},
+		{name: "admin scoped key denied by rules", authorizer: subjectAuthorizer("alice"), principal: key("admin"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := authapikey.NewFileStore(filepath.Join(t.TempDir(), "apikeys.json"))
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+			service, err := apikey.NewService(apikey.ServiceOptions{
+				Store:      store,
+				Admins:     tt.admins,
+				Authorizer: tt.authorizer,
+			})
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+			_, err = service.ListKeys(ctx)
+			if tt.want && err != nil {
+				t.Errorf("ListKeys() error = %v", err)
+			}
+			if !tt.want && !errors.Is(err, authz.ErrAccessDenied) {
+				t.Errorf("ListKeys() error = %v, want %v", err, authz.ErrAccessDenied)
+			}
+		})
+	}
+}
@@ -2,44 +2,68 @@ package cleaner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/snowmerak/DraftStore/lib/repository"
 	"github.com/snowmerak/DraftStore/lib/storage"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
 )
 
 const (
-	DefaultDraftBucketSuffix = "-draft"
+	DefaultDraftBucketSuffix = storage.DefaultDraftBucketSuffix
 )
 
 type Service struct {
-	bucketName     string
-	draftBucket    string
-	objectLifetime time.Duration
-	storage        storage.Storage
+	bucketName      string
+	draftBucket     string
+	draftPrefix     string
+	objectLifetime  time.Duration
+	blobGracePeriod time.Duration
+	storage         storage.Storage
+	repository      repository.Repository
 }
 
 type ServiceOptions struct {
-	BucketName     string
+	BucketName string
+	// DraftStaging must match the draft service's, so that only drafts are
+	// cleaned up when they are staged in the main bucket.
+	DraftStaging   storage.DraftStaging
 	ObjectLifetime time.Duration
-	Storage        storage.Storage
+	// BlobGracePeriod protects recently stored blobs from collection while
+	// the confirmation that created them is still writing its reference.
+	BlobGracePeriod time.Duration
+	Storage         storage.Storage
+	// Repository, when set, has the records of cleaned up drafts pruned by
+	// PruneDrafts and expired resumable uploads discarded by PruneUploads.
+	Repository repository.Repository
 }
 
 func NewService(opts ServiceOptions) (*Service, error) {
 	log := logger.GetServiceLogger("cleaner-service")
 
+	if err := opts.DraftStaging.Validate(); err != nil {
+		return nil, err
+	}
+
 	service := &Service{
-		bucketName:     opts.BucketName,
-		draftBucket:    opts.BucketName + DefaultDraftBucketSuffix,
-		objectLifetime: opts.ObjectLifetime,
-		storage:        opts.Storage,
+		bucketName:      opts.BucketName,
+		draftBucket:     opts.DraftStaging.Bucket(opts.BucketName),
+		draftPrefix:     opts.DraftStaging.KeyPrefix(),
+		objectLifetime:  opts.ObjectLifetime,
+		blobGracePeriod: opts.BlobGracePeriod,
+		storage:         opts.Storage,
+		repository:      opts.Repository,
 	}
 
 	log.Info().
 		Str("bucket_name", service.bucketName).
 		Str("draft_bucket", service.draftBucket).
+		Str("draft_prefix", service.draftPrefix).
 		Dur("object_lifetime", service.objectLifetime).
+		Dur("blob_grace_period", service.blobGracePeriod).
 		Msg("Cleaner service initialized")
 
 	return service, nil
@@ -49,6 +73,7 @@ func (s *Service) CleanupDrafts(ctx context.Context) error {
 	log := logger.GetServiceLogger("cleaner-service").With().
 		Str("operation", "cleanup_drafts").
 		Str("bucket", s.draftBucket).
+		Str("prefix", s.draftPrefix).
 		Dur("object_lifetime", s.objectLifetime).
 		Logger()
 
@@ -62,7 +87,7 @@ func (s *Service) CleanupDrafts(ctx context.Context) error {
 		Msg("Starting cleanup operation")
 
 	// Perform the cleanup operation
-	if err := s.storage.CleanupBucket(ctx, s.draftBucket, cutoffTime, s.objectLifetime); err != nil {
+	if err := s.storage.CleanupBucket(ctx, s.draftBucket, s.draftPrefix, cutoffTime, s.objectLifetime); err != nil {
 		log.Error().
 			Err(err).
 			Msg("Cleanup operation failed")
@@ -82,3 +107,182 @@ func (s *Service) CleanupDrafts(ctx context.Context) error {
 	log.Info().Msg("Cleanup operation completed successfully")
 	return nil
 }
+
+// CollectBlobs deletes deduplicated blobs in the main bucket that are no
+// longer referenced by any object and were not stored or refreshed within
+// the grace period. The draft service refreshes a blob after referencing
+// it, and every blob is checked again right before it is deleted, so only a
+// confirmation racing that last check and the delete itself can lose its
+// blob.
+func (s *Service) CollectBlobs(ctx context.Context) error {
+	log := logger.GetServiceLogger("cleaner-service").With().
+		Str("operation", "collect_blobs").
+		Str("bucket", s.bucketName).
+		Dur("blob_grace_period", s.blobGracePeriod).
+		Logger()
+
+	log.Info().Msg("Starting blob garbage collection")
+
+	blobs, err := s.storage.ListObjects(ctx, s.bucketName, storage.BlobPrefix)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to list blobs")
+		return fmt.Errorf("failed to list blobs in bucket %s: %w", s.bucketName, err)
+	}
+
+	cutoffTime := time.Now().Add(-s.blobGracePeriod)
+	deleted := 0
+	for _, blob := range blobs {
+		if blob.LastModified.After(cutoffTime) {
+			continue
+		}
+
+		digest := strings.TrimPrefix(blob.Key, storage.BlobPrefix)
+		collectable, err := s.collectable(ctx, digest, cutoffTime)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("digest", digest).
+				Msg("Failed to check blob")
+			return err
+		}
+		if !collectable {
+			continue
+		}
+
+		if err := s.storage.DeleteObject(ctx, s.bucketName, blob.Key); err != nil {
+			log.Error().
+				Err(err).
+				Str("digest", digest).
+				Msg("Failed to delete unreferenced blob")
+			return fmt.Errorf("failed to delete blob %s: %w", digest, err)
+		}
+		deleted++
+
+		logger.LogStateChange("collect", "blob", blob.Key,
+			map[string]interface{}{
+				"status": "unreferenced",
+			},
+			map[string]interface{}{
+				"status": "deleted",
+			})
+	}
+
+	log.Info().
+		Int("scanned", len(blobs)).
+		Int("deleted", deleted).
+		Msg("Blob garbage collection completed successfully")
+	return nil
+}
+
+// collectable reports whether the blob digest is unreferenced and was last
+// stored or refreshed before cutoffTime. The listing CollectBlobs starts
+// from may be long outdated, so the blob is looked up again.
+func (s *Service) collectable(ctx context.Context, digest string, cutoffTime time.Time) (bool, error) {
+	refs, err := s.storage.ListObjects(ctx, s.bucketName, storage.RefPrefix+digest+"/")
+	if err != nil {
+		return false, fmt.Errorf("failed to list references of blob %s: %w", digest, err)
+	}
+	if len(refs) > 0 {
+		return false, nil
+	}
+
+	// A confirmation refreshes the blob after writing its reference, so a
+	// blob that is still old had no reference written before the listing
+	blob, err := s.storage.StatObject(ctx, s.bucketName, storage.BlobPrefix+digest)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat blob %s: %w", digest, err)
+	}
+	return !blob.LastModified.After(cutoffTime), nil
+}
+
+// PruneDrafts removes expired draft records from the repository, releasing
+// the quota held by drafts that were never confirmed.
+func (s *Service) PruneDrafts(ctx context.Context) error {
+	log := logger.GetServiceLogger("cleaner-service").With().
+		Str("operation", "prune_drafts").
+		Logger()
+
+	if s.repository == nil {
+		log.Info().Msg("No repository configured, skipping draft pruning")
+		return nil
+	}
+
+	log.Info().Msg("Starting draft record pruning")
+
+	pruned, err := s.repository.PruneDrafts(ctx, time.Now())
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int("pruned", pruned).
+			Msg("Failed to prune draft records")
+		return fmt.Errorf("failed to prune draft records: %w", err)
+	}
+
+	log.Info().
+		Int("pruned", pruned).
+		Msg("Draft record pruning completed successfully")
+	return nil
+}
+
+// PruneUploads discards resumable uploads that expired before they were
+// completed: their records, multipart uploads and pending bytes.
+func (s *Service) PruneUploads(ctx context.Context) error {
+	log := logger.GetServiceLogger("cleaner-service").With().
+		Str("operation", "prune_uploads").
+		Str("bucket", s.draftBucket).
+		Logger()
+
+	if s.repository == nil {
+		log.Info().Msg("No repository configured, skipping upload pruning")
+		return nil
+	}
+
+	log.Info().Msg("Starting resumable upload pruning")
+
+	uploads, err := s.repository.PruneUploads(ctx, time.Now())
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int("pruned", len(uploads)).
+			Msg("Failed to prune upload records")
+		return fmt.Errorf("failed to prune upload records: %w", err)
+	}
+
+	// The records are gone, so storage failures are only logged
+	for _, upload := range uploads {
+		if upload.MultipartID != "" {
+			if err := s.storage.AbortMultipartUpload(ctx, s.draftBucket, s.draftPrefix+upload.Key, upload.MultipartID); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+				log.Error().
+					Err(err).
+					Str("upload_id", upload.ID).
+					Msg("Failed to abort multipart upload")
+			}
+		}
+		if err := s.storage.DeleteObject(ctx, s.draftBucket, s.draftPrefix+storage.PendingUploadPrefix+upload.PendingName()); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			log.Error().
+				Err(err).
+				Str("upload_id", upload.ID).
+				Msg("Failed to delete pending upload content")
+		}
+
+		logger.LogStateChange("prune", "upload", upload.ID,
+			map[string]interface{}{
+				"object_name": upload.Key,
+				"offset":      upload.Offset,
+				"size":        upload.Size,
+			},
+			map[string]interface{}{
+				"status": "deleted",
+			})
+	}
+
+	log.Info().
+		Int("pruned", len(uploads)).
+		Msg("Resumable upload pruning completed successfully")
+	return nil
+}
@@ -0,0 +1,30 @@
+package draft
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+var _ API = (*Service)(nil)
+
+// API is the set of draft operations exposed by the controllers. It is
+// implemented by Service and by routers that dispatch every call to the
+// Service of the caller's tenant.
+type API interface {
+	CreateDraftBucket(ctx context.Context) error
+	GetUploadURL(ctx context.Context, objectName string, opts UploadURLOptions) (UploadURL, error)
+	GetDownloadURL(ctx context.Context, objectName string, opts DownloadURLOptions) (string, error)
+	GetDraftDownloadURL(ctx context.Context, objectName string) (string, error)
+	StatDraft(ctx context.Context, objectName string) (DraftInfo, error)
+	ConfirmUpload(ctx context.Context, objectName string, opts ConfirmUploadOptions) (string, error)
+	CancelUpload(ctx context.Context, objectName string) error
+	SignLink(ctx context.Context, objectName string, ttl time.Duration) (SignedLink, error)
+	ResolveLink(ctx context.Context, objectName string, opts LinkOptions) (LinkURL, error)
+	UploadDraft(ctx context.Context, objectName string, body io.Reader, size int64, opts UploadOptions) (string, error)
+	OpenObject(ctx context.Context, objectName string, opts OpenObjectOptions) (*ObjectReader, error)
+	CreateUpload(ctx context.Context, objectName string, size int64, opts CreateUploadOptions) (ResumableUpload, error)
+	GetUpload(ctx context.Context, id string) (ResumableUpload, error)
+	WriteUpload(ctx context.Context, id string, offset int64, body io.Reader) (ResumableUpload, error)
+	TerminateUpload(ctx context.Context, id string) error
+}
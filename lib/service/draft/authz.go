@@ -0,0 +1,49 @@
+package draft
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// authorize checks the caller against the authorizer. Every operation is
+// allowed when no authorizer is configured.
+func (s *Service) authorize(ctx context.Context, operation, objectName string, fields map[string]any) error {
+	if s.authorizer == nil {
+		return nil
+	}
+
+	if s.tenant != "" {
+		fields = maps.Clone(fields)
+		if fields == nil {
+			fields = make(map[string]any, 1)
+		}
+		fields["tenant"] = s.tenant
+	}
+
+	principal := auth.FromContext(ctx)
+	allowed, err := s.authorizer.Authorize(ctx, authz.Request{
+		Operation: operation,
+		Key:       objectName,
+		Principal: principal,
+		Fields:    fields,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to authorize %s: %w", operation, err)
+	}
+	if !allowed {
+		log := logger.GetServiceLogger("draft-service")
+		log.Warn().
+			Str("operation", operation).
+			Str("object_name", objectName).
+			Str("subject", principal.Subject).
+			Msg("Operation denied by authorizer")
+		return fmt.Errorf("%w: %s on %q", ErrAccessDenied, operation, objectName)
+	}
+
+	return nil
+}
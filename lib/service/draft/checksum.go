@@ -0,0 +1,108 @@
+package draft
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+var checksumLengths = map[storage.ChecksumAlgorithm]int{
+	storage.ChecksumAlgorithmSHA256: 32,
+	storage.ChecksumAlgorithmCRC32C: 4,
+	storage.ChecksumAlgorithmMD5:    16,
+}
+
+// validateChecksum checks that a declared checksum is well formed.
+func validateChecksum(checksum storage.Checksum) error {
+	if checksum.Algorithm == storage.ChecksumAlgorithmNone {
+		if checksum.Value != "" {
+			return fmt.Errorf("%w: checksum value given without algorithm", ErrInvalidChecksum)
+		}
+		return nil
+	}
+
+	length, ok := checksumLengths[checksum.Algorithm]
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidChecksum, checksum.Algorithm)
+	}
+
+	digest, err := base64.StdEncoding.DecodeString(checksum.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %s value is not base64: %v", ErrInvalidChecksum, checksum.Algorithm, err)
+	}
+	if len(digest) != length {
+		return fmt.Errorf("%w: %s value must be %d bytes, got %d", ErrInvalidChecksum, checksum.Algorithm, length, len(digest))
+	}
+	return nil
+}
+
+// storedChecksum returns the strongest checksum recorded for the object.
+func storedChecksum(info storage.ObjectInfo) storage.Checksum {
+	switch {
+	case info.ChecksumSHA256 != "":
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmSHA256, Value: info.ChecksumSHA256}
+	case info.ChecksumCRC32C != "":
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmCRC32C, Value: info.ChecksumCRC32C}
+	default:
+		return storage.Checksum{}
+	}
+}
+
+// matchChecksum compares an expected checksum against the object metadata.
+func matchChecksum(info storage.ObjectInfo, expected storage.Checksum) bool {
+	switch expected.Algorithm {
+	case storage.ChecksumAlgorithmSHA256:
+		return info.ChecksumSHA256 == expected.Value
+	case storage.ChecksumAlgorithmCRC32C:
+		return info.ChecksumCRC32C == expected.Value
+	case storage.ChecksumAlgorithmMD5:
+		// The ETag of a single part upload is the hex encoded MD5 of its content
+		digest, err := base64.StdEncoding.DecodeString(expected.Value)
+		if err != nil {
+			return false
+		}
+		return strings.Trim(info.ETag, `"`) == hex.EncodeToString(digest)
+	default:
+		return false
+	}
+}
+
+// verifyChecksum checks the stored checksum of the draft's version info
+// against the expected one and returns the algorithm to carry over to the
+// main bucket copy.
+func (s *Service) verifyChecksum(ctx context.Context, objectName string, info storage.ObjectInfo, expected storage.Checksum) (storage.ChecksumAlgorithm, error) {
+	if err := validateChecksum(expected); err != nil {
+		return storage.ChecksumAlgorithmNone, err
+	}
+
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "verify_checksum").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Str("expected_algorithm", string(expected.Algorithm)).
+		Logger()
+
+	stored := storedChecksum(info)
+
+	if expected.Algorithm != storage.ChecksumAlgorithmNone && !matchChecksum(info, expected) {
+		log.Warn().
+			Str("stored_algorithm", string(stored.Algorithm)).
+			Msg("Draft checksum does not match the expected value")
+		return storage.ChecksumAlgorithmNone, fmt.Errorf("%w: %s of %s does not match", ErrChecksumMismatch, expected.Algorithm, objectName)
+	}
+
+	if s.requireChecksum && stored.Algorithm == storage.ChecksumAlgorithmNone && expected.Algorithm != storage.ChecksumAlgorithmMD5 {
+		log.Warn().Msg("Draft object has no stored checksum")
+		return storage.ChecksumAlgorithmNone, fmt.Errorf("%w: %s has no stored checksum", ErrChecksumMismatch, objectName)
+	}
+
+	log.Debug().
+		Str("stored_algorithm", string(stored.Algorithm)).
+		Msg("Draft checksum verified")
+	return stored.Algorithm, nil
+}
@@ -0,0 +1,176 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// BlobMetadataKey is the metadata key of a pointer object naming its blob.
+const BlobMetadataKey = "draftstore-blob"
+
+func blobKey(digest string) string {
+	return storage.BlobPrefix + digest
+}
+
+func refKey(digest, objectName string) string {
+	return storage.RefPrefix + digest + "/" + objectName
+}
+
+// draftDigest returns the hex encoded SHA-256 of the draft's version info,
+// using the stored checksum when available and streaming the object otherwise.
+func (s *Service) draftDigest(ctx context.Context, objectName string, info storage.ObjectInfo) (string, error) {
+	if info.ChecksumSHA256 != "" {
+		if digest, err := base64.StdEncoding.DecodeString(info.ChecksumSHA256); err == nil && len(digest) == sha256.Size {
+			return hex.EncodeToString(digest), nil
+		}
+	}
+
+	reader, err := s.storage.GetObject(ctx, s.draftBucket, s.draftKey(objectName), storage.GetObjectOptions{
+		IfMatch: info.ETag,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read draft object: %w", err)
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", fmt.Errorf("failed to hash draft object: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// promoteDeduplicated stores the draft's version info once under its content
+// hash and writes objectName as a pointer to it. The caller deletes the draft
+// afterwards.
+func (s *Service) promoteDeduplicated(ctx context.Context, draftName, objectName string, info storage.ObjectInfo, checksumAlgorithm storage.ChecksumAlgorithm) error {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "promote_deduplicated").
+		Str("draft_name", draftName).
+		Str("object_name", objectName).
+		Str("source_bucket", s.draftBucket).
+		Str("dest_bucket", s.bucketName).
+		Logger()
+
+	digest, err := s.draftDigest(ctx, draftName, info)
+	if err != nil {
+		return err
+	}
+	log = log.With().Str("digest", digest).Logger()
+
+	// Find the blob referenced by a previous version of the logical key
+	var previousDigest string
+	previous, err := s.storage.StatObject(ctx, s.bucketName, objectName)
+	switch {
+	case err == nil:
+		previousDigest = previous.Metadata[BlobMetadataKey]
+	case !errors.Is(err, storage.ErrObjectNotFound):
+		return fmt.Errorf("failed to stat existing object: %w", err)
+	}
+
+	// The reference marker is written before the blob is stored or
+	// refreshed, so the cleaner, which checks the references before the age
+	// of a blob, either sees the marker or a blob younger than its grace
+	// period. Only a collection whose last check and delete straddle both
+	// writes can still remove the blob.
+	if _, err := s.storage.PutObject(ctx, s.bucketName, refKey(digest, objectName), bytes.NewReader(nil), 0, storage.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to write blob reference: %w", err)
+	}
+
+	if err := s.storeBlob(ctx, draftName, digest, info.ETag, checksumAlgorithm); err != nil {
+		// Keep the marker when the previous version references the same blob
+		if previousDigest != digest {
+			if dErr := s.storage.DeleteObject(ctx, s.bucketName, refKey(digest, objectName)); dErr != nil {
+				log.Warn().
+					Err(dErr).
+					Msg("Failed to delete blob reference")
+			}
+		}
+		return err
+	}
+
+	// Drop the reference held by a previous version of the logical key
+	if previousDigest != "" && previousDigest != digest {
+		if err := s.storage.DeleteObject(ctx, s.bucketName, refKey(previousDigest, objectName)); err != nil {
+			log.Warn().
+				Err(err).
+				Str("previous_digest", previousDigest).
+				Msg("Failed to delete reference to previous blob")
+		}
+	}
+
+	if _, err := s.storage.PutObject(ctx, s.bucketName, objectName, bytes.NewReader(nil), 0, storage.PutObjectOptions{
+		ContentType: info.ContentType,
+		Metadata: map[string]string{
+			BlobMetadataKey: digest,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to write blob pointer: %w", err)
+	}
+
+	logger.LogStateChange("deduplicate", "object", objectName, nil, map[string]interface{}{
+		"blob":   blobKey(digest),
+		"bucket": s.bucketName,
+	})
+
+	return nil
+}
+
+// storeBlob copies the draft version etag to the blob of digest. An
+// identical blob that is already stored is replaced all the same, which
+// refreshes its modification time and so keeps the cleaner from collecting
+// it within the grace period.
+func (s *Service) storeBlob(ctx context.Context, draftName, digest, etag string, checksumAlgorithm storage.ChecksumAlgorithm) error {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "store_blob").
+		Str("draft_name", draftName).
+		Str("digest", digest).
+		Logger()
+
+	_, err := s.storage.StatObject(ctx, s.bucketName, blobKey(digest))
+	if err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return fmt.Errorf("failed to stat blob: %w", err)
+	}
+	existed := err == nil
+
+	if err := s.storage.CopyObject(ctx, s.draftBucket, s.draftKey(draftName), s.bucketName, blobKey(digest), storage.CopyObjectOptions{
+		ChecksumAlgorithm: checksumAlgorithm,
+		SourceIfMatch:     etag,
+	}); err != nil {
+		return fmt.Errorf("failed to copy draft object to blob: %w", err)
+	}
+	if existed {
+		log.Info().Msg("Identical content already stored, refreshed blob")
+	} else {
+		log.Info().Msg("Stored new content blob")
+	}
+	return nil
+}
+
+// resolveObject maps a logical key to the object holding its content.
+// It returns the pointer's content type so downloads keep the original type.
+func (s *Service) resolveObject(ctx context.Context, objectName string) (string, string, error) {
+	if !s.dedup {
+		return objectName, "", nil
+	}
+
+	info, err := s.storage.StatObject(ctx, s.bucketName, objectName)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve object: %w", err)
+	}
+
+	if digest := info.Metadata[BlobMetadataKey]; digest != "" {
+		return blobKey(digest), info.ContentType, nil
+	}
+	// Objects confirmed before deduplication was enabled are stored in place
+	return objectName, "", nil
+}
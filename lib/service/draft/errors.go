@@ -0,0 +1,46 @@
+package draft
+
+import (
+	"errors"
+
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/repository"
+)
+
+var (
+	// ErrValidationFailed is returned when a draft violates the content validation policy.
+	ErrValidationFailed = errors.New("draft validation failed")
+	// ErrInfected is returned when the malware scanner flags a draft.
+	ErrInfected = errors.New("draft object is infected")
+	// ErrChecksumMismatch is returned when a draft's stored checksum is missing
+	// or differs from the one declared by the client.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrInvalidChecksum is returned when a declared checksum is malformed.
+	ErrInvalidChecksum = errors.New("invalid checksum")
+	// ErrInvalidSize is returned when a declared size is negative or missing
+	// although the caller's quota requires it.
+	ErrInvalidSize = errors.New("invalid size")
+	// ErrTooLarge is returned alongside ErrValidationFailed when an object
+	// exceeds the maximum size of the validation policy.
+	ErrTooLarge = errors.New("object too large")
+	// ErrInvalidObjectName is returned for object names reserved by the service.
+	ErrInvalidObjectName = errors.New("invalid object name")
+	// ErrRejected is returned by pre hooks to veto an operation.
+	ErrRejected = errors.New("rejected by hook")
+	// ErrInvalidLink is returned for link tokens that are malformed, expired
+	// or signed for another key or tenant.
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrUploadNotFound is returned for resumable uploads that do not exist,
+	// expired or belong to another owner.
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrOffsetMismatch is returned when content is appended to a resumable
+	// upload at another offset than the one it reached.
+	ErrOffsetMismatch = errors.New("upload offset mismatch")
+	// ErrUploadLocked is returned while another request appends to the same
+	// resumable upload.
+	ErrUploadLocked = errors.New("upload locked")
+	// ErrAccessDenied is returned when the authorizer denies an operation.
+	ErrAccessDenied = authz.ErrAccessDenied
+	// ErrQuotaExceeded is returned when an operation would exceed the caller's quota.
+	ErrQuotaExceeded = repository.ErrQuotaExceeded
+)
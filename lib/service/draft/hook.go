@@ -0,0 +1,113 @@
+package draft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// HookRequest describes the operation a hook is invoked for.
+type HookRequest struct {
+	// ObjectName is the destination key. Pre hooks may rewrite it.
+	ObjectName string
+	// DraftName is the key of the draft being confirmed. It is empty for
+	// upload URL hooks.
+	DraftName   string
+	ContentType string
+	Size        int64
+	// Metadata holds the user metadata stored with the draft.
+	Metadata map[string]string
+	// Principal is the caller. It is the zero value for anonymous requests.
+	Principal auth.Principal
+}
+
+// PreUploadHook runs before an upload URL is issued. Returning an error
+// wrapping ErrRejected denies the request; changing req.ObjectName issues the
+// URL for the new key instead.
+type PreUploadHook interface {
+	PreUpload(ctx context.Context, req *HookRequest) error
+}
+
+// PostUploadHook runs after an upload URL is issued.
+type PostUploadHook interface {
+	PostUpload(ctx context.Context, req HookRequest) error
+}
+
+// PreConfirmHook runs before a draft is promoted. Returning an error wrapping
+// ErrRejected denies the confirmation; changing req.ObjectName promotes the
+// draft under the new key instead.
+type PreConfirmHook interface {
+	PreConfirm(ctx context.Context, req *HookRequest) error
+}
+
+// PostConfirmHook runs after a draft is promoted.
+type PostConfirmHook interface {
+	PostConfirm(ctx context.Context, req HookRequest) error
+}
+
+// Hooks groups the hooks run by the service. Hooks of each kind run in order
+// and the first pre hook error aborts the operation. Post hook errors are
+// logged since the operation has already completed.
+type Hooks struct {
+	PreUpload   []PreUploadHook
+	PostUpload  []PostUploadHook
+	PreConfirm  []PreConfirmHook
+	PostConfirm []PostConfirmHook
+}
+
+func (s *Service) runPreUploadHooks(ctx context.Context, req *HookRequest) error {
+	for _, hook := range s.hooks.PreUpload {
+		if err := hook.PreUpload(ctx, req); err != nil {
+			return fmt.Errorf("pre upload hook failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) runPostUploadHooks(ctx context.Context, req HookRequest) {
+	for _, hook := range s.hooks.PostUpload {
+		if err := hook.PostUpload(ctx, req); err != nil {
+			log := logger.GetServiceLogger("draft-service")
+			log.Error().
+				Err(err).
+				Str("object_name", req.ObjectName).
+				Msg("Post upload hook failed")
+		}
+	}
+}
+
+func (s *Service) runPreConfirmHooks(ctx context.Context, req *HookRequest) error {
+	for _, hook := range s.hooks.PreConfirm {
+		if err := hook.PreConfirm(ctx, req); err != nil {
+			return fmt.Errorf("pre confirm hook failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) runPostConfirmHooks(ctx context.Context, req HookRequest) {
+	for _, hook := range s.hooks.PostConfirm {
+		if err := hook.PostConfirm(ctx, req); err != nil {
+			log := logger.GetServiceLogger("draft-service")
+			log.Error().
+				Err(err).
+				Str("object_name", req.ObjectName).
+				Msg("Post confirm hook failed")
+		}
+	}
+}
+
+// confirmHookRequest describes the confirmation of the version info of a draft.
+func (s *Service) confirmHookRequest(ctx context.Context, draftName string, info storage.ObjectInfo) HookRequest {
+	return HookRequest{
+		ObjectName:  draftName,
+		DraftName:   draftName,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		Metadata:    info.Metadata,
+		Principal:   auth.FromContext(ctx),
+	}
+}
@@ -0,0 +1,320 @@
+package draft
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DefaultMaxLinkTTL caps the lifetime of signed links when LinkPolicy.MaxTTL is zero.
+	DefaultMaxLinkTTL = time.Hour
+	// DefaultMaxCachedLinkURLs bounds the presigned URL cache when
+	// LinkPolicy.MaxCachedURLs is zero.
+	DefaultMaxCachedLinkURLs = 10000
+)
+
+// LinkPolicy configures download links, which redirect to presigned URLs
+// and can be embedded in web pages.
+type LinkPolicy struct {
+	// PublicPrefixes are key prefixes anyone may download through links,
+	// without credentials or a signature.
+	PublicPrefixes []string
+	// Secret signs the links issued by SignLink. Signed links are disabled
+	// when it is empty.
+	Secret []byte
+	// MaxTTL caps the lifetime of signed links. Defaults to DefaultMaxLinkTTL.
+	MaxTTL time.Duration
+	// URLTTL is the lifetime of the presigned URLs links redirect to.
+	// Defaults to the download TTL.
+	URLTTL time.Duration
+	// RefreshBefore is how long before expiry a cached presigned URL is
+	// replaced. Defaults to a fifth of URLTTL.
+	RefreshBefore time.Duration
+	// MaxCachedURLs bounds the presigned URL cache. Defaults to
+	// DefaultMaxCachedLinkURLs; a negative value disables the cache.
+	MaxCachedURLs int
+}
+
+// LinkToken is the signature of a link issued by SignLink.
+type LinkToken struct {
+	Expires   time.Time
+	Signature string
+}
+
+// SignedLink is a link token and the tenant of the service that signed it.
+// The token is only valid for that tenant.
+type SignedLink struct {
+	LinkToken
+	Tenant string
+}
+
+// LinkOptions customizes ResolveLink.
+type LinkOptions struct {
+	// Token, when set, grants access to the object on its own.
+	Token LinkToken
+	// AllowAnonymous lets callers without credentials resolve links outside
+	// the public prefixes, subject to the authorizer.
+	AllowAnonymous bool
+}
+
+// LinkURL is the presigned URL a link redirects to.
+type LinkURL struct {
+	URL string
+	// RefreshAt is when the URL is replaced in the cache or the link token
+	// expires. Redirects to it should not be reused after that.
+	RefreshAt time.Time
+	// Public reports whether the object is under a public prefix, so that
+	// shared caches may store the redirect.
+	Public bool
+}
+
+// SignLink issues a token that lets anyone holding it download objectName
+// through a link until it expires. A zero ttl issues the longest link allowed.
+func (s *Service) SignLink(ctx context.Context, objectName string, ttl time.Duration) (SignedLink, error) {
+	if ttl <= 0 || ttl > s.links.MaxTTL {
+		ttl = s.links.MaxTTL
+	}
+
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "sign_link").
+		Str("object_name", objectName).
+		Dur("ttl", ttl).
+		Logger()
+
+	log.Info().Msg("Signing download link")
+
+	if err := s.authorize(ctx, authz.OperationDownload, objectName, map[string]any{
+		"link":        true,
+		"ttl_seconds": int64(ttl.Seconds()),
+	}); err != nil {
+		return SignedLink{}, fmt.Errorf("failed to sign link: %w", err)
+	}
+	if len(s.links.Secret) == 0 {
+		return SignedLink{}, fmt.Errorf("failed to sign link: %w: signed links are disabled", ErrInvalidLink)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return SignedLink{}, fmt.Errorf("failed to sign link: %w", err)
+	}
+
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+
+	log.Info().
+		Time("expires", expires).
+		Msg("Download link signed successfully")
+	return SignedLink{
+		LinkToken: LinkToken{
+			Expires:   expires,
+			Signature: s.linkSignature(objectName, expires),
+		},
+		Tenant: s.tenant,
+	}, nil
+}
+
+// ResolveLink returns a presigned download URL for objectName. The link is
+// allowed by a valid token, by a public prefix or by the authorizer, in that
+// order. Presigned URLs are cached per key until they are close to expiry;
+// a token gets a URL that expires no later than itself.
+func (s *Service) ResolveLink(ctx context.Context, objectName string, opts LinkOptions) (LinkURL, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "resolve_link").
+		Str("object_name", objectName).
+		Str("bucket", s.bucketName).
+		Bool("signed", opts.Token.Signature != "").
+		Logger()
+
+	now := time.Now()
+	public := s.publicLink(objectName)
+	switch {
+	case opts.Token.Signature != "":
+		if err := s.verifyLink(objectName, opts.Token, now); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Rejected invalid link")
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w", err)
+		}
+	case public:
+	default:
+		if !opts.AllowAnonymous && auth.FromContext(ctx).Anonymous() {
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w: %w", ErrAccessDenied, auth.ErrNoCredentials)
+		}
+		if err := s.authorize(ctx, authz.OperationDownload, objectName, map[string]any{
+			"link": true,
+		}); err != nil {
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w", err)
+		}
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return LinkURL{}, fmt.Errorf("failed to resolve link: %w", err)
+	}
+
+	// A URL must not outlive the token it is resolved for, so tokens that
+	// expire first get a URL of their own
+	ttl := s.links.URLTTL
+	if opts.Token.Signature != "" {
+		ttl = min(ttl, opts.Token.Expires.Sub(now).Truncate(time.Second))
+		if ttl <= 0 {
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w: expired at %s", ErrInvalidLink, opts.Token.Expires.Format(time.RFC3339))
+		}
+	}
+
+	link, ok := s.linkCache.get(objectName, now)
+	if ok && link.expires.After(now.Add(ttl)) {
+		ok = false
+	}
+	if !ok {
+		target, contentType, err := s.resolveObject(ctx, objectName)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to resolve object")
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w", err)
+		}
+
+		url, err := s.storage.MakeGetPresignedURL(ctx, s.bucketName, target, ttl, storage.GetPresignedURLOptions{
+			ResponseContentType: contentType,
+		})
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to generate download URL")
+			return LinkURL{}, fmt.Errorf("failed to resolve link: %w", err)
+		}
+
+		link = cachedLink{url: url, expires: now.Add(ttl), refreshAt: now.Add(ttl)}
+		if ttl == s.links.URLTTL {
+			link.refreshAt = link.expires.Add(-s.links.RefreshBefore)
+			s.linkCache.put(objectName, link, now)
+		}
+		log.Info().
+			Dur("ttl", ttl).
+			Msg("Download URL generated for link")
+	}
+
+	refreshAt := link.refreshAt
+	if opts.Token.Signature != "" && opts.Token.Expires.Before(refreshAt) {
+		refreshAt = opts.Token.Expires
+	}
+
+	return LinkURL{
+		URL:       link.url,
+		RefreshAt: refreshAt,
+		Public:    public,
+	}, nil
+}
+
+// publicLink reports whether objectName is under a public prefix.
+func (s *Service) publicLink(objectName string) bool {
+	for _, prefix := range s.links.PublicPrefixes {
+		if strings.HasPrefix(objectName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyLink checks that token was signed by this tenant for objectName and
+// has not expired.
+func (s *Service) verifyLink(objectName string, token LinkToken, now time.Time) error {
+	if len(s.links.Secret) == 0 {
+		return fmt.Errorf("%w: signed links are disabled", ErrInvalidLink)
+	}
+	if !hmac.Equal([]byte(token.Signature), []byte(s.linkSignature(objectName, token.Expires))) {
+		return fmt.Errorf("%w: bad signature", ErrInvalidLink)
+	}
+	if !now.Before(token.Expires) {
+		return fmt.Errorf("%w: expired at %s", ErrInvalidLink, token.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// linkSignature signs the tenant, key and expiry of a link.
+func (s *Service) linkSignature(objectName string, expires time.Time) string {
+	mac := hmac.New(sha256.New, s.links.Secret)
+	mac.Write([]byte(s.tenant + "\n" + objectName + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// cachedLink is a presigned URL shared by the links to a key.
+type cachedLink struct {
+	url       string
+	expires   time.Time
+	refreshAt time.Time
+}
+
+// linkCache keeps presigned URLs per key. A nil cache stores nothing.
+type linkCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedLink
+	max     int
+}
+
+func newLinkCache(max int) *linkCache {
+	if max < 0 {
+		return nil
+	}
+	if max == 0 {
+		max = DefaultMaxCachedLinkURLs
+	}
+	return &linkCache{
+		entries: make(map[string]cachedLink),
+		max:     max,
+	}
+}
+
+func (c *linkCache) get(key string, now time.Time) (cachedLink, bool) {
+	if c == nil {
+		return cachedLink{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	link, ok := c.entries[key]
+	if !ok || !now.Before(link.refreshAt) {
+		return cachedLink{}, false
+	}
+	return link, true
+}
+
+func (c *linkCache) put(key string, link cachedLink, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.max {
+		for existing, cached := range c.entries {
+			if !now.Before(cached.refreshAt) {
+				delete(c.entries, existing)
+			}
+		}
+		// Keep the cache bounded when every entry is still fresh
+		if len(c.entries) >= c.max {
+			return
+		}
+	}
+	c.entries[key] = link
+}
+
+// forget drops the URL of key, whose content changed.
+func (c *linkCache) forget(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,76 @@
+package draft
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestResolveLinkTTL(t *testing.T) {
+	const urlTTL = time.Hour
+
+	tests := []struct {
+		name      string
+		linkTTL   time.Duration
+		wantTTL   time.Duration
+		wantCache bool
+	}{
+		{name: "token outlives cached URL", linkTTL: 2 * urlTTL, wantTTL: urlTTL, wantCache: true},
+		{name: "token expires just before cached URL", linkTTL: urlTTL, wantTTL: urlTTL},
+		{name: "token expires first", linkTTL: 10 * time.Minute, wantTTL: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, err := NewService(ServiceOptions{
+				BucketName:  testBucket,
+				Storage:     newFakeStorage(),
+				DownloadTTL: urlTTL,
+				Links: LinkPolicy{
+					Secret: []byte("secret"),
+					MaxTTL: 2 * urlTTL,
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			t.Cleanup(service.Close)
+
+			// Cache a URL through an unsigned link
+			cached, err := service.ResolveLink(ctx, "file.bin", LinkOptions{AllowAnonymous: true})
+			if err != nil {
+				t.Fatalf("ResolveLink() error = %v", err)
+			}
+
+			link, err := service.SignLink(ctx, "file.bin", tt.linkTTL)
+			if err != nil {
+				t.Fatalf("SignLink() error = %v", err)
+			}
+			resolved, err := service.ResolveLink(ctx, "file.bin", LinkOptions{Token: link.LinkToken})
+			if err != nil {
+				t.Fatalf("ResolveLink() error = %v", err)
+			}
+
+			if (resolved.URL == cached.URL) != tt.wantCache {
+				t.Errorf("ResolveLink() cached = %v, want %v", resolved.URL == cached.URL, tt.wantCache)
+			}
+			if resolved.RefreshAt.After(link.Expires) {
+				t.Errorf("ResolveLink() refresh at %s, after the link expires at %s", resolved.RefreshAt, link.Expires)
+			}
+
+			parsed, err := url.Parse(resolved.URL)
+			if err != nil {
+				t.Fatalf("invalid URL %q: %v", resolved.URL, err)
+			}
+			ttl, err := time.ParseDuration(parsed.Query().Get("ttl"))
+			if err != nil {
+				t.Fatalf("invalid URL TTL in %q: %v", resolved.URL, err)
+			}
+			if ttl > tt.wantTTL || ttl < tt.wantTTL-2*time.Second {
+				t.Errorf("URL TTL = %s, want %s", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
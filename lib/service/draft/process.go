@@ -0,0 +1,138 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/snowmerak/DraftStore/lib/processor"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DerivativeSeparator joins an object name and a variant into the key of a
+	// derivative, e.g. "photo.jpg@w256".
+	DerivativeSeparator = "@"
+	// DefaultProcessQueueSize is the number of confirmed objects buffered per worker.
+	DefaultProcessQueueSize = 16
+)
+
+// DerivativeKey returns the main bucket key of a derivative of objectName.
+func DerivativeKey(objectName, variant string) string {
+	return objectName + DerivativeSeparator + variant
+}
+
+func (s *Service) startProcessWorkers(workers int) {
+	s.processQueue = make(chan string, workers*DefaultProcessQueueSize)
+	for i := 0; i < workers; i++ {
+		s.processWG.Add(1)
+		go func() {
+			defer s.processWG.Done()
+			for objectName := range s.processQueue {
+				s.processObject(context.Background(), objectName)
+			}
+		}()
+	}
+}
+
+// Close stops the processing workers after the queued objects are processed.
+func (s *Service) Close() {
+	s.closeOnce.Do(func() {
+		if s.processQueue != nil {
+			close(s.processQueue)
+			s.processWG.Wait()
+		}
+	})
+}
+
+// enqueueProcessing runs the processors for a confirmed object, inline or on
+// the worker pool. Failures are logged and never fail the confirmation.
+func (s *Service) enqueueProcessing(ctx context.Context, objectName string) {
+	if len(s.processors) == 0 {
+		return
+	}
+
+	if s.processQueue == nil {
+		s.processObject(ctx, objectName)
+		return
+	}
+
+	select {
+	case s.processQueue <- objectName:
+	case <-ctx.Done():
+		log := logger.GetServiceLogger("draft-service")
+		log.Warn().
+			Err(ctx.Err()).
+			Str("object_name", objectName).
+			Msg("Dropped object from processing queue")
+	}
+}
+
+func (s *Service) processObject(ctx context.Context, objectName string) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "process_object").
+		Str("object_name", objectName).
+		Str("bucket", s.bucketName).
+		Logger()
+
+	info, err := s.storage.StatObject(ctx, s.bucketName, objectName)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to stat confirmed object")
+		return
+	}
+
+	target, _, err := s.resolveObject(ctx, objectName)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to resolve confirmed object")
+		return
+	}
+
+	for _, p := range s.processors {
+		if !p.Accepts(info.ContentType) {
+			continue
+		}
+
+		if err := s.runProcessor(ctx, p, objectName, target); err != nil {
+			log.Error().
+				Err(err).
+				Str("processor", p.Name()).
+				Msg("Failed to process object")
+		}
+	}
+}
+
+func (s *Service) runProcessor(ctx context.Context, p processor.Processor, objectName, target string) error {
+	reader, err := s.storage.GetObject(ctx, s.bucketName, target, storage.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to read object: %w", err)
+	}
+	defer reader.Close()
+
+	derivatives, err := p.Process(ctx, reader)
+	if err != nil {
+		return fmt.Errorf("failed to process object: %w", err)
+	}
+
+	for _, derivative := range derivatives {
+		key := DerivativeKey(objectName, derivative.Variant)
+		if _, err := s.storage.PutObject(ctx, s.bucketName, key, bytes.NewReader(derivative.Data), int64(len(derivative.Data)), storage.PutObjectOptions{
+			ContentType: derivative.ContentType,
+		}); err != nil {
+			return fmt.Errorf("failed to write derivative %s: %w", derivative.Variant, err)
+		}
+
+		logger.LogStateChange("derive", "object", key, nil, map[string]interface{}{
+			"source":    objectName,
+			"processor": p.Name(),
+			"variant":   derivative.Variant,
+			"size":      len(derivative.Data),
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DefaultDraftLifetime matches the cleaner's default object lifetime.
+	DefaultDraftLifetime = 24 * time.Hour
+	// AnonymousOwner is charged for requests without a principal.
+	AnonymousOwner = "anonymous"
+)
+
+// QuotaPolicy limits the usage of every owner. The zero value only accounts
+// usage without limiting it.
+type QuotaPolicy struct {
+	Default repository.Quota
+	// Owners overrides Default for individual owners, keyed by QuotaOwner.
+	Owners map[string]repository.Quota
+}
+
+func (p QuotaPolicy) quota(owner string) repository.Quota {
+	if quota, ok := p.Owners[owner]; ok {
+		return quota
+	}
+	return p.Default
+}
+
+// QuotaOwner returns the owner usage is charged to, "<method>:<subject>" for
+// authenticated principals and AnonymousOwner otherwise.
+func QuotaOwner(principal auth.Principal) string {
+	if principal.Anonymous() {
+		return AnonymousOwner
+	}
+	return principal.Method + ":" + principal.Subject
+}
+
+type quotaDocument struct {
+	Owners map[string]repository.Quota `json:"owners"`
+}
+
+// LoadQuotaOverrides reads per owner quotas from a JSON file of the form
+// {"owners": {"jwt:alice": {"max_drafts": 10}}}.
+func LoadQuotaOverrides(path string) (map[string]repository.Quota, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quota file: %w", err)
+	}
+
+	var document quotaDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode quota file: %w", err)
+	}
+	return document.Owners, nil
+}
+
+// reserveDraft charges a new draft to the caller. It does nothing without a repository.
+func (s *Service) reserveDraft(ctx context.Context, objectName string, size int64) error {
+	if s.repository == nil {
+		return nil
+	}
+
+	owner := QuotaOwner(auth.FromContext(ctx))
+	now := time.Now()
+	err := s.repository.ReserveDraft(ctx, owner, repository.Draft{
+		Key:       objectName,
+		Size:      size,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.draftLifetime),
+	}, s.quotas.quota(owner))
+	if err != nil {
+		return fmt.Errorf("failed to reserve draft for %s: %w", owner, err)
+	}
+	return nil
+}
+
+// releaseDraft removes the reservation of a draft, if any. It is released
+// against the owner that reserved it, which need not be the caller when
+// another principal confirms or cancels the upload.
+func (s *Service) releaseDraft(ctx context.Context, objectName string) error {
+	if s.repository == nil {
+		return nil
+	}
+
+	owner, err := s.repository.DraftOwner(ctx, objectName)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Drafts reserved before owners were recorded belong to the caller
+		owner = QuotaOwner(auth.FromContext(ctx))
+	} else if err != nil {
+		return fmt.Errorf("failed to look up draft owner: %w", err)
+	}
+	if _, err := s.repository.ReleaseDraft(ctx, owner, objectName); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("failed to release draft for %s: %w", owner, err)
+	}
+	return nil
+}
+
+// chargeConfirmed charges size to the caller's confirmed bytes as the content
+// of objectName, crediting the size objectName was confirmed with before.
+// Checking and recording the charge is a single repository update, so
+// concurrent confirmations cannot pass the quota together. It returns the
+// previous size for restoreConfirmed.
+func (s *Service) chargeConfirmed(ctx context.Context, objectName string, size int64) (int64, error) {
+	if s.repository == nil {
+		return 0, nil
+	}
+
+	owner := QuotaOwner(auth.FromContext(ctx))
+	previous, err := s.repository.ConfirmObject(ctx, owner, objectName, size, s.quotas.quota(owner))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", owner, err)
+	}
+	return previous, nil
+}
+
+// restoreConfirmed undoes chargeConfirmed when the draft could not be
+// promoted. Failures are only logged.
+func (s *Service) restoreConfirmed(ctx context.Context, objectName string, previous int64) {
+	if s.repository == nil {
+		return
+	}
+
+	owner := QuotaOwner(auth.FromContext(ctx))
+	if _, err := s.repository.ConfirmObject(ctx, owner, objectName, previous, repository.Quota{}); err != nil {
+		log := logger.GetServiceLogger("draft-service")
+		log.Error().
+			Err(err).
+			Str("owner", owner).
+			Str("object_name", objectName).
+			Msg("Failed to restore confirmed usage")
+	}
+}
+
+// validateSize checks a declared upload size against the validation policy
+// and the caller's quota.
+func (s *Service) validateSize(ctx context.Context, size int64) error {
+	if size < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidSize, size)
+	}
+	if s.validation.MaxSize > 0 && size > s.validation.MaxSize {
+		return fmt.Errorf("%w: %w: size %d exceeds maximum of %d bytes", ErrValidationFailed, ErrTooLarge, size, s.validation.MaxSize)
+	}
+	if s.repository != nil && size == 0 && s.quotas.quota(QuotaOwner(auth.FromContext(ctx))).MaxDraftBytes > 0 {
+		return fmt.Errorf("%w: a size is required", ErrInvalidSize)
+	}
+	return nil
+}
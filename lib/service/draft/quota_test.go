@@ -0,0 +1,65 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+func TestReleaseDraftOwner(t *testing.T) {
+	uploader := auth.Principal{Subject: "alice", Method: auth.MethodJWT}
+	other := auth.Principal{Subject: "bob", Method: auth.MethodJWT}
+
+	tests := []struct {
+		name    string
+		release func(ctx context.Context, s *Service, objectName string) error
+	}{
+		{
+			name: "cancel",
+			release: func(ctx context.Context, s *Service, objectName string) error {
+				return s.CancelUpload(ctx, objectName)
+			},
+		},
+		{
+			name: "confirm",
+			release: func(ctx context.Context, s *Service, objectName string) error {
+				_, err := s.ConfirmUpload(ctx, objectName, ConfirmUploadOptions{})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, releaser := range []auth.Principal{uploader, other} {
+			t.Run(tt.name+" by "+releaser.Subject, func(t *testing.T) {
+				repo := repository.NewMemoryRepository()
+				service, fake := newResumableService(t, repo)
+
+				const objectName = "file.bin"
+				content := testContent(10)
+				if _, err := service.GetUploadURL(auth.WithPrincipal(context.Background(), uploader), objectName, UploadURLOptions{Size: int64(len(content))}); err != nil {
+					t.Fatalf("GetUploadURL() error = %v", err)
+				}
+				if _, err := fake.PutObject(context.Background(), service.draftBucket, service.draftKey(objectName), bytes.NewReader(content), int64(len(content)), storage.PutObjectOptions{}); err != nil {
+					t.Fatalf("failed to upload draft: %v", err)
+				}
+
+				if err := tt.release(auth.WithPrincipal(context.Background(), releaser), service, objectName); err != nil {
+					t.Fatalf("release error = %v", err)
+				}
+
+				usage, err := repo.Usage(context.Background(), QuotaOwner(uploader))
+				if err != nil {
+					t.Fatalf("Usage() error = %v", err)
+				}
+				if usage.Drafts != 0 || usage.DraftBytes != 0 {
+					t.Errorf("uploader usage = %+v, want the draft released", usage)
+				}
+			})
+		}
+	}
+}
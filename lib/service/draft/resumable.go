@@ -0,0 +1,625 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// DefaultResumablePartSize is the part size of resumable uploads when
+// ResumablePolicy.PartSize is zero.
+const DefaultResumablePartSize = 8 << 20
+
+// ResumablePolicy configures resumable uploads, which receive a draft in
+// several requests so that interrupted clients continue where they stopped.
+type ResumablePolicy struct {
+	Enabled bool
+	// PartSize is the size of the multipart upload parts the content is
+	// stored in, and the most a request buffers in memory. It also bounds
+	// uploads to storage.MaxParts parts. Defaults to DefaultResumablePartSize
+	// and is raised to storage.MinPartSize.
+	PartSize int64
+	// Lifetime is how long an upload may take before it is discarded.
+	// Defaults to the draft lifetime.
+	Lifetime time.Duration
+}
+
+// MaxSize returns the largest upload the policy can store.
+func (p ResumablePolicy) MaxSize() int64 {
+	return p.PartSize * storage.MaxParts
+}
+
+// CreateUploadOptions customizes CreateUpload.
+type CreateUploadOptions struct {
+	ContentType string
+	// Metadata is kept with the upload and returned by GetUpload.
+	Metadata map[string]string
+}
+
+// ResumableUpload is the state of a resumable upload.
+type ResumableUpload struct {
+	ID string
+	// ObjectName is the draft the upload completes into. It differs from the
+	// requested name when a pre upload hook rewrote it.
+	ObjectName string
+	Size       int64
+	// Offset is how many bytes of the upload were received.
+	Offset    int64
+	Metadata  map[string]string
+	ExpiresAt time.Time
+}
+
+// Complete reports whether the upload was received entirely. Its draft can
+// then be confirmed with ConfirmUpload.
+func (u ResumableUpload) Complete() bool {
+	return u.Offset == u.Size
+}
+
+// CreateUpload starts a resumable upload of size bytes into the draft
+// objectName. Its content is appended with WriteUpload.
+func (s *Service) CreateUpload(ctx context.Context, objectName string, size int64, opts CreateUploadOptions) (ResumableUpload, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "create_upload").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Int64("size", size).
+		Logger()
+
+	log.Info().Msg("Creating resumable upload")
+
+	if !s.resumable.Enabled {
+		return ResumableUpload{}, errors.New("failed to create upload: resumable uploads are disabled")
+	}
+	if err := s.authorize(ctx, authz.OperationUpload, objectName, map[string]any{
+		"size":      size,
+		"resumable": true,
+	}); err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	if err := s.validateSize(ctx, size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected upload with invalid size")
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if size > s.resumable.MaxSize() {
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w: %w: size %d exceeds maximum of %d bytes", ErrValidationFailed, ErrTooLarge, size, s.resumable.MaxSize())
+	}
+
+	hookRequest := HookRequest{
+		ObjectName:  objectName,
+		ContentType: opts.ContentType,
+		Size:        size,
+		Metadata:    opts.Metadata,
+		Principal:   auth.FromContext(ctx),
+	}
+	if err := s.runPreUploadHooks(ctx, &hookRequest); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload rejected by hook")
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if hookRequest.ObjectName != objectName {
+		log.Info().
+			Str("rewritten_object_name", hookRequest.ObjectName).
+			Msg("Object name rewritten by hook")
+		objectName = hookRequest.ObjectName
+	}
+	if objectName == "" {
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w: empty object name", ErrRejected)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	id, err := newUploadID()
+	if err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	// Charge the draft before accepting its content
+	if err := s.reserveDraft(ctx, objectName, size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload exceeds quota")
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	now := time.Now()
+	upload := repository.Upload{
+		ID:          id,
+		Owner:       QuotaOwner(auth.FromContext(ctx)),
+		Key:         objectName,
+		Size:        size,
+		ContentType: opts.ContentType,
+		Metadata:    opts.Metadata,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.resumable.Lifetime),
+	}
+
+	// Multipart uploads need at least one part, so empty drafts are stored directly
+	if size == 0 {
+		_, err = s.storage.PutObject(ctx, s.draftBucket, s.draftKey(objectName), bytes.NewReader(nil), 0, storage.PutObjectOptions{
+			ContentType: opts.ContentType,
+		})
+	} else {
+		upload.MultipartID, err = s.storage.CreateMultipartUpload(ctx, s.draftBucket, s.draftKey(objectName), storage.PutObjectOptions{
+			ContentType: opts.ContentType,
+		})
+	}
+	if err == nil {
+		err = s.repository.SaveUpload(ctx, &upload)
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to create resumable upload")
+		s.discardUpload(ctx, upload)
+		if rErr := s.releaseDraft(ctx, objectName); rErr != nil {
+			log.Error().
+				Err(rErr).
+				Msg("Failed to release draft reservation")
+		}
+		return ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	logger.LogStateChange("create_upload", "upload", id, nil, map[string]interface{}{
+		"object_name": objectName,
+		"bucket":      s.draftBucket,
+		"size":        size,
+	})
+
+	if size == 0 {
+		s.runPostUploadHooks(ctx, hookRequest)
+	}
+
+	log.Info().
+		Str("upload_id", id).
+		Msg("Resumable upload created successfully")
+	return resumableUpload(upload), nil
+}
+
+// GetUpload returns the state of the caller's resumable upload id.
+func (s *Service) GetUpload(ctx context.Context, id string) (ResumableUpload, error) {
+	upload, err := s.loadUpload(ctx, id)
+	if err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to get upload: %w", err)
+	}
+	if err := s.authorize(ctx, authz.OperationUpload, upload.Key, map[string]any{
+		"size":      upload.Size,
+		"resumable": true,
+	}); err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to get upload: %w", err)
+	}
+	return resumableUpload(upload), nil
+}
+
+// WriteUpload appends body to the caller's resumable upload id, which must
+// have reached offset. The bytes received before body ends or fails are
+// kept, so the client continues from the returned offset after an
+// interruption. The last write completes the draft.
+//
+// Every change of the upload is a conditional write of its record. A
+// request racing another one for the same upload, on any replica, fails
+// with ErrOffsetMismatch once either of them changed the record, so only
+// one of them advances the offset.
+func (s *Service) WriteUpload(ctx context.Context, id string, offset int64, body io.Reader) (ResumableUpload, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "write_upload").
+		Str("upload_id", id).
+		Str("bucket", s.draftBucket).
+		Int64("offset", offset).
+		Logger()
+
+	if !s.uploadLocks.lock(id) {
+		return ResumableUpload{}, fmt.Errorf("failed to write upload: %w: %s", ErrUploadLocked, id)
+	}
+	defer s.uploadLocks.unlock(id)
+
+	upload, err := s.loadUpload(ctx, id)
+	if err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to write upload: %w", err)
+	}
+	if err := s.authorize(ctx, authz.OperationUpload, upload.Key, map[string]any{
+		"size":      upload.Size,
+		"resumable": true,
+	}); err != nil {
+		return ResumableUpload{}, fmt.Errorf("failed to write upload: %w", err)
+	}
+	if upload.Offset != offset {
+		return resumableUpload(upload), fmt.Errorf("failed to write upload: %w: upload is at %d, not %d", ErrOffsetMismatch, upload.Offset, offset)
+	}
+	if upload.Complete() {
+		return resumableUpload(upload), nil
+	}
+
+	log = log.With().
+		Str("object_name", upload.Key).
+		Int64("size", upload.Size).
+		Logger()
+
+	log.Info().Msg("Writing resumable upload")
+
+	// The received bytes are stored even when the client goes away mid-request
+	ctx = context.WithoutCancel(ctx)
+
+	// Parts are assembled in a buffer that starts with the pending bytes of
+	// the previous request
+	var committed int64
+	for _, part := range upload.Parts {
+		committed += part.Size
+	}
+	start, pending := committed, upload.Offset-committed
+	buf := make([]byte, s.resumable.PartSize)
+	if pending > 0 {
+		if err := s.readPending(ctx, upload, buf[:pending]); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to read pending upload content")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+	}
+
+	content := io.LimitReader(body, upload.Size-upload.Offset)
+	filled := pending
+	var readErr error
+	for readErr == nil {
+		var n int
+		n, readErr = io.ReadFull(content, buf[filled:])
+		filled += int64(n)
+
+		final := committed+filled == upload.Size
+		if filled < int64(len(buf)) && !final {
+			continue
+		}
+		if final && trailingData(body) {
+			err := fmt.Errorf("%w: received more than %d bytes", ErrInvalidSize, upload.Size)
+			log.Warn().
+				Err(err).
+				Msg("Rejected resumable upload content")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+
+		// Claim the part number first, so that a racing request never
+		// stores a part under the same number
+		number := upload.NextPartNumber()
+		if number > storage.MaxParts {
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w: more than %d parts", ErrInvalidSize, storage.MaxParts)
+		}
+		upload.NextPart = number + 1
+		if err := s.saveUpload(ctx, &upload); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Failed to claim upload part")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+
+		part, err := s.storage.UploadPart(ctx, s.draftBucket, s.draftKey(upload.Key), upload.MultipartID, number, bytes.NewReader(buf[:filled]), filled)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Int("part_number", number).
+				Msg("Failed to store upload part")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+		committed += filled
+		filled = 0
+		upload.Parts = append(upload.Parts, repository.UploadPart{
+			Number: part.Number,
+			ETag:   part.ETag,
+			Size:   part.Size,
+		})
+		upload.Offset = committed
+		consumed := upload
+		upload.Pending = ""
+		if err := s.saveUpload(ctx, &upload); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Failed to save upload state")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+		if pending > 0 {
+			// The pending bytes of the previous request went into this part
+			s.deletePending(ctx, consumed)
+			pending = 0
+		}
+		if final {
+			break
+		}
+	}
+
+	// Bytes that do not fill a part are kept for the next request, under a
+	// new name so that a racing request cannot replace them
+	if filled > 0 && (committed != start || filled != pending) {
+		next := upload
+		next.Offset = committed + filled
+		next.Pending, err = newUploadID()
+		if err != nil {
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+		if _, err := s.storage.PutObject(ctx, s.draftBucket, s.pendingKey(next), bytes.NewReader(buf[:filled]), filled, storage.PutObjectOptions{}); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to store pending upload content")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+		if err := s.saveUpload(ctx, &next); err != nil {
+			log.Warn().
+				Err(err).
+				Msg("Failed to save upload state")
+			s.deletePending(ctx, next)
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+		if pending > 0 {
+			s.deletePending(ctx, upload)
+		}
+		upload = next
+	}
+
+	if upload.Offset == upload.Size {
+		if err := s.completeUpload(ctx, &upload); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to complete resumable upload")
+			return resumableUpload(upload), fmt.Errorf("failed to write upload: %w", err)
+		}
+	}
+
+	if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+		log.Warn().
+			Err(readErr).
+			Int64("received", upload.Offset-offset).
+			Msg("Resumable upload interrupted")
+		return resumableUpload(upload), fmt.Errorf("failed to read upload content: %w", readErr)
+	}
+
+	log.Info().
+		Int64("received", upload.Offset-offset).
+		Int64("upload_offset", upload.Offset).
+		Msg("Resumable upload written successfully")
+	return resumableUpload(upload), nil
+}
+
+// TerminateUpload discards the caller's resumable upload id and, if it was
+// complete, its draft.
+func (s *Service) TerminateUpload(ctx context.Context, id string) error {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "terminate_upload").
+		Str("upload_id", id).
+		Str("bucket", s.draftBucket).
+		Logger()
+
+	log.Info().Msg("Terminating resumable upload")
+
+	if !s.uploadLocks.lock(id) {
+		return fmt.Errorf("failed to terminate upload: %w: %s", ErrUploadLocked, id)
+	}
+	defer s.uploadLocks.unlock(id)
+
+	upload, err := s.loadUpload(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to terminate upload: %w", err)
+	}
+	if err := s.authorize(ctx, authz.OperationCancel, upload.Key, map[string]any{
+		"resumable": true,
+	}); err != nil {
+		return fmt.Errorf("failed to terminate upload: %w", err)
+	}
+
+	s.discardUpload(ctx, upload)
+	if upload.Complete() {
+		if err := s.storage.DeleteObject(ctx, s.draftBucket, s.draftKey(upload.Key)); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			log.Error().
+				Err(err).
+				Msg("Failed to delete draft object")
+			return fmt.Errorf("failed to terminate upload: %w", err)
+		}
+	}
+	if err := s.repository.DeleteUpload(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Error().
+			Err(err).
+			Msg("Failed to delete upload state")
+		return fmt.Errorf("failed to terminate upload: %w", err)
+	}
+	if err := s.releaseDraft(ctx, upload.Key); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to release draft reservation")
+		return fmt.Errorf("failed to terminate upload: %w", err)
+	}
+
+	logger.LogStateChange("terminate_upload", "upload", id,
+		map[string]interface{}{
+			"object_name": upload.Key,
+			"offset":      upload.Offset,
+		},
+		map[string]interface{}{
+			"status": "deleted",
+		})
+
+	log.Info().Msg("Resumable upload terminated successfully")
+	return nil
+}
+
+// loadUpload returns the caller's unexpired resumable upload id.
+func (s *Service) loadUpload(ctx context.Context, id string) (repository.Upload, error) {
+	if !s.resumable.Enabled {
+		return repository.Upload{}, errors.New("resumable uploads are disabled")
+	}
+
+	upload, err := s.repository.GetUpload(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return repository.Upload{}, fmt.Errorf("%w: %s", ErrUploadNotFound, id)
+	}
+	if err != nil {
+		return repository.Upload{}, fmt.Errorf("failed to load upload %s: %w", id, err)
+	}
+
+	// Uploads of other owners are reported as missing, so that IDs cannot be probed
+	if upload.Owner != QuotaOwner(auth.FromContext(ctx)) || !upload.ExpiresAt.After(time.Now()) {
+		return repository.Upload{}, fmt.Errorf("%w: %s", ErrUploadNotFound, id)
+	}
+	return upload, nil
+}
+
+// saveUpload stores the state of a resumable upload. Uploads changed by
+// another request in the meantime are reported as ErrOffsetMismatch.
+func (s *Service) saveUpload(ctx context.Context, upload *repository.Upload) error {
+	if err := s.repository.SaveUpload(ctx, upload); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return fmt.Errorf("%w: upload changed concurrently: %w", ErrOffsetMismatch, err)
+		}
+		return fmt.Errorf("failed to save upload %s: %w", upload.ID, err)
+	}
+	return nil
+}
+
+// readPending reads the pending bytes of a resumable upload into buf.
+func (s *Service) readPending(ctx context.Context, upload repository.Upload, buf []byte) error {
+	reader, err := s.storage.GetObject(ctx, s.draftBucket, s.pendingKey(upload), storage.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to open pending content: %w", err)
+	}
+	defer reader.Close()
+
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		return fmt.Errorf("failed to read pending content: %w", err)
+	}
+	return nil
+}
+
+// completeUpload assembles the parts of a received upload into its draft.
+func (s *Service) completeUpload(ctx context.Context, upload *repository.Upload) error {
+	parts := make([]storage.Part, 0, len(upload.Parts))
+	for _, part := range upload.Parts {
+		parts = append(parts, storage.Part{
+			Number: part.Number,
+			ETag:   part.ETag,
+			Size:   part.Size,
+		})
+	}
+	if err := s.storage.CompleteMultipartUpload(ctx, s.draftBucket, s.draftKey(upload.Key), upload.MultipartID, parts); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	// The upload is kept until it expires, so that a client retrying the
+	// last request learns that it is complete
+	upload.MultipartID = ""
+	if err := s.saveUpload(ctx, upload); err != nil {
+		return err
+	}
+
+	logger.LogStateChange("upload", "object", upload.Key, nil, map[string]interface{}{
+		"bucket":    s.draftBucket,
+		"size":      upload.Size,
+		"upload_id": upload.ID,
+	})
+
+	s.runPostUploadHooks(ctx, HookRequest{
+		ObjectName:  upload.Key,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		Metadata:    upload.Metadata,
+		Principal:   auth.FromContext(ctx),
+	})
+	return nil
+}
+
+// discardUpload aborts the multipart upload of an upload and deletes its
+// pending bytes. Failures are only logged, since the storage cleans up
+// after the draft lifetime anyway.
+func (s *Service) discardUpload(ctx context.Context, upload repository.Upload) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("upload_id", upload.ID).
+		Logger()
+
+	if upload.MultipartID != "" {
+		if err := s.storage.AbortMultipartUpload(ctx, s.draftBucket, s.draftKey(upload.Key), upload.MultipartID); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			log.Error().
+				Err(err).
+				Msg("Failed to abort multipart upload")
+		}
+	}
+	s.deletePending(ctx, upload)
+}
+
+// deletePending deletes the pending bytes of a resumable upload. Failures
+// are only logged, since the storage cleans up after the draft lifetime
+// anyway.
+func (s *Service) deletePending(ctx context.Context, upload repository.Upload) {
+	if err := s.storage.DeleteObject(ctx, s.draftBucket, s.pendingKey(upload)); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		log := logger.GetServiceLogger("draft-service")
+		log.Warn().
+			Err(err).
+			Str("upload_id", upload.ID).
+			Msg("Failed to delete pending upload content")
+	}
+}
+
+// pendingKey returns the key of the pending bytes of a resumable upload.
+func (s *Service) pendingKey(upload repository.Upload) string {
+	return s.draftKey(storage.PendingUploadPrefix + upload.PendingName())
+}
+
+func resumableUpload(upload repository.Upload) ResumableUpload {
+	return ResumableUpload{
+		ID:         upload.ID,
+		ObjectName: upload.Key,
+		Size:       upload.Size,
+		Offset:     upload.Offset,
+		Metadata:   upload.Metadata,
+		ExpiresAt:  upload.ExpiresAt,
+	}
+}
+
+// newUploadID returns a random resumable upload ID.
+func newUploadID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate upload ID: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// uploadLocks serializes the requests writing to the same resumable upload
+// within the process.
+type uploadLocks struct {
+	mu     sync.Mutex
+	active map[string]struct{}
+}
+
+// lock reports whether the upload id was locked; it fails while another
+// request holds it.
+func (l *uploadLocks) lock(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.active[id]; ok {
+		return false
+	}
+	if l.active == nil {
+		l.active = make(map[string]struct{})
+	}
+	l.active[id] = struct{}{}
+	return true
+}
+
+func (l *uploadLocks) unlock(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.active, id)
+}
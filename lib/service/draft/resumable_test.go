@@ -0,0 +1,213 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+const testBucket = "test"
+
+func newResumableService(t *testing.T, repo repository.Repository) (*Service, *fakeStorage) {
+	t.Helper()
+
+	fake := newFakeStorage()
+	service, err := NewService(ServiceOptions{
+		BucketName: testBucket,
+		Storage:    fake,
+		Repository: repo,
+		Resumable: ResumablePolicy{
+			Enabled:  true,
+			PartSize: storage.MinPartSize,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	t.Cleanup(service.Close)
+	return service, fake
+}
+
+func testContent(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+	return content
+}
+
+func TestWriteUpload(t *testing.T) {
+	const part = storage.MinPartSize
+
+	tests := []struct {
+		name   string
+		writes []int
+		parts  int
+	}{
+		{name: "single request", writes: []int{2*part + 100}, parts: 3},
+		{name: "request per part", writes: []int{part, part, 100}, parts: 3},
+		{name: "pending bytes carried over", writes: []int{100, part, part}, parts: 3},
+		{name: "pending bytes completed", writes: []int{part - 1, 1, 100}, parts: 2},
+		{name: "smaller than a part", writes: []int{10, 20, 30}, parts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := repository.NewMemoryRepository()
+			service, fake := newResumableService(t, repo)
+
+			size := 0
+			for _, n := range tt.writes {
+				size += n
+			}
+			content := testContent(size)
+
+			upload, err := service.CreateUpload(ctx, "file.bin", int64(size), CreateUploadOptions{})
+			if err != nil {
+				t.Fatalf("CreateUpload() error = %v", err)
+			}
+
+			offset := 0
+			for _, n := range tt.writes {
+				upload, err = service.WriteUpload(ctx, upload.ID, int64(offset), bytes.NewReader(content[offset:offset+n]))
+				if err != nil {
+					t.Fatalf("WriteUpload(%d) error = %v", offset, err)
+				}
+				offset += n
+				if upload.Offset != int64(offset) {
+					t.Fatalf("WriteUpload() offset = %d, want %d", upload.Offset, offset)
+				}
+			}
+
+			if !upload.Complete() {
+				t.Fatalf("upload incomplete at %d of %d", upload.Offset, upload.Size)
+			}
+			stored, err := repo.GetUpload(ctx, upload.ID)
+			if err != nil {
+				t.Fatalf("GetUpload() error = %v", err)
+			}
+			if len(stored.Parts) != tt.parts {
+				t.Errorf("stored %d parts, want %d", len(stored.Parts), tt.parts)
+			}
+			draft, ok := fake.content(service.draftBucket, "file.bin")
+			if !ok || !bytes.Equal(draft, content) {
+				t.Errorf("draft content differs from the written content")
+			}
+			if keys := fake.keys(service.draftBucket, storage.PendingUploadPrefix); len(keys) > 0 {
+				t.Errorf("pending content left behind: %v", keys)
+			}
+		})
+	}
+}
+
+func TestWriteUploadOffsetMismatch(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newResumableService(t, repository.NewMemoryRepository())
+
+	upload, err := service.CreateUpload(ctx, "file.bin", 100, CreateUploadOptions{})
+	if err != nil {
+		t.Fatalf("CreateUpload() error = %v", err)
+	}
+	if _, err := service.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(testContent(10))); err != nil {
+		t.Fatalf("WriteUpload() error = %v", err)
+	}
+
+	upload, err = service.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(testContent(10)))
+	if !errors.Is(err, ErrOffsetMismatch) {
+		t.Fatalf("WriteUpload() error = %v, want %v", err, ErrOffsetMismatch)
+	}
+	if upload.Offset != 10 {
+		t.Errorf("WriteUpload() offset = %d, want 10", upload.Offset)
+	}
+}
+
+// racingRepository saves an upload once behind the back of the service, as
+// a request on another replica would, before the service saves it.
+type racingRepository struct {
+	repository.Repository
+	race bool
+}
+
+func (r *racingRepository) SaveUpload(ctx context.Context, upload *repository.Upload) error {
+	if r.race && upload.Version != "" {
+		r.race = false
+		stored, err := r.Repository.GetUpload(ctx, upload.ID)
+		if err != nil {
+			return err
+		}
+		if err := r.Repository.SaveUpload(ctx, &stored); err != nil {
+			return err
+		}
+	}
+	return r.Repository.SaveUpload(ctx, upload)
+}
+
+func TestWriteUploadConflict(t *testing.T) {
+	const part = storage.MinPartSize
+
+	tests := []struct {
+		name   string
+		before int
+		write  int
+	}{
+		{name: "while claiming a part", before: 0, write: part},
+		{name: "while storing pending bytes", before: 0, write: 100},
+		{name: "while replacing pending bytes", before: 100, write: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := &racingRepository{Repository: repository.NewMemoryRepository()}
+			service, fake := newResumableService(t, repo)
+			content := testContent(2 * part)
+
+			upload, err := service.CreateUpload(ctx, "file.bin", int64(len(content)), CreateUploadOptions{})
+			if err != nil {
+				t.Fatalf("CreateUpload() error = %v", err)
+			}
+			if tt.before > 0 {
+				if _, err := service.WriteUpload(ctx, upload.ID, 0, bytes.NewReader(content[:tt.before])); err != nil {
+					t.Fatalf("WriteUpload() error = %v", err)
+				}
+			}
+			pending := fake.keys(service.draftBucket, storage.PendingUploadPrefix)
+
+			repo.race = true
+			_, err = service.WriteUpload(ctx, upload.ID, int64(tt.before), bytes.NewReader(content[tt.before:tt.before+tt.write]))
+			if !errors.Is(err, ErrOffsetMismatch) {
+				t.Fatalf("WriteUpload() error = %v, want %v", err, ErrOffsetMismatch)
+			}
+
+			stored, err := repo.GetUpload(ctx, upload.ID)
+			if err != nil {
+				t.Fatalf("GetUpload() error = %v", err)
+			}
+			if stored.Offset != int64(tt.before) {
+				t.Errorf("stored offset = %d, want %d", stored.Offset, tt.before)
+			}
+			if keys := fake.keys(service.draftBucket, storage.PendingUploadPrefix); !slices.Equal(keys, pending) {
+				t.Errorf("pending content = %v, want %v", keys, pending)
+			}
+
+			// The upload continues from the stored offset
+			upload, err = service.WriteUpload(ctx, upload.ID, int64(tt.before), bytes.NewReader(content[tt.before:]))
+			if err != nil {
+				t.Fatalf("WriteUpload() error = %v", err)
+			}
+			if !upload.Complete() {
+				t.Fatalf("upload incomplete at %d of %d", upload.Offset, upload.Size)
+			}
+			draft, _ := fake.content(service.draftBucket, "file.bin")
+			if !bytes.Equal(draft, content) {
+				t.Errorf("draft content differs from the written content")
+			}
+		})
+	}
+}
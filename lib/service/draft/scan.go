@@ -0,0 +1,66 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/snowmerak/DraftStore/lib/scanner"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// scanDraft streams the version info of the draft object through the
+// configured malware scanner. Infected drafts are reported wrapping
+// ErrInfected. A scanner that cannot be reached or times out is ignored when
+// the service is configured to fail open; every other scan error, such as a
+// size limit or an unexpected reply, rejects the draft.
+func (s *Service) scanDraft(ctx context.Context, objectName string, info storage.ObjectInfo) error {
+	if s.scanner == nil {
+		return nil
+	}
+
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "scan_draft").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Bool("fail_open", s.scanFailOpen).
+		Logger()
+
+	log.Info().Msg("Scanning draft object")
+
+	reader, err := s.storage.GetObject(ctx, s.draftBucket, s.draftKey(objectName), storage.GetObjectOptions{
+		IfMatch: info.ETag,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to read draft object")
+		return fmt.Errorf("failed to read draft object: %w", err)
+	}
+	defer reader.Close()
+
+	result, err := s.scanner.Scan(ctx, reader)
+	if err != nil {
+		if s.scanFailOpen && errors.Is(err, scanner.ErrUnavailable) {
+			log.Warn().
+				Err(err).
+				Msg("Malware scan failed, continuing because scanner fails open")
+			return nil
+		}
+		log.Error().
+			Err(err).
+			Msg("Malware scan failed")
+		return fmt.Errorf("failed to scan draft object: %w", err)
+	}
+
+	if result.Infected {
+		log.Warn().
+			Str("signature", result.Signature).
+			Msg("Malware detected in draft object")
+		return fmt.Errorf("%w: %s", ErrInfected, result.Signature)
+	}
+
+	log.Info().Msg("Draft object scanned clean")
+	return nil
+}
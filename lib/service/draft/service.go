@@ -2,48 +2,262 @@ package draft
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/processor"
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/scanner"
 	"github.com/snowmerak/DraftStore/lib/storage"
 	"github.com/snowmerak/DraftStore/lib/util/logger"
 )
 
 const (
-	DefaultDraftBucketSuffix = "-draft"
+	DefaultDraftBucketSuffix = storage.DefaultDraftBucketSuffix
 )
 
 type Service struct {
-	bucketName  string
-	draftBucket string
-	storage     storage.Storage
-	uploadTTL   time.Duration
-	downloadTTL time.Duration
+	tenant           string
+	bucketName       string
+	draftBucket      string
+	staging          storage.DraftStaging
+	storage          storage.Storage
+	uploadTTL        time.Duration
+	downloadTTL      time.Duration
+	maxDownloadTTL   time.Duration
+	draftDownloadTTL time.Duration
+	validation       ValidationPolicy
+	scanner          scanner.Scanner
+	scanFailOpen     bool
+	requireChecksum  bool
+	dedup            bool
+	processors       []processor.Processor
+	hooks            Hooks
+	authorizer       authz.Authorizer
+	repository       repository.Repository
+	quotas           QuotaPolicy
+	draftLifetime    time.Duration
+	links            LinkPolicy
+	linkCache        *linkCache
+	resumable        ResumablePolicy
+	uploadLocks      uploadLocks
+	processQueue     chan string
+	processWG        sync.WaitGroup
+	closeOnce        sync.Once
 }
 
 type ServiceOptions struct {
-	BucketName  string
-	Storage     storage.Storage
-	UploadTTL   time.Duration
-	DownloadTTL time.Duration
+	// Tenant names the tenant the service belongs to. It is logged and passed
+	// to the authorizer as the "tenant" request field.
+	Tenant     string
+	BucketName string
+	// DraftStaging selects where drafts are kept until they are confirmed:
+	// a separate draft bucket or a prefix of the main bucket.
+	DraftStaging storage.DraftStaging
+	Storage      storage.Storage
+	UploadTTL    time.Duration
+	DownloadTTL  time.Duration
+	// MaxDownloadTTL caps the TTL a caller may request for a download URL.
+	// Defaults to DownloadTTL when zero.
+	MaxDownloadTTL time.Duration
+	// DraftDownloadTTL is the lifetime of preview URLs for unconfirmed drafts.
+	// It must not exceed DownloadTTL.
+	DraftDownloadTTL time.Duration
+	// Validation is enforced by ConfirmUpload before a draft is promoted.
+	Validation ValidationPolicy
+	// Scanner, when set, scans every draft for malware before it is promoted.
+	Scanner scanner.Scanner
+	// ScanFailOpen lets confirmation proceed when the scanner cannot be
+	// reached or times out. Other scan errors always fail confirmation.
+	ScanFailOpen bool
+	// RequireChecksum rejects upload URL requests without a declared checksum
+	// and drafts that have no stored checksum at confirmation.
+	RequireChecksum bool
+	// Dedup stores confirmed content once under its SHA-256 and keeps the
+	// object name as a lightweight pointer to it.
+	Dedup bool
+	// Processors derive additional objects, such as thumbnails, from every
+	// confirmed object whose content type they accept.
+	Processors []processor.Processor
+	// ProcessorWorkers runs processors on a pool of that many workers.
+	// Zero runs them inline before ConfirmUpload returns.
+	ProcessorWorkers int
+	// Hooks run around upload URL issuance and confirmation.
+	Hooks Hooks
+	// Authorizer, when set, must allow every operation before it runs.
+	Authorizer authz.Authorizer
+	// Repository, when set, accounts the drafts and confirmed bytes of every
+	// owner and enforces Quotas.
+	Repository repository.Repository
+	Quotas     QuotaPolicy
+	// DraftLifetime is how long an unconfirmed draft counts against quotas.
+	// It should match the cleaner's object lifetime. Defaults to DefaultDraftLifetime.
+	DraftLifetime time.Duration
+	// Links configures the download links resolved by ResolveLink.
+	Links LinkPolicy
+	// Resumable configures resumable uploads. They keep their state in
+	// Repository, which is required when they are enabled.
+	Resumable ResumablePolicy
+}
+
+// UploadURLOptions customizes an upload URL issued by GetUploadURL.
+type UploadURLOptions struct {
+	// Checksum is the client's digest of the file. The storage backend
+	// rejects uploads whose content does not match it.
+	Checksum storage.Checksum
+	// Size is the declared file size in bytes. The storage backend rejects
+	// uploads of any other size. It is required when the caller's quota
+	// limits draft bytes.
+	Size int64
+}
+
+// UploadURL is a presigned upload URL and the headers the client must send with it.
+type UploadURL struct {
+	URL             string
+	RequiredHeaders map[string]string
+	// ObjectName is the draft key the URL uploads to. It differs from the
+	// requested name when a pre upload hook rewrote it.
+	ObjectName string
+}
+
+// ConfirmUploadOptions customizes ConfirmUpload.
+type ConfirmUploadOptions struct {
+	// Checksum, when set, must match the checksum stored with the draft.
+	Checksum storage.Checksum
+}
+
+// DownloadURLOptions customizes a download URL issued by GetDownloadURL.
+type DownloadURLOptions struct {
+	// TTL overrides the default download TTL. It is capped by MaxDownloadTTL.
+	TTL time.Duration
+	// Variant selects a derivative generated by a processor instead of the original.
+	Variant                    string
+	ResponseContentDisposition string
+	ResponseContentType        string
+	ResponseCacheControl       string
+}
+
+// DraftInfo describes an unconfirmed draft.
+type DraftInfo struct {
+	ObjectName   string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+	// Stored checksums, base64 encoded. Empty when the draft has none.
+	ChecksumSHA256 string
+	ChecksumCRC32C string
 }
 
 func NewService(opts ServiceOptions) (*Service, error) {
 	log := logger.GetServiceLogger("draft-service")
 
+	if err := opts.DraftStaging.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.DraftDownloadTTL > opts.DownloadTTL {
+		return nil, fmt.Errorf("draft download TTL %s exceeds download TTL %s", opts.DraftDownloadTTL, opts.DownloadTTL)
+	}
+
 	service := &Service{
-		storage:     opts.Storage,
-		bucketName:  opts.BucketName,
-		draftBucket: opts.BucketName + DefaultDraftBucketSuffix,
-		uploadTTL:   opts.UploadTTL,
-		downloadTTL: opts.DownloadTTL,
+		tenant:           opts.Tenant,
+		storage:          opts.Storage,
+		bucketName:       opts.BucketName,
+		draftBucket:      opts.DraftStaging.Bucket(opts.BucketName),
+		staging:          opts.DraftStaging,
+		uploadTTL:        opts.UploadTTL,
+		downloadTTL:      opts.DownloadTTL,
+		maxDownloadTTL:   opts.MaxDownloadTTL,
+		draftDownloadTTL: opts.DraftDownloadTTL,
+		validation:       opts.Validation,
+		scanner:          opts.Scanner,
+		scanFailOpen:     opts.ScanFailOpen,
+		requireChecksum:  opts.RequireChecksum,
+		dedup:            opts.Dedup,
+		processors:       opts.Processors,
+		hooks:            opts.Hooks,
+		authorizer:       opts.Authorizer,
+		repository:       opts.Repository,
+		quotas:           opts.Quotas,
+		draftLifetime:    opts.DraftLifetime,
+		links:            opts.Links,
+		linkCache:        newLinkCache(opts.Links.MaxCachedURLs),
+		resumable:        opts.Resumable,
+	}
+
+	if service.draftLifetime <= 0 {
+		service.draftLifetime = DefaultDraftLifetime
+	}
+	if service.maxDownloadTTL <= 0 {
+		service.maxDownloadTTL = service.downloadTTL
+	}
+	if service.links.MaxTTL <= 0 {
+		service.links.MaxTTL = DefaultMaxLinkTTL
+	}
+	if service.links.URLTTL <= 0 {
+		service.links.URLTTL = service.downloadTTL
+	}
+	if service.links.RefreshBefore <= 0 || service.links.RefreshBefore >= service.links.URLTTL {
+		service.links.RefreshBefore = service.links.URLTTL / 5
+	}
+	if service.resumable.Enabled && service.repository == nil {
+		return nil, errors.New("resumable uploads require a repository")
+	}
+	if service.resumable.PartSize <= 0 {
+		service.resumable.PartSize = DefaultResumablePartSize
+	}
+	if service.resumable.PartSize < storage.MinPartSize {
+		service.resumable.PartSize = storage.MinPartSize
+	}
+	if service.resumable.Lifetime <= 0 {
+		service.resumable.Lifetime = service.draftLifetime
+	}
+	if len(service.processors) > 0 && opts.ProcessorWorkers > 0 {
+		service.startProcessWorkers(opts.ProcessorWorkers)
 	}
 
 	log.Info().
+		Str("tenant", service.tenant).
 		Str("bucket_name", service.bucketName).
 		Str("draft_bucket", service.draftBucket).
+		Str("draft_prefix", service.staging.KeyPrefix()).
 		Dur("upload_ttl", service.uploadTTL).
 		Dur("download_ttl", service.downloadTTL).
+		Dur("max_download_ttl", service.maxDownloadTTL).
+		Dur("draft_download_ttl", service.draftDownloadTTL).
+		Int64("validation_max_size", service.validation.MaxSize).
+		Strs("validation_allowed_types", service.validation.AllowedContentTypes).
+		Bool("validation_extension_match", service.validation.RequireExtensionMatch).
+		Str("quarantine_bucket", service.validation.QuarantineBucket).
+		Bool("scanner_enabled", service.scanner != nil).
+		Bool("scan_fail_open", service.scanFailOpen).
+		Bool("require_checksum", service.requireChecksum).
+		Bool("dedup", service.dedup).
+		Int("processors", len(service.processors)).
+		Int("processor_workers", opts.ProcessorWorkers).
+		Int("pre_upload_hooks", len(service.hooks.PreUpload)).
+		Int("post_upload_hooks", len(service.hooks.PostUpload)).
+		Int("pre_confirm_hooks", len(service.hooks.PreConfirm)).
+		Int("post_confirm_hooks", len(service.hooks.PostConfirm)).
+		Bool("authorizer_enabled", service.authorizer != nil).
+		Bool("quotas_enabled", service.repository != nil).
+		Int64("quota_max_drafts", service.quotas.Default.MaxDrafts).
+		Int64("quota_max_draft_bytes", service.quotas.Default.MaxDraftBytes).
+		Int64("quota_max_confirmed_bytes", service.quotas.Default.MaxConfirmedBytes).
+		Int("quota_overrides", len(service.quotas.Owners)).
+		Dur("draft_lifetime", service.draftLifetime).
+		Strs("link_public_prefixes", service.links.PublicPrefixes).
+		Bool("signed_links_enabled", len(service.links.Secret) > 0).
+		Dur("link_max_ttl", service.links.MaxTTL).
+		Dur("link_url_ttl", service.links.URLTTL).
+		Bool("link_cache_enabled", service.linkCache != nil).
+		Bool("resumable_enabled", service.resumable.Enabled).
+		Int64("resumable_part_size", service.resumable.PartSize).
+		Dur("resumable_lifetime", service.resumable.Lifetime).
 		Msg("Draft service initialized")
 
 	return service, nil
@@ -58,34 +272,40 @@ func (s *Service) CreateDraftBucket(ctx context.Context) error {
 
 	log.Info().Msg("Starting bucket creation operation")
 
-	// Check if draft bucket exists
-	exists, err := s.storage.ExistsBucket(ctx, s.draftBucket)
-	if err != nil {
-		log.Error().
-			Err(err).
-			Msg("Failed to check if draft bucket exists")
-		return fmt.Errorf("failed to check if draft bucket exists: %w", err)
+	if err := s.authorize(ctx, authz.OperationCreateBucket, "", nil); err != nil {
+		return err
 	}
 
-	if exists {
-		log.Info().Msg("Draft bucket already exists")
-	} else {
-		log.Info().Msg("Creating draft bucket")
-		if err := s.storage.CreateBucket(ctx, s.draftBucket); err != nil {
+	// Drafts staged under a prefix live in the main bucket
+	if !s.staging.Prefixed() {
+		exists, err := s.storage.ExistsBucket(ctx, s.draftBucket)
+		if err != nil {
 			log.Error().
 				Err(err).
-				Msg("Failed to create draft bucket")
-			return fmt.Errorf("failed to create draft bucket %s: %w", s.draftBucket, err)
+				Msg("Failed to check if draft bucket exists")
+			return fmt.Errorf("failed to check if draft bucket exists: %w", err)
 		}
 
-		logger.LogStateChange("create", "bucket", s.draftBucket, nil, map[string]interface{}{
-			"bucket_name": s.draftBucket,
-			"type":        "draft",
-		})
+		if exists {
+			log.Info().Msg("Draft bucket already exists")
+		} else {
+			log.Info().Msg("Creating draft bucket")
+			if err := s.storage.CreateBucket(ctx, s.draftBucket); err != nil {
+				log.Error().
+					Err(err).
+					Msg("Failed to create draft bucket")
+				return fmt.Errorf("failed to create draft bucket %s: %w", s.draftBucket, err)
+			}
+
+			logger.LogStateChange("create", "bucket", s.draftBucket, nil, map[string]interface{}{
+				"bucket_name": s.draftBucket,
+				"type":        "draft",
+			})
+		}
 	}
 
 	// Check if main bucket exists
-	exists, err = s.storage.ExistsBucket(ctx, s.bucketName)
+	exists, err := s.storage.ExistsBucket(ctx, s.bucketName)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -110,45 +330,189 @@ func (s *Service) CreateDraftBucket(ctx context.Context) error {
 		})
 	}
 
+	// Check if quarantine bucket exists
+	if s.validation.QuarantineBucket != "" {
+		exists, err = s.storage.ExistsBucket(ctx, s.validation.QuarantineBucket)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to check if quarantine bucket exists")
+			return fmt.Errorf("failed to check if quarantine bucket exists: %w", err)
+		}
+
+		if exists {
+			log.Info().Msg("Quarantine bucket already exists")
+		} else {
+			log.Info().Msg("Creating quarantine bucket")
+			if err := s.storage.CreateBucket(ctx, s.validation.QuarantineBucket); err != nil {
+				log.Error().
+					Err(err).
+					Msg("Failed to create quarantine bucket")
+				return fmt.Errorf("failed to create quarantine bucket %s: %w", s.validation.QuarantineBucket, err)
+			}
+
+			logger.LogStateChange("create", "bucket", s.validation.QuarantineBucket, nil, map[string]interface{}{
+				"bucket_name": s.validation.QuarantineBucket,
+				"type":        "quarantine",
+			})
+		}
+	}
+
 	log.Info().Msg("Bucket creation operation completed successfully")
 	return nil
 }
 
-func (s *Service) GetUploadURL(ctx context.Context, objectName string) (string, error) {
+func (s *Service) GetUploadURL(ctx context.Context, objectName string, opts UploadURLOptions) (UploadURL, error) {
 	log := logger.GetServiceLogger("draft-service").With().
 		Str("operation", "get_upload_url").
 		Str("object_name", objectName).
-		Str("bucket", s.bucketName).
+		Str("bucket", s.draftBucket).
 		Dur("ttl", s.uploadTTL).
+		Str("checksum_algorithm", string(opts.Checksum.Algorithm)).
+		Int64("size", opts.Size).
 		Logger()
 
 	log.Info().Msg("Generating upload URL")
 
-	url, err := s.storage.MakeUploadPresignedURL(ctx, s.bucketName, objectName, s.uploadTTL)
+	if err := s.authorize(ctx, authz.OperationUpload, objectName, map[string]any{
+		"checksum_algorithm": string(opts.Checksum.Algorithm),
+		"size":               opts.Size,
+	}); err != nil {
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	if err := validateChecksum(opts.Checksum); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected malformed checksum")
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+	if s.requireChecksum && opts.Checksum.Algorithm == storage.ChecksumAlgorithmNone {
+		log.Warn().Msg("Rejected upload URL request without checksum")
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w: a checksum is required", ErrInvalidChecksum)
+	}
+	if err := s.validateSize(ctx, opts.Size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected upload URL request with invalid size")
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	hookRequest := HookRequest{
+		ObjectName: objectName,
+		Principal:  auth.FromContext(ctx),
+	}
+	if err := s.runPreUploadHooks(ctx, &hookRequest); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload URL request rejected by hook")
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+	if hookRequest.ObjectName != objectName {
+		log.Info().
+			Str("rewritten_object_name", hookRequest.ObjectName).
+			Msg("Object name rewritten by hook")
+		objectName = hookRequest.ObjectName
+	}
+	if objectName == "" {
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w: empty object name", ErrRejected)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	// Charge the draft before issuing a URL for it
+	if err := s.reserveDraft(ctx, objectName, opts.Size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload URL request exceeds quota")
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	presigned, err := s.storage.MakeUploadPresignedURL(ctx, s.draftBucket, s.draftKey(objectName), s.uploadTTL, storage.UploadPresignedURLOptions{
+		Checksum:      opts.Checksum,
+		ContentLength: opts.Size,
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
 			Msg("Failed to generate upload URL")
-		return "", fmt.Errorf("failed to get upload URL: %w", err)
+		if rErr := s.releaseDraft(ctx, objectName); rErr != nil {
+			log.Error().
+				Err(rErr).
+				Msg("Failed to release draft reservation")
+		}
+		return UploadURL{}, fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	headers := make(map[string]string, len(presigned.SignedHeaders))
+	for name := range presigned.SignedHeaders {
+		headers[name] = presigned.SignedHeaders.Get(name)
 	}
 
 	log.Info().
-		Str("url_length", fmt.Sprintf("%d", len(url))).
+		Str("url_length", fmt.Sprintf("%d", len(presigned.URL))).
+		Int("required_headers", len(headers)).
 		Msg("Upload URL generated successfully")
-	return url, nil
+
+	s.runPostUploadHooks(ctx, hookRequest)
+
+	return UploadURL{
+		URL:             presigned.URL,
+		RequiredHeaders: headers,
+		ObjectName:      objectName,
+	}, nil
 }
 
-func (s *Service) GetDownloadURL(ctx context.Context, objectName string) (string, error) {
+func (s *Service) GetDownloadURL(ctx context.Context, objectName string, opts DownloadURLOptions) (string, error) {
+	ttl := s.downloadTTL
+	if opts.TTL > 0 {
+		ttl = min(opts.TTL, s.maxDownloadTTL)
+	}
+
 	log := logger.GetServiceLogger("draft-service").With().
 		Str("operation", "get_download_url").
 		Str("object_name", objectName).
+		Str("variant", opts.Variant).
 		Str("bucket", s.bucketName).
-		Dur("ttl", s.downloadTTL).
+		Dur("ttl", ttl).
 		Logger()
 
 	log.Info().Msg("Generating download URL")
 
-	url, err := s.storage.MakeGetPresignedURL(ctx, s.bucketName, objectName, s.downloadTTL)
+	if err := s.authorize(ctx, authz.OperationDownload, objectName, map[string]any{
+		"variant":                      opts.Variant,
+		"ttl_seconds":                  int64(opts.TTL.Seconds()),
+		"response_content_disposition": opts.ResponseContentDisposition,
+		"response_content_type":        opts.ResponseContentType,
+		"response_cache_control":       opts.ResponseCacheControl,
+	}); err != nil {
+		return "", fmt.Errorf("failed to get download URL: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return "", fmt.Errorf("failed to get download URL: %w", err)
+	}
+
+	if opts.Variant != "" {
+		objectName = DerivativeKey(objectName, opts.Variant)
+	}
+
+	target, contentType, err := s.resolveObject(ctx, objectName)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to resolve object")
+		return "", fmt.Errorf("failed to get download URL: %w", err)
+	}
+	if opts.ResponseContentType == "" {
+		opts.ResponseContentType = contentType
+	}
+
+	url, err := s.storage.MakeGetPresignedURL(ctx, s.bucketName, target, ttl, storage.GetPresignedURLOptions{
+		ResponseContentDisposition: opts.ResponseContentDisposition,
+		ResponseContentType:        opts.ResponseContentType,
+		ResponseCacheControl:       opts.ResponseCacheControl,
+	})
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -162,7 +526,81 @@ func (s *Service) GetDownloadURL(ctx context.Context, objectName string) (string
 	return url, nil
 }
 
-func (s *Service) ConfirmUpload(ctx context.Context, objectName string) error {
+func (s *Service) GetDraftDownloadURL(ctx context.Context, objectName string) (string, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "get_draft_download_url").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Dur("ttl", s.draftDownloadTTL).
+		Logger()
+
+	log.Info().Msg("Generating draft download URL")
+
+	if err := s.authorize(ctx, authz.OperationDraftDownload, objectName, nil); err != nil {
+		return "", fmt.Errorf("failed to get draft download URL: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return "", fmt.Errorf("failed to get draft download URL: %w", err)
+	}
+
+	url, err := s.storage.MakeGetPresignedURL(ctx, s.draftBucket, s.draftKey(objectName), s.draftDownloadTTL, storage.GetPresignedURLOptions{})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to generate draft download URL")
+		return "", fmt.Errorf("failed to get draft download URL: %w", err)
+	}
+
+	log.Info().
+		Str("url_length", fmt.Sprintf("%d", len(url))).
+		Msg("Draft download URL generated successfully")
+	return url, nil
+}
+
+// StatDraft returns the metadata of the unconfirmed draft objectName. It is
+// authorized as a draft download since it reveals the draft's content type
+// and checksums.
+func (s *Service) StatDraft(ctx context.Context, objectName string) (DraftInfo, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "stat_draft").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Logger()
+
+	log.Info().Msg("Getting draft metadata")
+
+	if err := s.authorize(ctx, authz.OperationDraftDownload, objectName, nil); err != nil {
+		return DraftInfo{}, fmt.Errorf("failed to stat draft: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return DraftInfo{}, fmt.Errorf("failed to stat draft: %w", err)
+	}
+
+	info, err := s.storage.StatObject(ctx, s.draftBucket, s.draftKey(objectName))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to stat draft object")
+		return DraftInfo{}, fmt.Errorf("failed to stat draft: %w", err)
+	}
+
+	log.Info().
+		Int64("size", info.Size).
+		Msg("Draft metadata retrieved successfully")
+	return DraftInfo{
+		ObjectName:     objectName,
+		Size:           info.Size,
+		ContentType:    info.ContentType,
+		ETag:           info.ETag,
+		LastModified:   info.LastModified,
+		ChecksumSHA256: info.ChecksumSHA256,
+		ChecksumCRC32C: info.ChecksumCRC32C,
+	}, nil
+}
+
+// ConfirmUpload promotes the draft objectName to the main bucket and returns
+// the key it was stored under.
+func (s *Service) ConfirmUpload(ctx context.Context, objectName string, opts ConfirmUploadOptions) (string, error) {
 	log := logger.GetServiceLogger("draft-service").With().
 		Str("operation", "confirm_upload").
 		Str("object_name", objectName).
@@ -172,22 +610,117 @@ func (s *Service) ConfirmUpload(ctx context.Context, objectName string) error {
 
 	log.Info().Msg("Starting upload confirmation process")
 
-	// Copy object from draft bucket to main bucket
-	if err := s.storage.CopyObject(ctx, s.draftBucket, objectName, s.bucketName, objectName); err != nil {
+	if err := s.authorize(ctx, authz.OperationConfirm, objectName, map[string]any{
+		"checksum_algorithm": string(opts.Checksum.Algorithm),
+	}); err != nil {
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+
+	// Every check inspects this version of the draft, and the promotion
+	// fails if the draft was replaced in the meantime
+	info, err := s.storage.StatObject(ctx, s.draftBucket, s.draftKey(objectName))
+	if err != nil {
 		log.Error().
 			Err(err).
-			Msg("Failed to copy object from draft to main bucket")
-		return fmt.Errorf("failed to confirm upload: %w", err)
+			Msg("Failed to stat draft object")
+		return "", fmt.Errorf("failed to confirm upload: failed to stat draft object: %w", err)
+	}
+
+	// Validate draft content before promotion
+	if err := s.validateDraft(ctx, objectName, info); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Draft object failed validation")
+		if errors.Is(err, ErrValidationFailed) {
+			if qErr := s.quarantineDraft(ctx, objectName); qErr != nil {
+				log.Error().
+					Err(qErr).
+					Msg("Failed to quarantine draft object")
+			}
+		}
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+
+	// Scan draft content for malware before promotion
+	if err := s.scanDraft(ctx, objectName, info); err != nil {
+		if errors.Is(err, ErrInfected) {
+			if qErr := s.quarantineDraft(ctx, objectName); qErr != nil {
+				log.Error().
+					Err(qErr).
+					Msg("Failed to quarantine infected draft object")
+			}
+		}
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+
+	// Verify draft integrity before promotion
+	checksumAlgorithm, err := s.verifyChecksum(ctx, objectName, info, opts.Checksum)
+	if err != nil {
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+
+	// Let hooks veto the confirmation or choose the destination key
+	hookRequest := s.confirmHookRequest(ctx, objectName, info)
+	if err := s.runPreConfirmHooks(ctx, &hookRequest); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Confirmation rejected by hook")
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+	destName := hookRequest.ObjectName
+	if destName == "" {
+		return "", fmt.Errorf("failed to confirm upload: %w: empty object name", ErrRejected)
+	}
+	if err := s.checkObjectName(destName); err != nil {
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+	if destName != objectName {
+		log = log.With().Str("dest_object_name", destName).Logger()
+		log.Info().Msg("Destination key rewritten by hook")
+	}
+
+	previousSize, err := s.chargeConfirmed(ctx, destName, info.Size)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Confirmation exceeds quota")
+		return "", fmt.Errorf("failed to confirm upload: %w", err)
+	}
+
+	if s.dedup {
+		// Store content once under its hash and point the object name at it
+		if err := s.promoteDeduplicated(ctx, objectName, destName, info, checksumAlgorithm); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to promote deduplicated object")
+			s.restoreConfirmed(ctx, destName, previousSize)
+			return "", fmt.Errorf("failed to confirm upload: %w", err)
+		}
+	} else {
+		// Copy object from draft bucket to main bucket, keeping its checksum
+		if err := s.storage.CopyObject(ctx, s.draftBucket, s.draftKey(objectName), s.bucketName, destName, storage.CopyObjectOptions{
+			ChecksumAlgorithm: checksumAlgorithm,
+			SourceIfMatch:     info.ETag,
+		}); err != nil {
+			log.Error().
+				Err(err).
+				Msg("Failed to copy object from draft to main bucket")
+			s.restoreConfirmed(ctx, destName, previousSize)
+			return "", fmt.Errorf("failed to confirm upload: %w", err)
+		}
 	}
 
 	log.Info().Msg("Object copied successfully, now deleting from draft bucket")
 
 	// Delete object from draft bucket
-	if err := s.storage.DeleteObject(ctx, s.draftBucket, objectName); err != nil {
+	if err := s.storage.DeleteObject(ctx, s.draftBucket, s.draftKey(objectName)); err != nil {
 		log.Error().
 			Err(err).
 			Msg("Failed to delete object from draft bucket after confirmation")
-		return fmt.Errorf("failed to delete draft object after confirmation: %w", err)
+		return "", fmt.Errorf("failed to delete draft object after confirmation: %w", err)
 	}
 
 	// Log the state change
@@ -197,10 +730,71 @@ func (s *Service) ConfirmUpload(ctx context.Context, objectName string) error {
 			"bucket":   s.draftBucket,
 		},
 		map[string]interface{}{
-			"location": "main_bucket",
-			"bucket":   s.bucketName,
+			"location":    "main_bucket",
+			"bucket":      s.bucketName,
+			"object_name": destName,
 		})
 
+	// The object is already promoted, so a stale reservation is only logged
+	if err := s.releaseDraft(ctx, objectName); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to release draft reservation")
+	}
+
+	// Links must not redirect to the content this confirmation replaced
+	s.linkCache.forget(destName)
+
+	// Generate derivatives of the confirmed object
+	s.enqueueProcessing(ctx, destName)
+
+	s.runPostConfirmHooks(ctx, hookRequest)
+
 	log.Info().Msg("Upload confirmation completed successfully")
+	return destName, nil
+}
+
+// CancelUpload deletes the draft objectName and releases the quota
+// reservation for it.
+func (s *Service) CancelUpload(ctx context.Context, objectName string) error {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "cancel_upload").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Logger()
+
+	log.Info().Msg("Cancelling upload")
+
+	if err := s.authorize(ctx, authz.OperationCancel, objectName, nil); err != nil {
+		return fmt.Errorf("failed to cancel upload: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return fmt.Errorf("failed to cancel upload: %w", err)
+	}
+
+	if err := s.storage.DeleteObject(ctx, s.draftBucket, s.draftKey(objectName)); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to delete draft object")
+		return fmt.Errorf("failed to cancel upload: %w", err)
+	}
+
+	if err := s.releaseDraft(ctx, objectName); err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to release draft reservation")
+		return fmt.Errorf("failed to cancel upload: %w", err)
+	}
+
+	logger.LogStateChange("cancel_upload", "object", objectName,
+		map[string]interface{}{
+			"location": "draft_bucket",
+			"bucket":   s.draftBucket,
+		},
+		map[string]interface{}{
+			"status": "deleted",
+		})
+
+	log.Info().Msg("Upload cancelled successfully")
 	return nil
 }
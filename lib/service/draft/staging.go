@@ -0,0 +1,38 @@
+package draft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/snowmerak/DraftStore/lib/repository"
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+// reservedPrefixes hold the service's own records, which callers must never
+// read or overwrite through object names.
+var reservedPrefixes = []string{
+	storage.BlobPrefix,
+	storage.RefPrefix,
+	storage.PendingUploadPrefix,
+	repository.DefaultUsagePrefix,
+}
+
+// draftKey returns the key the draft objectName is staged under.
+func (s *Service) draftKey(objectName string) string {
+	return s.staging.Key(objectName)
+}
+
+// checkObjectName rejects keys under the draft prefix, which would expose or
+// overwrite other callers' unconfirmed drafts, and keys under the reserved
+// prefixes of deduplicated blobs, resumable uploads and usage records.
+func (s *Service) checkObjectName(objectName string) error {
+	if prefix := s.staging.KeyPrefix(); prefix != "" && strings.HasPrefix(objectName, prefix) {
+		return fmt.Errorf("%w: %q is reserved for drafts", ErrInvalidObjectName, objectName)
+	}
+	for _, prefix := range reservedPrefixes {
+		if strings.HasPrefix(objectName, prefix) {
+			return fmt.Errorf("%w: %q is reserved", ErrInvalidObjectName, objectName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package draft
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+// fakeStorage keeps objects and multipart uploads in memory.
+type fakeStorage struct {
+	mu        sync.Mutex
+	objects   map[string]fakeObject
+	multipart map[string]map[int][]byte
+	// sequence makes ETags, upload IDs and URLs unique
+	sequence int
+}
+
+type fakeObject struct {
+	data []byte
+	info storage.ObjectInfo
+}
+
+var _ storage.Storage = (*fakeStorage)(nil)
+
+func newFakeStorage() *fakeStorage {
+	return &fakeStorage{
+		objects:   make(map[string]fakeObject),
+		multipart: make(map[string]map[int][]byte),
+	}
+}
+
+func fakeKey(bucketName, objectName string) string {
+	return bucketName + "/" + objectName
+}
+
+func fakeETag(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// keys returns the sorted keys of the stored objects under prefix.
+func (f *fakeStorage) keys(bucketName, prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for key := range f.objects {
+		if name, ok := strings.CutPrefix(key, bucketName+"/"); ok && strings.HasPrefix(name, prefix) {
+			keys = append(keys, name)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// content returns the content of an object.
+func (f *fakeStorage) content(bucketName, objectName string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[fakeKey(bucketName, objectName)]
+	return object.data, ok
+}
+
+func (f *fakeStorage) CreateBucket(ctx context.Context, bucketName string) error {
+	return nil
+}
+
+func (f *fakeStorage) DeleteBucket(ctx context.Context, bucketName string) error {
+	return nil
+}
+
+func (f *fakeStorage) ExistsBucket(ctx context.Context, bucketName string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeStorage) MakeUploadPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts storage.UploadPresignedURLOptions) (storage.PresignedURL, error) {
+	return storage.PresignedURL{URL: "https://storage.test/" + fakeKey(bucketName, objectName)}, nil
+}
+
+func (f *fakeStorage) MakeGetPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts storage.GetPresignedURLOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sequence++
+	return fmt.Sprintf("https://storage.test/%s?ttl=%s&n=%d", fakeKey(bucketName, objectName), ttl, f.sequence), nil
+}
+
+func (f *fakeStorage) StatObject(ctx context.Context, bucketName, objectName string) (storage.ObjectInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[fakeKey(bucketName, objectName)]
+	if !ok {
+		return storage.ObjectInfo{}, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+	}
+	return object.info, nil
+}
+
+func (f *fakeStorage) GetObject(ctx context.Context, bucketName, objectName string, opts storage.GetObjectOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[fakeKey(bucketName, objectName)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+	}
+	if opts.IfMatch != "" && opts.IfMatch != object.info.ETag {
+		return nil, fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+func (f *fakeStorage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts storage.PutObjectOptions) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeKey(bucketName, objectName)
+	existing, ok := f.objects[key]
+	if (opts.IfNotExists && ok) || (opts.IfMatch != "" && (!ok || existing.info.ETag != opts.IfMatch)) {
+		return "", fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+	}
+	f.store(key, objectName, data, opts.ContentType, opts.Metadata)
+	return f.objects[key].info.ETag, nil
+}
+
+// store saves an object; f.mu must be held.
+func (f *fakeStorage) store(key, objectName string, data []byte, contentType string, metadata map[string]string) {
+	f.sequence++
+	f.objects[key] = fakeObject{
+		data: data,
+		info: storage.ObjectInfo{
+			Key:          objectName,
+			Size:         int64(len(data)),
+			ContentType:  contentType,
+			ETag:         fmt.Sprintf("%s-%d", fakeETag(data), f.sequence),
+			LastModified: time.Now(),
+			Metadata:     metadata,
+		},
+	}
+}
+
+func (f *fakeStorage) ListObjects(ctx context.Context, bucketName, prefix string) ([]storage.ObjectInfo, error) {
+	var objects []storage.ObjectInfo
+	for _, name := range f.keys(bucketName, prefix) {
+		info, err := f.StatObject(ctx, bucketName, name)
+		if err == nil {
+			objects = append(objects, info)
+		}
+	}
+	return objects, nil
+}
+
+func (f *fakeStorage) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, opts storage.CopyObjectOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	object, ok := f.objects[fakeKey(srcBucket, srcObject)]
+	if !ok {
+		return fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, srcBucket, srcObject)
+	}
+	if opts.SourceIfMatch != "" && opts.SourceIfMatch != object.info.ETag {
+		return fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, srcBucket, srcObject)
+	}
+	f.store(fakeKey(dstBucket, dstObject), dstObject, object.data, object.info.ContentType, object.info.Metadata)
+	return nil
+}
+
+func (f *fakeStorage) DeleteObject(ctx context.Context, bucketName, objectName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := fakeKey(bucketName, objectName)
+	if _, ok := f.objects[key]; !ok {
+		return fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+	}
+	delete(f.objects, key)
+	return nil
+}
+
+func (f *fakeStorage) CleanupBucket(ctx context.Context, bucketName, prefix string, criteria time.Time, duration time.Duration) error {
+	return nil
+}
+
+func (f *fakeStorage) CreateMultipartUpload(ctx context.Context, bucketName, objectName string, opts storage.PutObjectOptions) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sequence++
+	id := fmt.Sprintf("multipart-%d", f.sequence)
+	f.multipart[id] = make(map[int][]byte)
+	return id, nil
+}
+
+func (f *fakeStorage) UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, size int64) (storage.Part, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return storage.Part{}, err
+	}
+	if int64(len(data)) != size {
+		return storage.Part{}, fmt.Errorf("part %d has %d bytes, not %d", partNumber, len(data), size)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts, ok := f.multipart[uploadID]
+	if !ok {
+		return storage.Part{}, fmt.Errorf("%w: multipart upload %s", storage.ErrObjectNotFound, uploadID)
+	}
+	parts[partNumber] = data
+	return storage.Part{Number: partNumber, ETag: fakeETag(data), Size: size}, nil
+}
+
+func (f *fakeStorage) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []storage.Part) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.multipart[uploadID]
+	if !ok {
+		return fmt.Errorf("%w: multipart upload %s", storage.ErrObjectNotFound, uploadID)
+	}
+	var data []byte
+	for i, part := range parts {
+		content, ok := stored[part.Number]
+		if !ok || fakeETag(content) != part.ETag {
+			return fmt.Errorf("invalid part %d", part.Number)
+		}
+		if i < len(parts)-1 && len(content) < storage.MinPartSize {
+			return errors.New("part too small")
+		}
+		data = append(data, content...)
+	}
+	delete(f.multipart, uploadID)
+	f.store(fakeKey(bucketName, objectName), objectName, data, "", nil)
+	return nil
+}
+
+func (f *fakeStorage) AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.multipart, uploadID)
+	return nil
+}
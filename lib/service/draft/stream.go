@@ -0,0 +1,301 @@
+package draft
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/authz"
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+// UploadOptions customizes UploadDraft.
+type UploadOptions struct {
+	ContentType string
+	// Checksum, when set, must match the streamed content. The draft is
+	// deleted when it does not.
+	Checksum storage.Checksum
+}
+
+// OpenObjectOptions customizes OpenObject.
+type OpenObjectOptions struct {
+	// Variant selects a derivative generated by a processor instead of the original.
+	Variant string
+}
+
+// ObjectReader is a confirmed object opened by OpenObject. Its content is
+// read in ranges, so that callers can serve partial downloads.
+type ObjectReader struct {
+	ObjectName   string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+
+	storage storage.Storage
+	bucket  string
+	key     string
+}
+
+// Range reads length bytes of the object starting at offset. A zero length
+// reads to the end of the object. The caller must close the returned reader.
+func (o *ObjectReader) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 || length < 0 || offset > o.Size {
+		return nil, fmt.Errorf("%w: range %d+%d of %d bytes", ErrInvalidSize, offset, length, o.Size)
+	}
+	if offset == o.Size {
+		return io.NopCloser(eofReader{}), nil
+	}
+
+	body, err := o.storage.GetObject(ctx, o.bucket, o.key, storage.GetObjectOptions{
+		Offset: offset,
+		Length: length,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return body, nil
+}
+
+// eofReader is an empty object range.
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+// UploadDraft streams body into the draft objectName, for clients that cannot
+// reach presigned URLs, and returns the draft key it was stored under. The
+// body must carry exactly size bytes. The draft is confirmed with
+// ConfirmUpload like an upload through a presigned URL.
+func (s *Service) UploadDraft(ctx context.Context, objectName string, body io.Reader, size int64, opts UploadOptions) (string, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "upload_draft").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Str("checksum_algorithm", string(opts.Checksum.Algorithm)).
+		Int64("size", size).
+		Logger()
+
+	log.Info().Msg("Starting draft upload")
+
+	if err := s.authorize(ctx, authz.OperationUpload, objectName, map[string]any{
+		"checksum_algorithm": string(opts.Checksum.Algorithm),
+		"size":               size,
+		"stream":             true,
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+
+	if err := validateChecksum(opts.Checksum); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected malformed checksum")
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+	if s.requireChecksum && opts.Checksum.Algorithm == storage.ChecksumAlgorithmNone {
+		log.Warn().Msg("Rejected upload without checksum")
+		return "", fmt.Errorf("failed to upload draft: %w: a checksum is required", ErrInvalidChecksum)
+	}
+	if err := s.validateSize(ctx, size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Rejected upload with invalid size")
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+
+	hookRequest := HookRequest{
+		ObjectName: objectName,
+		Principal:  auth.FromContext(ctx),
+	}
+	if err := s.runPreUploadHooks(ctx, &hookRequest); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload rejected by hook")
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+	if hookRequest.ObjectName != objectName {
+		log.Info().
+			Str("rewritten_object_name", hookRequest.ObjectName).
+			Msg("Object name rewritten by hook")
+		objectName = hookRequest.ObjectName
+	}
+	if objectName == "" {
+		return "", fmt.Errorf("failed to upload draft: %w: empty object name", ErrRejected)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+
+	// Charge the draft before accepting its content
+	if err := s.reserveDraft(ctx, objectName, size); err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Upload exceeds quota")
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+
+	// The content is hashed as it streams through, so the declared checksum
+	// is verified even by backends that do not check it
+	content := &checksumReader{reader: io.LimitReader(body, size)}
+	if opts.Checksum.Algorithm != storage.ChecksumAlgorithmNone {
+		content.hash = newChecksumHash(opts.Checksum.Algorithm)
+	}
+
+	_, err := s.storage.PutObject(ctx, s.draftBucket, s.draftKey(objectName), content, size, storage.PutObjectOptions{
+		ContentType: opts.ContentType,
+		Checksum:    opts.Checksum,
+	})
+	switch {
+	case err != nil && content.read == size && !content.matches(opts.Checksum):
+		// The backend rejected the content for the checksum it was sent with
+		err = fmt.Errorf("%w: %s of %s does not match", ErrChecksumMismatch, opts.Checksum.Algorithm, objectName)
+	case err != nil:
+		err = fmt.Errorf("failed to store draft object: %w", err)
+	case content.read != size:
+		err = fmt.Errorf("%w: received %d of %d bytes", ErrInvalidSize, content.read, size)
+	case trailingData(body):
+		err = fmt.Errorf("%w: received more than %d bytes", ErrInvalidSize, size)
+	case !content.matches(opts.Checksum):
+		err = fmt.Errorf("%w: %s of %s does not match", ErrChecksumMismatch, opts.Checksum.Algorithm, objectName)
+	}
+	if err != nil {
+		log.Error().
+			Err(err).
+			Int64("received", content.read).
+			Msg("Failed to upload draft")
+		if dErr := s.storage.DeleteObject(ctx, s.draftBucket, s.draftKey(objectName)); dErr != nil && !errors.Is(dErr, storage.ErrObjectNotFound) {
+			log.Error().
+				Err(dErr).
+				Msg("Failed to delete rejected draft object")
+		}
+		if rErr := s.releaseDraft(ctx, objectName); rErr != nil {
+			log.Error().
+				Err(rErr).
+				Msg("Failed to release draft reservation")
+		}
+		return "", fmt.Errorf("failed to upload draft: %w", err)
+	}
+
+	logger.LogStateChange("upload", "object", objectName, nil, map[string]interface{}{
+		"bucket": s.draftBucket,
+		"size":   size,
+	})
+
+	s.runPostUploadHooks(ctx, hookRequest)
+
+	log.Info().Msg("Draft upload completed successfully")
+	return objectName, nil
+}
+
+// OpenObject opens the confirmed object objectName for reading through the
+// server, for clients that cannot reach presigned URLs.
+func (s *Service) OpenObject(ctx context.Context, objectName string, opts OpenObjectOptions) (*ObjectReader, error) {
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "open_object").
+		Str("object_name", objectName).
+		Str("variant", opts.Variant).
+		Str("bucket", s.bucketName).
+		Logger()
+
+	log.Info().Msg("Opening object")
+
+	if err := s.authorize(ctx, authz.OperationDownload, objectName, map[string]any{
+		"variant": opts.Variant,
+		"stream":  true,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	if err := s.checkObjectName(objectName); err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+
+	if opts.Variant != "" {
+		objectName = DerivativeKey(objectName, opts.Variant)
+	}
+
+	target, contentType, err := s.resolveObject(ctx, objectName)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to resolve object")
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+
+	info, err := s.storage.StatObject(ctx, s.bucketName, target)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to stat object")
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+
+	log.Info().
+		Int64("size", info.Size).
+		Msg("Object opened successfully")
+	return &ObjectReader{
+		ObjectName:   objectName,
+		Size:         info.Size,
+		ContentType:  contentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+		storage:      s.storage,
+		bucket:       s.bucketName,
+		key:          target,
+	}, nil
+}
+
+// trailingData reports whether body carries more than the bytes already read.
+func trailingData(body io.Reader) bool {
+	var b [1]byte
+	n, _ := io.ReadFull(body, b[:])
+	return n > 0
+}
+
+// newChecksumHash returns the hash computing a checksum of algorithm.
+func newChecksumHash(algorithm storage.ChecksumAlgorithm) hash.Hash {
+	switch algorithm {
+	case storage.ChecksumAlgorithmSHA256:
+		return sha256.New()
+	case storage.ChecksumAlgorithmCRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case storage.ChecksumAlgorithmMD5:
+		return md5.New()
+	default:
+		return nil
+	}
+}
+
+// checksumReader counts and hashes the bytes read through it.
+type checksumReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	read   int64
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.hash != nil {
+		r.hash.Write(p[:n])
+	}
+	return n, err
+}
+
+// matches compares the digest of the bytes read so far with expected.
+func (r *checksumReader) matches(expected storage.Checksum) bool {
+	if r.hash == nil {
+		return true
+	}
+	return base64.StdEncoding.EncodeToString(r.hash.Sum(nil)) == expected.Value
+}
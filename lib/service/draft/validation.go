@@ -0,0 +1,153 @@
+package draft
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	// DefaultSniffLength is the number of leading bytes used for MIME detection.
+	// http.DetectContentType never considers more than 512 bytes.
+	DefaultSniffLength = 512
+)
+
+// ValidationPolicy describes the checks a draft must pass before it is promoted.
+// The zero value disables validation.
+type ValidationPolicy struct {
+	// MaxSize is the maximum object size in bytes. Zero means unlimited.
+	MaxSize int64
+	// AllowedContentTypes lists MIME types detected from the object content.
+	// Entries may use a wildcard subtype such as "image/*". Empty allows any type.
+	AllowedContentTypes []string
+	// RequireExtensionMatch rejects drafts whose file extension maps to a
+	// different MIME type than the one detected from the content.
+	RequireExtensionMatch bool
+	// QuarantineBucket receives drafts that fail validation or are flagged
+	// by the malware scanner. When empty, rejected drafts are left in the
+	// draft bucket for the cleaner.
+	QuarantineBucket string
+}
+
+func (p ValidationPolicy) enabled() bool {
+	return p.MaxSize > 0 || len(p.AllowedContentTypes) > 0 || p.RequireExtensionMatch
+}
+
+func (p ValidationPolicy) needsSniff() bool {
+	return len(p.AllowedContentTypes) > 0 || p.RequireExtensionMatch
+}
+
+func (p ValidationPolicy) allows(contentType string) bool {
+	if len(p.AllowedContentTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.AllowedContentTypes {
+		if allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDraft checks the version info of the draft object against the
+// validation policy. Policy violations are returned wrapping ErrValidationFailed.
+func (s *Service) validateDraft(ctx context.Context, objectName string, info storage.ObjectInfo) error {
+	if !s.validation.enabled() {
+		return nil
+	}
+
+	log := logger.GetServiceLogger("draft-service").With().
+		Str("operation", "validate_draft").
+		Str("object_name", objectName).
+		Str("bucket", s.draftBucket).
+		Logger()
+
+	if s.validation.MaxSize > 0 && info.Size > s.validation.MaxSize {
+		return fmt.Errorf("%w: %w: object size %d exceeds limit %d", ErrValidationFailed, ErrTooLarge, info.Size, s.validation.MaxSize)
+	}
+
+	if !s.validation.needsSniff() {
+		return nil
+	}
+
+	reader, err := s.storage.GetObject(ctx, s.draftBucket, s.draftKey(objectName), storage.GetObjectOptions{
+		Length:  DefaultSniffLength,
+		IfMatch: info.ETag,
+	})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to read draft object")
+		return fmt.Errorf("failed to read draft object: %w", err)
+	}
+	defer reader.Close()
+
+	head, err := io.ReadAll(io.LimitReader(reader, DefaultSniffLength))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Msg("Failed to read draft object")
+		return fmt.Errorf("failed to read draft object: %w", err)
+	}
+
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	log.Debug().
+		Str("detected_content_type", detected).
+		Str("declared_content_type", info.ContentType).
+		Msg("Detected draft content type")
+
+	if !s.validation.allows(detected) {
+		return fmt.Errorf("%w: content type %s is not allowed", ErrValidationFailed, detected)
+	}
+
+	if s.validation.RequireExtensionMatch {
+		ext := path.Ext(objectName)
+		if expected := mime.TypeByExtension(ext); expected != "" {
+			expected, _, _ = mime.ParseMediaType(expected)
+			if expected != detected {
+				return fmt.Errorf("%w: extension %s does not match content type %s", ErrValidationFailed, ext, detected)
+			}
+		}
+	}
+
+	return nil
+}
+
+// quarantineDraft moves a draft that failed validation to the quarantine bucket.
+func (s *Service) quarantineDraft(ctx context.Context, objectName string) error {
+	if s.validation.QuarantineBucket == "" {
+		return nil
+	}
+
+	if err := s.storage.CopyObject(ctx, s.draftBucket, s.draftKey(objectName), s.validation.QuarantineBucket, objectName, storage.CopyObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to copy draft object to quarantine: %w", err)
+	}
+
+	if err := s.storage.DeleteObject(ctx, s.draftBucket, s.draftKey(objectName)); err != nil {
+		return fmt.Errorf("failed to delete quarantined draft object: %w", err)
+	}
+
+	logger.LogStateChange("quarantine", "object", objectName,
+		map[string]interface{}{
+			"location": "draft_bucket",
+			"bucket":   s.draftBucket,
+		},
+		map[string]interface{}{
+			"location": "quarantine_bucket",
+			"bucket":   s.validation.QuarantineBucket,
+		})
+
+	return nil
+}
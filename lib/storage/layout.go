@@ -0,0 +1,16 @@
+package storage
+
+// Reserved prefixes of the records the draft service keeps next to the
+// objects it stores. The draft service rejects object names under them and
+// the cleaner collects the records they hold.
+const (
+	// BlobPrefix holds deduplicated content in the main bucket, keyed by
+	// its SHA-256.
+	BlobPrefix = ".blobs/sha256/"
+	// RefPrefix holds one empty marker per logical key referencing a blob,
+	// under RefPrefix + <sha256> + "/" + <logical key>.
+	RefPrefix = ".refs/sha256/"
+	// PendingUploadPrefix holds, under the draft prefix, the received bytes
+	// of resumable uploads that do not fill a part yet.
+	PendingUploadPrefix = "~uploads/"
+)
@@ -2,6 +2,12 @@ package minio
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -14,6 +20,7 @@ var _ storage.Storage = (*Client)(nil)
 
 type Client struct {
 	client *minio.Client
+	core   minio.Core
 }
 
 type ClientOptions struct {
@@ -62,6 +69,7 @@ func NewClient(opts ClientOptions) (*Client, error) {
 
 	return &Client{
 		client: client,
+		core:   minio.Core{Client: client},
 	}, nil
 }
 
@@ -81,8 +89,19 @@ func (c *Client) ExistsBucket(ctx context.Context, bucketName string) (bool, err
 }
 
 // MakeGetPresignedURL implements storage.Storage.
-func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration) (string, error) {
-	presignedURL, err := c.client.PresignedGetObject(ctx, bucketName, objectName, ttl, nil)
+func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration, opts storage.GetPresignedURLOptions) (string, error) {
+	reqParams := make(url.Values)
+	if opts.ResponseContentDisposition != "" {
+		reqParams.Set("response-content-disposition", opts.ResponseContentDisposition)
+	}
+	if opts.ResponseContentType != "" {
+		reqParams.Set("response-content-type", opts.ResponseContentType)
+	}
+	if opts.ResponseCacheControl != "" {
+		reqParams.Set("response-cache-control", opts.ResponseCacheControl)
+	}
+
+	presignedURL, err := c.client.PresignedGetObject(ctx, bucketName, objectName, ttl, reqParams)
 	if err != nil {
 		return "", err
 	}
@@ -90,25 +109,193 @@ func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, obj
 }
 
 // MakeUploadPresignedURL implements storage.Storage.
-func (c *Client) MakeUploadPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration) (string, error) {
-	presignedURL, err := c.client.PresignedPutObject(ctx, bucketName, objectName, ttl)
+func (c *Client) MakeUploadPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration, opts storage.UploadPresignedURLOptions) (storage.PresignedURL, error) {
+	headers := make(http.Header)
+	switch opts.Checksum.Algorithm {
+	case storage.ChecksumAlgorithmNone:
+	case storage.ChecksumAlgorithmSHA256:
+		headers.Set("X-Amz-Checksum-Sha256", opts.Checksum.Value)
+	case storage.ChecksumAlgorithmCRC32C:
+		headers.Set("X-Amz-Checksum-Crc32c", opts.Checksum.Value)
+	case storage.ChecksumAlgorithmMD5:
+		headers.Set("Content-Md5", opts.Checksum.Value)
+	default:
+		return storage.PresignedURL{}, fmt.Errorf("unsupported checksum algorithm: %s", opts.Checksum.Algorithm)
+	}
+	if opts.ContentLength > 0 {
+		headers.Set("Content-Length", strconv.FormatInt(opts.ContentLength, 10))
+	}
+
+	presignedURL, err := c.client.PresignHeader(ctx, http.MethodPut, bucketName, objectName, ttl, nil, headers)
+	if err != nil {
+		return storage.PresignedURL{}, err
+	}
+	return storage.PresignedURL{
+		URL:           presignedURL.String(),
+		SignedHeaders: headers,
+	}, nil
+}
+
+// StatObject implements storage.Storage.
+func (c *Client) StatObject(ctx context.Context, bucketName string, objectName string) (storage.ObjectInfo, error) {
+	info, err := c.client.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{Checksum: true})
+	if err != nil {
+		if isNotFound(err) {
+			return storage.ObjectInfo{}, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+		}
+		return storage.ObjectInfo{}, err
+	}
+
+	return storage.ObjectInfo{
+		Key:            info.Key,
+		Size:           info.Size,
+		ContentType:    info.ContentType,
+		ETag:           info.ETag,
+		LastModified:   info.LastModified,
+		ChecksumSHA256: info.ChecksumSHA256,
+		ChecksumCRC32C: info.ChecksumCRC32C,
+		Metadata:       normalizeMetadata(info.UserMetadata),
+	}, nil
+}
+
+// GetObject implements storage.Storage.
+func (c *Client) GetObject(ctx context.Context, bucketName string, objectName string, opts storage.GetObjectOptions) (io.ReadCloser, error) {
+	getOpts := minio.GetObjectOptions{}
+	switch {
+	case opts.Length > 0:
+		if err := getOpts.SetRange(opts.Offset, opts.Offset+opts.Length-1); err != nil {
+			return nil, err
+		}
+	case opts.Offset > 0:
+		if err := getOpts.SetRange(opts.Offset, 0); err != nil {
+			return nil, err
+		}
+	}
+	if opts.IfMatch != "" {
+		if err := getOpts.SetMatchETag(strings.Trim(opts.IfMatch, `"`)); err != nil {
+			return nil, err
+		}
+	}
+
+	object, err := c.client.GetObject(ctx, bucketName, objectName, getOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy; Stat issues the request so that errors surface here
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+		}
+		if isPreconditionFailed(err) {
+			return nil, fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+		}
+		return nil, err
+	}
+	return object, nil
+}
+
+// PutObject implements storage.Storage.
+func (c *Client) PutObject(ctx context.Context, bucketName string, objectName string, reader io.Reader, size int64, opts storage.PutObjectOptions) (string, error) {
+	putOpts := minio.PutObjectOptions{
+		ContentType:  opts.ContentType,
+		UserMetadata: opts.Metadata,
+	}
+	if opts.Checksum.Algorithm != storage.ChecksumAlgorithmNone {
+		// A declared checksum covers the whole object, so it cannot be split into parts.
+		// A single part upload also keeps the ETag an MD5 of the content.
+		putOpts.DisableMultipart = true
+		putOpts.UserMetadata = make(map[string]string, len(opts.Metadata)+1)
+		for k, v := range opts.Metadata {
+			putOpts.UserMetadata[k] = v
+		}
+		switch opts.Checksum.Algorithm {
+		case storage.ChecksumAlgorithmSHA256:
+			putOpts.UserMetadata["X-Amz-Checksum-Sha256"] = opts.Checksum.Value
+		case storage.ChecksumAlgorithmCRC32C:
+			putOpts.UserMetadata["X-Amz-Checksum-Crc32c"] = opts.Checksum.Value
+		case storage.ChecksumAlgorithmMD5:
+			// minio-go only sends Content-MD5 by buffering the body; the caller verifies it
+		default:
+			return "", fmt.Errorf("unsupported checksum algorithm: %s", opts.Checksum.Algorithm)
+		}
+	}
+	// Conditions are only evaluated on single part uploads
+	if opts.IfMatch != "" {
+		putOpts.DisableMultipart = true
+		putOpts.SetMatchETag(strings.Trim(opts.IfMatch, `"`))
+	}
+	if opts.IfNotExists {
+		putOpts.DisableMultipart = true
+		putOpts.SetMatchETagExcept("*")
+	}
+
+	info, err := c.client.PutObject(ctx, bucketName, objectName, reader, size, putOpts)
+	// An object that is gone no longer has the ETag either
+	if isPreconditionFailed(err) || (opts.IfMatch != "" && isNotFound(err)) {
+		return "", fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+	}
 	if err != nil {
 		return "", err
 	}
-	return presignedURL.String(), nil
+	return info.ETag, nil
+}
+
+// ListObjects implements storage.Storage.
+func (c *Client) ListObjects(ctx context.Context, bucketName string, prefix string) ([]storage.ObjectInfo, error) {
+	objectCh := c.client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+
+	var objects []storage.ObjectInfo
+	for object := range objectCh {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+
+		objects = append(objects, storage.ObjectInfo{
+			Key:          object.Key,
+			Size:         object.Size,
+			ETag:         object.ETag,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return objects, nil
 }
 
 // CopyObject implements storage.Storage.
-func (c *Client) CopyObject(ctx context.Context, srcBucket string, srcObject string, dstBucket string, dstObject string) error {
-	srcOpts := minio.CopySrcOptions{
-		Bucket: srcBucket,
-		Object: srcObject,
+func (c *Client) CopyObject(ctx context.Context, srcBucket string, srcObject string, dstBucket string, dstObject string, opts storage.CopyObjectOptions) error {
+	var err error
+	if opts.ChecksumAlgorithm == storage.ChecksumAlgorithmSHA256 || opts.ChecksumAlgorithm == storage.ChecksumAlgorithmCRC32C {
+		// CopyDestOptions cannot request a checksum, so go through the core
+		// API, which ignores the conditions of CopySrcOptions
+		headers := map[string]string{
+			"X-Amz-Checksum-Algorithm": string(opts.ChecksumAlgorithm),
+		}
+		if opts.SourceIfMatch != "" {
+			headers["X-Amz-Copy-Source-If-Match"] = quoteETag(opts.SourceIfMatch)
+		}
+		_, err = c.core.CopyObject(ctx, srcBucket, srcObject, dstBucket, dstObject, headers, minio.CopySrcOptions{}, minio.PutObjectOptions{})
+	} else {
+		srcOpts := minio.CopySrcOptions{
+			Bucket: srcBucket,
+			Object: srcObject,
+		}
+		if opts.SourceIfMatch != "" {
+			srcOpts.MatchETag = quoteETag(opts.SourceIfMatch)
+		}
+		dstOpts := minio.CopyDestOptions{
+			Bucket: dstBucket,
+			Object: dstObject,
+		}
+		_, err = c.client.CopyObject(ctx, dstOpts, srcOpts)
 	}
-	dstOpts := minio.CopyDestOptions{
-		Bucket: dstBucket,
-		Object: dstObject,
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, srcBucket, srcObject)
 	}
-	_, err := c.client.CopyObject(ctx, dstOpts, srcOpts)
 	return err
 }
 
@@ -118,8 +305,9 @@ func (c *Client) DeleteObject(ctx context.Context, bucketName string, objectName
 }
 
 // CleanupBucket implements storage.Storage.
-func (c *Client) CleanupBucket(ctx context.Context, bucketName string, criteria time.Time, duration time.Duration) error {
+func (c *Client) CleanupBucket(ctx context.Context, bucketName, prefix string, criteria time.Time, duration time.Duration) error {
 	objectCh := c.client.ListObjects(ctx, bucketName, minio.ListObjectsOptions{
+		Prefix:       prefix,
 		Recursive:    true,
 		WithMetadata: true,
 	})
@@ -147,3 +335,78 @@ func (c *Client) CleanupBucket(ctx context.Context, bucketName string, criteria
 
 	return nil
 }
+
+// CreateMultipartUpload implements storage.Storage.
+func (c *Client) CreateMultipartUpload(ctx context.Context, bucketName string, objectName string, opts storage.PutObjectOptions) (string, error) {
+	return c.core.NewMultipartUpload(ctx, bucketName, objectName, minio.PutObjectOptions{
+		ContentType:  opts.ContentType,
+		UserMetadata: opts.Metadata,
+	})
+}
+
+// UploadPart implements storage.Storage.
+func (c *Client) UploadPart(ctx context.Context, bucketName string, objectName string, uploadID string, partNumber int, reader io.Reader, size int64) (storage.Part, error) {
+	part, err := c.core.PutObjectPart(ctx, bucketName, objectName, uploadID, partNumber, reader, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		if isNotFound(err) {
+			return storage.Part{}, fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+		}
+		return storage.Part{}, err
+	}
+	return storage.Part{
+		Number: partNumber,
+		ETag:   part.ETag,
+		Size:   size,
+	}, nil
+}
+
+// CompleteMultipartUpload implements storage.Storage.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, bucketName string, objectName string, uploadID string, parts []storage.Part) error {
+	completed := make([]minio.CompletePart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, minio.CompletePart{
+			PartNumber: part.Number,
+			ETag:       part.ETag,
+		})
+	}
+
+	_, err := c.core.CompleteMultipartUpload(ctx, bucketName, objectName, uploadID, completed, minio.PutObjectOptions{})
+	if err != nil && isNotFound(err) {
+		return fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+	}
+	return err
+}
+
+// AbortMultipartUpload implements storage.Storage.
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucketName string, objectName string, uploadID string) error {
+	err := c.core.AbortMultipartUpload(ctx, bucketName, objectName, uploadID)
+	if err != nil && isNotFound(err) {
+		return fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+	}
+	return err
+}
+
+func isNotFound(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case minio.NoSuchKey, "NoSuchUpload":
+		return true
+	}
+	return false
+}
+
+func isPreconditionFailed(err error) bool {
+	return err != nil && minio.ToErrorResponse(err).Code == "PreconditionFailed"
+}
+
+// quoteETag returns etag in the quoted form of the If-Match headers.
+func quoteETag(etag string) string {
+	return `"` + strings.Trim(etag, `"`) + `"`
+}
+
+func normalizeMetadata(metadata map[string]string) map[string]string {
+	normalized := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		normalized[strings.ToLower(key)] = value
+	}
+	return normalized
+}
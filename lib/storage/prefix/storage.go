@@ -0,0 +1,138 @@
+package prefix
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+var _ storage.Storage = (*Storage)(nil)
+
+// Storage confines another Storage to the object keys under a prefix, so
+// several tenants can share a bucket without seeing each other's objects.
+// Callers use unprefixed keys; buckets are passed through unchanged.
+type Storage struct {
+	storage storage.Storage
+	prefix  string
+}
+
+type StorageOptions struct {
+	Storage storage.Storage
+	// Prefix is prepended to every object key, e.g. "acme/".
+	Prefix string
+}
+
+func NewStorage(opts StorageOptions) (*Storage, error) {
+	if opts.Storage == nil {
+		return nil, errors.New("storage is required")
+	}
+	if opts.Prefix == "" {
+		return nil, errors.New("prefix is required")
+	}
+
+	return &Storage{
+		storage: opts.Storage,
+		prefix:  opts.Prefix,
+	}, nil
+}
+
+func (s *Storage) key(objectName string) string {
+	return s.prefix + objectName
+}
+
+// CreateBucket implements storage.Storage.
+func (s *Storage) CreateBucket(ctx context.Context, bucketName string) error {
+	return s.storage.CreateBucket(ctx, bucketName)
+}
+
+// DeleteBucket implements storage.Storage.
+func (s *Storage) DeleteBucket(ctx context.Context, bucketName string) error {
+	return s.storage.DeleteBucket(ctx, bucketName)
+}
+
+// ExistsBucket implements storage.Storage.
+func (s *Storage) ExistsBucket(ctx context.Context, bucketName string) (bool, error) {
+	return s.storage.ExistsBucket(ctx, bucketName)
+}
+
+// MakeUploadPresignedURL implements storage.Storage.
+func (s *Storage) MakeUploadPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts storage.UploadPresignedURLOptions) (storage.PresignedURL, error) {
+	return s.storage.MakeUploadPresignedURL(ctx, bucketName, s.key(objectName), ttl, opts)
+}
+
+// MakeGetPresignedURL implements storage.Storage.
+func (s *Storage) MakeGetPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts storage.GetPresignedURLOptions) (string, error) {
+	return s.storage.MakeGetPresignedURL(ctx, bucketName, s.key(objectName), ttl, opts)
+}
+
+// StatObject implements storage.Storage.
+func (s *Storage) StatObject(ctx context.Context, bucketName, objectName string) (storage.ObjectInfo, error) {
+	info, err := s.storage.StatObject(ctx, bucketName, s.key(objectName))
+	if err != nil {
+		return storage.ObjectInfo{}, err
+	}
+	info.Key = strings.TrimPrefix(info.Key, s.prefix)
+	return info, nil
+}
+
+// GetObject implements storage.Storage.
+func (s *Storage) GetObject(ctx context.Context, bucketName, objectName string, opts storage.GetObjectOptions) (io.ReadCloser, error) {
+	return s.storage.GetObject(ctx, bucketName, s.key(objectName), opts)
+}
+
+// PutObject implements storage.Storage.
+func (s *Storage) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts storage.PutObjectOptions) (string, error) {
+	return s.storage.PutObject(ctx, bucketName, s.key(objectName), reader, size, opts)
+}
+
+// ListObjects implements storage.Storage. The returned keys have the prefix removed.
+func (s *Storage) ListObjects(ctx context.Context, bucketName, prefix string) ([]storage.ObjectInfo, error) {
+	objects, err := s.storage.ListObjects(ctx, bucketName, s.key(prefix))
+	if err != nil {
+		return nil, err
+	}
+	for i := range objects {
+		objects[i].Key = strings.TrimPrefix(objects[i].Key, s.prefix)
+	}
+	return objects, nil
+}
+
+// CopyObject implements storage.Storage.
+func (s *Storage) CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, opts storage.CopyObjectOptions) error {
+	return s.storage.CopyObject(ctx, srcBucket, s.key(srcObject), dstBucket, s.key(dstObject), opts)
+}
+
+// DeleteObject implements storage.Storage.
+func (s *Storage) DeleteObject(ctx context.Context, bucketName, objectName string) error {
+	return s.storage.DeleteObject(ctx, bucketName, s.key(objectName))
+}
+
+// CleanupBucket implements storage.Storage. Only objects under the prefix are
+// considered, so cleaning one tenant never touches another's drafts.
+func (s *Storage) CleanupBucket(ctx context.Context, bucketName, prefix string, criteria time.Time, duration time.Duration) error {
+	return s.storage.CleanupBucket(ctx, bucketName, s.key(prefix), criteria, duration)
+}
+
+// CreateMultipartUpload implements storage.Storage.
+func (s *Storage) CreateMultipartUpload(ctx context.Context, bucketName, objectName string, opts storage.PutObjectOptions) (string, error) {
+	return s.storage.CreateMultipartUpload(ctx, bucketName, s.key(objectName), opts)
+}
+
+// UploadPart implements storage.Storage.
+func (s *Storage) UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, size int64) (storage.Part, error) {
+	return s.storage.UploadPart(ctx, bucketName, s.key(objectName), uploadID, partNumber, reader, size)
+}
+
+// CompleteMultipartUpload implements storage.Storage.
+func (s *Storage) CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []storage.Part) error {
+	return s.storage.CompleteMultipartUpload(ctx, bucketName, s.key(objectName), uploadID, parts)
+}
+
+// AbortMultipartUpload implements storage.Storage.
+func (s *Storage) AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
+	return s.storage.AbortMultipartUpload(ctx, bucketName, s.key(objectName), uploadID)
+}
@@ -3,6 +3,9 @@ package s3
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -102,11 +105,22 @@ func (c *Client) ExistsBucket(ctx context.Context, bucketName string) (bool, err
 }
 
 // MakeGetPresignedURL implements storage.Storage.
-func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration) (string, error) {
-	request, err := c.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration, opts storage.GetPresignedURLOptions) (string, error) {
+	input := &s3.GetObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(objectName),
-	}, func(opts *s3.PresignOptions) {
+	}
+	if opts.ResponseContentDisposition != "" {
+		input.ResponseContentDisposition = aws.String(opts.ResponseContentDisposition)
+	}
+	if opts.ResponseContentType != "" {
+		input.ResponseContentType = aws.String(opts.ResponseContentType)
+	}
+	if opts.ResponseCacheControl != "" {
+		input.ResponseCacheControl = aws.String(opts.ResponseCacheControl)
+	}
+
+	request, err := c.presigner.PresignGetObject(ctx, input, func(opts *s3.PresignOptions) {
 		opts.Expires = ttl
 	})
 	if err != nil {
@@ -116,25 +130,53 @@ func (c *Client) MakeGetPresignedURL(ctx context.Context, bucketName string, obj
 }
 
 // MakeUploadPresignedURL implements storage.Storage.
-func (c *Client) MakeUploadPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration) (string, error) {
-	request, err := c.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+func (c *Client) MakeUploadPresignedURL(ctx context.Context, bucketName string, objectName string, ttl time.Duration, opts storage.UploadPresignedURLOptions) (storage.PresignedURL, error) {
+	input := &s3.PutObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(objectName),
-	}, func(opts *s3.PresignOptions) {
+	}
+	switch opts.Checksum.Algorithm {
+	case storage.ChecksumAlgorithmNone:
+	case storage.ChecksumAlgorithmSHA256:
+		input.ChecksumSHA256 = aws.String(opts.Checksum.Value)
+	case storage.ChecksumAlgorithmCRC32C:
+		input.ChecksumCRC32C = aws.String(opts.Checksum.Value)
+	case storage.ChecksumAlgorithmMD5:
+		input.ContentMD5 = aws.String(opts.Checksum.Value)
+	default:
+		return storage.PresignedURL{}, fmt.Errorf("unsupported checksum algorithm: %s", opts.Checksum.Algorithm)
+	}
+	if opts.ContentLength > 0 {
+		input.ContentLength = aws.Int64(opts.ContentLength)
+	}
+
+	request, err := c.presigner.PresignPutObject(ctx, input, func(opts *s3.PresignOptions) {
 		opts.Expires = ttl
 	})
 	if err != nil {
-		return "", err
+		return storage.PresignedURL{}, err
 	}
-	return request.URL, nil
+
+	// Host is implied by the URL; every other signed header must be sent by the client
+	headers := request.SignedHeader.Clone()
+	headers.Del("Host")
+
+	return storage.PresignedURL{
+		URL:           request.URL,
+		SignedHeaders: headers,
+	}, nil
 }
 
 // CleanupBucket implements storage.Storage.
-func (c *Client) CleanupBucket(ctx context.Context, bucketName string, criteria time.Time, duration time.Duration) error {
+func (c *Client) CleanupBucket(ctx context.Context, bucketName, prefix string, criteria time.Time, duration time.Duration) error {
 	// List objects in the bucket
-	paginator := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{
+	input := &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucketName),
-	})
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	paginator := s3.NewListObjectsV2Paginator(c.client, input)
 
 	var objectsToDelete []string
 
@@ -188,15 +230,152 @@ func (c *Client) CleanupBucket(ctx context.Context, bucketName string, criteria
 	return nil
 }
 
+// StatObject implements storage.Storage.
+func (c *Client) StatObject(ctx context.Context, bucketName string, objectName string) (storage.ObjectInfo, error) {
+	output, err := c.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(bucketName),
+		Key:          aws.String(objectName),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return storage.ObjectInfo{}, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+		}
+		return storage.ObjectInfo{}, err
+	}
+
+	return storage.ObjectInfo{
+		Key:            objectName,
+		Size:           aws.ToInt64(output.ContentLength),
+		ContentType:    aws.ToString(output.ContentType),
+		ETag:           aws.ToString(output.ETag),
+		LastModified:   aws.ToTime(output.LastModified),
+		ChecksumSHA256: aws.ToString(output.ChecksumSHA256),
+		ChecksumCRC32C: aws.ToString(output.ChecksumCRC32C),
+		Metadata:       normalizeMetadata(output.Metadata),
+	}, nil
+}
+
+// GetObject implements storage.Storage.
+func (c *Client) GetObject(ctx context.Context, bucketName string, objectName string, opts storage.GetObjectOptions) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectName),
+	}
+	switch {
+	case opts.Length > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", opts.Offset, opts.Offset+opts.Length-1))
+	case opts.Offset > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", opts.Offset))
+	}
+	if opts.IfMatch != "" {
+		input.IfMatch = aws.String(quoteETag(opts.IfMatch))
+	}
+
+	output, err := c.client.GetObject(ctx, input)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("%w: %s/%s", storage.ErrObjectNotFound, bucketName, objectName)
+		}
+		if isPreconditionFailed(err) {
+			return nil, fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+		}
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// PutObject implements storage.Storage.
+func (c *Client) PutObject(ctx context.Context, bucketName string, objectName string, reader io.Reader, size int64, opts storage.PutObjectOptions) (string, error) {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(bucketName),
+		Key:           aws.String(objectName),
+		Body:          reader,
+		ContentLength: aws.Int64(size),
+		Metadata:      opts.Metadata,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	switch opts.Checksum.Algorithm {
+	case storage.ChecksumAlgorithmNone:
+	case storage.ChecksumAlgorithmSHA256:
+		input.ChecksumSHA256 = aws.String(opts.Checksum.Value)
+	case storage.ChecksumAlgorithmCRC32C:
+		input.ChecksumCRC32C = aws.String(opts.Checksum.Value)
+	case storage.ChecksumAlgorithmMD5:
+		input.ContentMD5 = aws.String(opts.Checksum.Value)
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm: %s", opts.Checksum.Algorithm)
+	}
+	if opts.IfMatch != "" {
+		input.IfMatch = aws.String(quoteETag(opts.IfMatch))
+	}
+	if opts.IfNotExists {
+		input.IfNoneMatch = aws.String("*")
+	}
+
+	output, err := c.client.PutObject(ctx, input)
+	// An object that is gone no longer has the ETag either
+	if isPreconditionFailed(err) || (opts.IfMatch != "" && isNotFound(err)) {
+		return "", fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, bucketName, objectName)
+	}
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+// ListObjects implements storage.Storage.
+func (c *Client) ListObjects(ctx context.Context, bucketName string, prefix string) ([]storage.ObjectInfo, error) {
+	paginator := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []storage.ObjectInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, storage.ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         aws.ToString(obj.ETag),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
 // CopyObject implements storage.Storage.
-func (c *Client) CopyObject(ctx context.Context, srcBucket string, srcObject string, dstBucket string, dstObject string) error {
+func (c *Client) CopyObject(ctx context.Context, srcBucket string, srcObject string, dstBucket string, dstObject string, opts storage.CopyObjectOptions) error {
 	copySource := srcBucket + "/" + srcObject
 
-	_, err := c.client.CopyObject(ctx, &s3.CopyObjectInput{
+	input := &s3.CopyObjectInput{
 		Bucket:     aws.String(dstBucket),
 		CopySource: aws.String(copySource),
 		Key:        aws.String(dstObject),
-	})
+	}
+	switch opts.ChecksumAlgorithm {
+	case storage.ChecksumAlgorithmSHA256:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	case storage.ChecksumAlgorithmCRC32C:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32c
+	}
+	if opts.SourceIfMatch != "" {
+		input.CopySourceIfMatch = aws.String(quoteETag(opts.SourceIfMatch))
+	}
+
+	_, err := c.client.CopyObject(ctx, input)
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: %s/%s", storage.ErrPreconditionFailed, srcBucket, srcObject)
+	}
 
 	return err
 }
@@ -210,3 +389,112 @@ func (c *Client) DeleteObject(ctx context.Context, bucketName string, objectName
 
 	return err
 }
+
+// CreateMultipartUpload implements storage.Storage.
+func (c *Client) CreateMultipartUpload(ctx context.Context, bucketName string, objectName string, opts storage.PutObjectOptions) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(objectName),
+		Metadata: opts.Metadata,
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+
+	output, err := c.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+// UploadPart implements storage.Storage.
+func (c *Client) UploadPart(ctx context.Context, bucketName string, objectName string, uploadID string, partNumber int, reader io.Reader, size int64) (storage.Part, error) {
+	output, err := c.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(bucketName),
+		Key:           aws.String(objectName),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(int32(partNumber)),
+		Body:          reader,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return storage.Part{}, fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+		}
+		return storage.Part{}, err
+	}
+	return storage.Part{
+		Number: partNumber,
+		ETag:   aws.ToString(output.ETag),
+		Size:   size,
+	}, nil
+}
+
+// CompleteMultipartUpload implements storage.Storage.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, bucketName string, objectName string, uploadID string, parts []storage.Part) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.Number)),
+		})
+	}
+
+	_, err := c.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(objectName),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completed,
+		},
+	})
+	if err != nil && isNotFound(err) {
+		return fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+	}
+	return err
+}
+
+// AbortMultipartUpload implements storage.Storage.
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucketName string, objectName string, uploadID string) error {
+	_, err := c.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(objectName),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil && isNotFound(err) {
+		return fmt.Errorf("%w: upload %s of %s/%s", storage.ErrObjectNotFound, uploadID, bucketName, objectName)
+	}
+	return err
+}
+
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchUpload":
+			return true
+		}
+	}
+	return false
+}
+
+func isPreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	// Concurrent conditional writes to the same key fail with a conflict
+	return errors.As(err, &apiErr) &&
+		(apiErr.ErrorCode() == "PreconditionFailed" || apiErr.ErrorCode() == "ConditionalRequestConflict")
+}
+
+// quoteETag returns etag in the quoted form of the If-Match headers.
+func quoteETag(etag string) string {
+	return `"` + strings.Trim(etag, `"`) + `"`
+}
+
+func normalizeMetadata(metadata map[string]string) map[string]string {
+	normalized := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		normalized[strings.ToLower(key)] = value
+	}
+	return normalized
+}
@@ -0,0 +1,70 @@
+package storage
+
+import "fmt"
+
+// Draft staging layouts.
+const (
+	// DraftStagingBucket stages drafts in a separate bucket named after the
+	// main bucket.
+	DraftStagingBucket = "bucket"
+	// DraftStagingPrefix stages drafts under a prefix of the main bucket.
+	DraftStagingPrefix = "prefix"
+)
+
+const (
+	DefaultDraftBucketSuffix = "-draft"
+	DefaultDraftPrefix       = "_drafts/"
+)
+
+// DraftStaging describes where unconfirmed drafts are kept.
+type DraftStaging struct {
+	// Layout is DraftStagingBucket or DraftStagingPrefix. Defaults to DraftStagingBucket.
+	Layout string
+	// BucketSuffix names the draft bucket after the main bucket in the bucket
+	// layout. Defaults to DefaultDraftBucketSuffix.
+	BucketSuffix string
+	// Prefix holds the drafts in the prefix layout. Defaults to DefaultDraftPrefix.
+	Prefix string
+}
+
+// Validate rejects unknown layouts.
+func (s DraftStaging) Validate() error {
+	switch s.Layout {
+	case "", DraftStagingBucket, DraftStagingPrefix:
+		return nil
+	default:
+		return fmt.Errorf("unknown draft staging layout %q", s.Layout)
+	}
+}
+
+// Prefixed reports whether drafts are staged in the main bucket.
+func (s DraftStaging) Prefixed() bool {
+	return s.Layout == DraftStagingPrefix
+}
+
+// Bucket returns the bucket holding the drafts of mainBucket.
+func (s DraftStaging) Bucket(mainBucket string) string {
+	if s.Prefixed() {
+		return mainBucket
+	}
+	if s.BucketSuffix == "" {
+		return mainBucket + DefaultDraftBucketSuffix
+	}
+	return mainBucket + s.BucketSuffix
+}
+
+// KeyPrefix returns the prefix of every draft key. It is empty in the bucket layout.
+func (s DraftStaging) KeyPrefix() string {
+	if !s.Prefixed() {
+		return ""
+	}
+	if s.Prefix == "" {
+		return DefaultDraftPrefix
+	}
+	return s.Prefix
+}
+
+// Key returns the key the draft objectName is staged under.
+func (s DraftStaging) Key(objectName string) string {
+	return s.KeyPrefix() + objectName
+}
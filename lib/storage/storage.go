@@ -2,16 +2,151 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
 	"time"
 )
 
+var (
+	// ErrObjectNotFound is returned when the requested object does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrPreconditionFailed is returned when an object no longer has the
+	// ETag an operation was conditioned on.
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
 type Storage interface {
 	CreateBucket(ctx context.Context, bucketName string) error
 	DeleteBucket(ctx context.Context, bucketName string) error
 	ExistsBucket(ctx context.Context, bucketName string) (bool, error)
-	MakeUploadPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration) (string, error)
-	MakeGetPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration) (string, error)
-	CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string) error
+	MakeUploadPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts UploadPresignedURLOptions) (PresignedURL, error)
+	MakeGetPresignedURL(ctx context.Context, bucketName, objectName string, ttl time.Duration, opts GetPresignedURLOptions) (string, error)
+	StatObject(ctx context.Context, bucketName, objectName string) (ObjectInfo, error)
+	GetObject(ctx context.Context, bucketName, objectName string, opts GetObjectOptions) (io.ReadCloser, error)
+	// PutObject stores the object and returns its ETag.
+	PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts PutObjectOptions) (string, error)
+	ListObjects(ctx context.Context, bucketName, prefix string) ([]ObjectInfo, error)
+	CopyObject(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string, opts CopyObjectOptions) error
 	DeleteObject(ctx context.Context, bucketName, objectName string) error
-	CleanupBucket(ctx context.Context, bucketName string, criteria time.Time, duration time.Duration) error
+	// CleanupBucket deletes the objects under prefix that are older than both
+	// criteria and duration. An empty prefix covers the whole bucket.
+	CleanupBucket(ctx context.Context, bucketName, prefix string, criteria time.Time, duration time.Duration) error
+	// CreateMultipartUpload starts a multipart upload of objectName and
+	// returns its upload ID. The object only appears once the upload is
+	// completed with CompleteMultipartUpload.
+	CreateMultipartUpload(ctx context.Context, bucketName, objectName string, opts PutObjectOptions) (string, error)
+	// UploadPart stores part number partNumber, counted from 1, of a
+	// multipart upload. Every part but the last must be at least
+	// MinPartSize bytes.
+	UploadPart(ctx context.Context, bucketName, objectName, uploadID string, partNumber int, reader io.Reader, size int64) (Part, error)
+	// CompleteMultipartUpload assembles the parts, in order, into the object.
+	CompleteMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string, parts []Part) error
+	// AbortMultipartUpload discards a multipart upload and its parts.
+	AbortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error
+}
+
+const (
+	// MinPartSize is the smallest size of every multipart upload part but the last.
+	MinPartSize = 5 << 20
+	// MaxParts is the largest number of parts of a multipart upload.
+	MaxParts = 10000
+)
+
+// Part is an uploaded part of a multipart upload.
+type Part struct {
+	Number int
+	ETag   string
+	Size   int64
+}
+
+// ChecksumAlgorithm names an object integrity checksum supported by S3.
+type ChecksumAlgorithm string
+
+const (
+	ChecksumAlgorithmNone   ChecksumAlgorithm = ""
+	ChecksumAlgorithmSHA256 ChecksumAlgorithm = "SHA256"
+	ChecksumAlgorithmCRC32C ChecksumAlgorithm = "CRC32C"
+	// ChecksumAlgorithmMD5 is enforced through the Content-MD5 header and is
+	// not stored as an object checksum.
+	ChecksumAlgorithmMD5 ChecksumAlgorithm = "MD5"
+)
+
+// Checksum is a base64 encoded digest of an object.
+type Checksum struct {
+	Algorithm ChecksumAlgorithm
+	Value     string
+}
+
+// UploadPresignedURLOptions constrains a presigned PUT request.
+type UploadPresignedURLOptions struct {
+	// Checksum, when set, is signed into the URL so that the storage backend
+	// rejects uploads whose content does not match it.
+	Checksum Checksum
+	// ContentLength, when positive, is signed into the URL so that the storage
+	// backend rejects uploads of any other size.
+	ContentLength int64
+}
+
+// PresignedURL is a presigned request and the headers the caller must send with it.
+type PresignedURL struct {
+	URL           string
+	SignedHeaders http.Header
+}
+
+// CopyObjectOptions customizes a server-side copy.
+type CopyObjectOptions struct {
+	// ChecksumAlgorithm asks the backend to compute and store this checksum on the copy.
+	ChecksumAlgorithm ChecksumAlgorithm
+	// SourceIfMatch, when set, fails the copy with ErrPreconditionFailed
+	// unless the source object still has this ETag.
+	SourceIfMatch string
+}
+
+// GetPresignedURLOptions overrides response headers of a presigned GET request.
+// Empty fields are left to the stored object metadata.
+type GetPresignedURLOptions struct {
+	ResponseContentDisposition string
+	ResponseContentType        string
+	ResponseCacheControl       string
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+	// Stored checksums, base64 encoded. Empty when the object has none.
+	ChecksumSHA256 string
+	ChecksumCRC32C string
+	// Metadata holds user-defined metadata with lower-case keys. It is only
+	// populated by StatObject.
+	Metadata map[string]string
+}
+
+// PutObjectOptions sets the attributes of an uploaded object.
+type PutObjectOptions struct {
+	ContentType string
+	Metadata    map[string]string
+	// Checksum, when set, is sent with the upload so that the storage backend
+	// rejects content that does not match it and stores it with the object.
+	Checksum Checksum
+	// IfMatch, when set, fails the upload with ErrPreconditionFailed unless
+	// the object still exists with this ETag. Only PutObject honors it.
+	IfMatch string
+	// IfNotExists fails the upload with ErrPreconditionFailed when the object
+	// already exists. Only PutObject honors it.
+	IfNotExists bool
+}
+
+// GetObjectOptions selects the byte range read by GetObject.
+// A zero Length reads from Offset to the end of the object.
+type GetObjectOptions struct {
+	Offset int64
+	Length int64
+	// IfMatch, when set, fails the read with ErrPreconditionFailed unless
+	// the object still has this ETag.
+	IfMatch string
 }
@@ -0,0 +1,109 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrUnknownTenant is returned for requests naming a tenant that is not configured.
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrTenantRequired is returned for requests naming no tenant when there
+	// is no default service.
+	ErrTenantRequired = errors.New("tenant is required")
+	// ErrTenantMismatch is returned when the tenant header contradicts the
+	// tenant of the authenticated principal.
+	ErrTenantMismatch = errors.New("tenant does not match principal")
+)
+
+// Config routes a tenant to its buckets.
+type Config struct {
+	ID string `json:"id"`
+	// BucketName is the tenant's main bucket; its draft bucket is derived
+	// from it. The default bucket is shared when empty.
+	BucketName string `json:"bucket_name,omitempty"`
+	// KeyPrefix confines the tenant to the object keys under it, e.g. "acme/".
+	KeyPrefix string `json:"key_prefix,omitempty"`
+	// Storage, when set, gives the tenant its own storage backend.
+	Storage *StorageConfig `json:"storage,omitempty"`
+}
+
+// StorageConfig selects a tenant's storage backend. It mirrors the server's
+// STORAGE_TYPE, AWS_* and MINIO_* settings.
+type StorageConfig struct {
+	// Type is "s3" or "minio".
+	Type      string `json:"type"`
+	Region    string `json:"region,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	AccessKey string `json:"access_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+	UseSSL    bool   `json:"use_ssl,omitempty"`
+}
+
+// Equal reports whether both configs route to the same place.
+func (c Config) Equal(other Config) bool {
+	if c.ID != other.ID || c.BucketName != other.BucketName || c.KeyPrefix != other.KeyPrefix {
+		return false
+	}
+	if c.Storage == nil || other.Storage == nil {
+		return c.Storage == other.Storage
+	}
+	return *c.Storage == *other.Storage
+}
+
+// LogFields returns the fields describing the tenant in logs. It never
+// contains credentials.
+func (c Config) LogFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"bucket_name": c.BucketName,
+		"key_prefix":  c.KeyPrefix,
+	}
+	if c.Storage != nil {
+		fields["storage_type"] = c.Storage.Type
+		fields["storage_endpoint"] = c.Storage.Endpoint
+		fields["storage_region"] = c.Storage.Region
+	}
+	return fields
+}
+
+type configFile struct {
+	Tenants []Config `json:"tenants"`
+}
+
+// LoadConfigs reads tenants from a JSON file of the form
+// {"tenants": [{"id": "acme", "bucket_name": "acme-files"}, {"id": "globex", "key_prefix": "globex/"}]}.
+func LoadConfigs(path string) ([]Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant file: %w", err)
+	}
+
+	var file configFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tenant file %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool, len(file.Tenants))
+	for _, cfg := range file.Tenants {
+		if cfg.ID == "" {
+			return nil, fmt.Errorf("tenant without id in %s", path)
+		}
+		if seen[cfg.ID] {
+			return nil, fmt.Errorf("duplicate tenant %q in %s", cfg.ID, path)
+		}
+		seen[cfg.ID] = true
+
+		// A tenant sharing the default buckets without a prefix would see
+		// every other tenant's objects
+		if cfg.BucketName == "" && cfg.KeyPrefix == "" {
+			return nil, fmt.Errorf("tenant %q needs a bucket_name or a key_prefix", cfg.ID)
+		}
+		if cfg.Storage != nil && cfg.Storage.Type == "" {
+			return nil, fmt.Errorf("tenant %q has a storage without type", cfg.ID)
+		}
+	}
+
+	return file.Tenants, nil
+}
@@ -0,0 +1,24 @@
+package tenant
+
+import "context"
+
+const (
+	// Header names the tenant on HTTP requests and gRPC calls.
+	Header = "X-Tenant-ID"
+	// DefaultAttribute is the principal attribute, e.g. a JWT claim, naming
+	// the caller's tenant.
+	DefaultAttribute = "tenant"
+)
+
+type idKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant requested by the caller.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFromContext returns the tenant stored in ctx, or an empty string.
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
@@ -0,0 +1,217 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+	"github.com/snowmerak/DraftStore/lib/util/logger"
+)
+
+const (
+	DefaultReloadInterval = 30 * time.Second
+	// DefaultRetireDelay outlasts the server's request timeout, so requests
+	// still using a replaced service finish before it is closed.
+	DefaultRetireDelay = 2 * time.Minute
+)
+
+// Registry holds a draft service per tenant, built from a tenant file that is
+// reloaded when it changes. Tenants whose config is unchanged keep their
+// service across reloads.
+type Registry struct {
+	path        string
+	interval    time.Duration
+	retireDelay time.Duration
+	build       func(Config) (*draft.Service, error)
+	mu          sync.RWMutex
+	configs     map[string]Config
+	services    map[string]*draft.Service
+	modTime     time.Time
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+type RegistryOptions struct {
+	// Path is the tenant file read by LoadConfigs.
+	Path string
+	// ReloadInterval is how often the file is checked for changes.
+	ReloadInterval time.Duration
+	// RetireDelay is how long a replaced or removed tenant's service keeps
+	// serving requests in flight before it is closed.
+	RetireDelay time.Duration
+	// Build creates the draft service of a tenant.
+	Build func(Config) (*draft.Service, error)
+}
+
+func NewRegistry(opts RegistryOptions) (*Registry, error) {
+	log := logger.GetServiceLogger("tenant-registry")
+
+	if opts.Path == "" {
+		return nil, errors.New("tenant file is required")
+	}
+	if opts.Build == nil {
+		return nil, errors.New("build function is required")
+	}
+	if opts.ReloadInterval <= 0 {
+		opts.ReloadInterval = DefaultReloadInterval
+	}
+	if opts.RetireDelay <= 0 {
+		opts.RetireDelay = DefaultRetireDelay
+	}
+
+	r := &Registry{
+		path:        opts.Path,
+		interval:    opts.ReloadInterval,
+		retireDelay: opts.RetireDelay,
+		build:       opts.Build,
+		stop:        make(chan struct{}),
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	go r.reloadLoop()
+
+	log.Info().
+		Str("path", r.path).
+		Int("tenants", len(r.services)).
+		Dur("reload_interval", r.interval).
+		Dur("retire_delay", r.retireDelay).
+		Msg("Tenant registry initialized")
+
+	return r, nil
+}
+
+// Get returns the draft service of a tenant.
+func (r *Registry) Get(id string) (*draft.Service, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	service, ok := r.services[id]
+	return service, ok
+}
+
+// Close stops watching the file and closes every tenant's service.
+func (r *Registry) Close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, service := range r.services {
+			service.Close()
+		}
+	})
+}
+
+// reload rebuilds the tenants if the file changed and reports whether it did.
+// The previous tenants stay in use when reading the file or building any
+// tenant's service fails.
+func (r *Registry) reload() (bool, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat %s: %w", r.path, err)
+	}
+
+	r.mu.RLock()
+	unchanged := info.ModTime().Equal(r.modTime)
+	previousConfigs, previousServices := r.configs, r.services
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	configs, err := LoadConfigs(r.path)
+	if err != nil {
+		return false, err
+	}
+
+	nextConfigs := make(map[string]Config, len(configs))
+	nextServices := make(map[string]*draft.Service, len(configs))
+	var built []*draft.Service
+	for _, cfg := range configs {
+		nextConfigs[cfg.ID] = cfg
+		if previous, ok := previousConfigs[cfg.ID]; ok && previous.Equal(cfg) {
+			nextServices[cfg.ID] = previousServices[cfg.ID]
+			continue
+		}
+
+		service, err := r.build(cfg)
+		if err != nil {
+			for _, service := range built {
+				service.Close()
+			}
+			return false, fmt.Errorf("failed to build tenant %s: %w", cfg.ID, err)
+		}
+		nextServices[cfg.ID] = service
+		built = append(built, service)
+	}
+
+	r.mu.Lock()
+	r.configs = nextConfigs
+	r.services = nextServices
+	r.modTime = info.ModTime()
+	r.mu.Unlock()
+
+	for id, previous := range previousConfigs {
+		next, ok := nextConfigs[id]
+		switch {
+		case !ok:
+			logger.LogStateChange("remove", "tenant", id, previous.LogFields(), nil)
+		case !previous.Equal(next):
+			logger.LogStateChange("update", "tenant", id, previous.LogFields(), next.LogFields())
+		default:
+			continue
+		}
+		r.retire(previousServices[id])
+	}
+	for id, next := range nextConfigs {
+		if _, ok := previousConfigs[id]; !ok {
+			logger.LogStateChange("create", "tenant", id, nil, next.LogFields())
+		}
+	}
+
+	return true, nil
+}
+
+// retire closes a service that is no longer routed to once requests that
+// resolved it before the reload are done.
+func (r *Registry) retire(service *draft.Service) {
+	time.AfterFunc(r.retireDelay, service.Close)
+}
+
+func (r *Registry) reloadLoop() {
+	log := logger.GetServiceLogger("tenant-registry")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			reloaded, err := r.reload()
+			if err != nil {
+				// Keep serving the previous tenants
+				log.Error().
+					Err(err).
+					Msg("Failed to reload tenants")
+				continue
+			}
+			if reloaded {
+				r.mu.RLock()
+				tenants := len(r.services)
+				r.mu.RUnlock()
+
+				log.Info().
+					Str("path", r.path).
+					Int("tenants", tenants).
+					Msg("Tenants reloaded")
+			}
+		}
+	}
+}
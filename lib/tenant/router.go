@@ -0,0 +1,210 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/snowmerak/DraftStore/lib/auth"
+	"github.com/snowmerak/DraftStore/lib/service/draft"
+)
+
+var _ draft.API = (*Router)(nil)
+
+// Router dispatches every draft operation to the service of the caller's
+// tenant. The tenant is taken from the principal's tenant attribute and,
+// for principals without one, from the tenant requested in the context.
+// Servers should only store a tenant header there when unbound callers are
+// trusted to pick their tenant.
+type Router struct {
+	registry  *Registry
+	fallback  draft.API
+	attribute string
+}
+
+type RouterOptions struct {
+	Registry *Registry
+	// Default serves requests that name no tenant. They are rejected with
+	// ErrTenantRequired when it is nil.
+	Default draft.API
+	// Attribute is the principal attribute naming the caller's tenant.
+	// Defaults to DefaultAttribute.
+	Attribute string
+}
+
+func NewRouter(opts RouterOptions) (*Router, error) {
+	if opts.Registry == nil {
+		return nil, errors.New("registry is required")
+	}
+	if opts.Attribute == "" {
+		opts.Attribute = DefaultAttribute
+	}
+
+	return &Router{
+		registry:  opts.Registry,
+		fallback:  opts.Default,
+		attribute: opts.Attribute,
+	}, nil
+}
+
+// Resolve returns the service of the caller's tenant.
+func (r *Router) Resolve(ctx context.Context) (draft.API, error) {
+	id := IDFromContext(ctx)
+	if bound := auth.FromContext(ctx).Attributes[r.attribute]; bound != "" {
+		// Principals bound to a tenant cannot switch to another one
+		if id != "" && id != bound {
+			return nil, fmt.Errorf("%w: requested %q", ErrTenantMismatch, id)
+		}
+		id = bound
+	}
+
+	if id == "" {
+		if r.fallback == nil {
+			return nil, ErrTenantRequired
+		}
+		return r.fallback, nil
+	}
+
+	service, ok := r.registry.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, id)
+	}
+	return service, nil
+}
+
+// CreateDraftBucket implements draft.API.
+func (r *Router) CreateDraftBucket(ctx context.Context) error {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.CreateDraftBucket(ctx)
+}
+
+// GetUploadURL implements draft.API.
+func (r *Router) GetUploadURL(ctx context.Context, objectName string, opts draft.UploadURLOptions) (draft.UploadURL, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.UploadURL{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.GetUploadURL(ctx, objectName, opts)
+}
+
+// GetDownloadURL implements draft.API.
+func (r *Router) GetDownloadURL(ctx context.Context, objectName string, opts draft.DownloadURLOptions) (string, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.GetDownloadURL(ctx, objectName, opts)
+}
+
+// GetDraftDownloadURL implements draft.API.
+func (r *Router) GetDraftDownloadURL(ctx context.Context, objectName string) (string, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.GetDraftDownloadURL(ctx, objectName)
+}
+
+// StatDraft implements draft.API.
+func (r *Router) StatDraft(ctx context.Context, objectName string) (draft.DraftInfo, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.DraftInfo{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.StatDraft(ctx, objectName)
+}
+
+// UploadDraft implements draft.API.
+func (r *Router) UploadDraft(ctx context.Context, objectName string, body io.Reader, size int64, opts draft.UploadOptions) (string, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.UploadDraft(ctx, objectName, body, size, opts)
+}
+
+// OpenObject implements draft.API.
+func (r *Router) OpenObject(ctx context.Context, objectName string, opts draft.OpenObjectOptions) (*draft.ObjectReader, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.OpenObject(ctx, objectName, opts)
+}
+
+// CreateUpload implements draft.API.
+func (r *Router) CreateUpload(ctx context.Context, objectName string, size int64, opts draft.CreateUploadOptions) (draft.ResumableUpload, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.ResumableUpload{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.CreateUpload(ctx, objectName, size, opts)
+}
+
+// GetUpload implements draft.API.
+func (r *Router) GetUpload(ctx context.Context, id string) (draft.ResumableUpload, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.ResumableUpload{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.GetUpload(ctx, id)
+}
+
+// WriteUpload implements draft.API.
+func (r *Router) WriteUpload(ctx context.Context, id string, offset int64, body io.Reader) (draft.ResumableUpload, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.ResumableUpload{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.WriteUpload(ctx, id, offset, body)
+}
+
+// TerminateUpload implements draft.API.
+func (r *Router) TerminateUpload(ctx context.Context, id string) error {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.TerminateUpload(ctx, id)
+}
+
+// ConfirmUpload implements draft.API.
+func (r *Router) ConfirmUpload(ctx context.Context, objectName string, opts draft.ConfirmUploadOptions) (string, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.ConfirmUpload(ctx, objectName, opts)
+}
+
+// CancelUpload implements draft.API.
+func (r *Router) CancelUpload(ctx context.Context, objectName string) error {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.CancelUpload(ctx, objectName)
+}
+
+// SignLink implements draft.API.
+func (r *Router) SignLink(ctx context.Context, objectName string, ttl time.Duration) (draft.SignedLink, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.SignedLink{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.SignLink(ctx, objectName, ttl)
+}
+
+// ResolveLink implements draft.API.
+func (r *Router) ResolveLink(ctx context.Context, objectName string, opts draft.LinkOptions) (draft.LinkURL, error) {
+	service, err := r.Resolve(ctx)
+	if err != nil {
+		return draft.LinkURL{}, fmt.Errorf("failed to resolve tenant: %w", err)
+	}
+	return service.ResolveLink(ctx, objectName, opts)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -34,14 +35,38 @@ func GetServiceLogger(serviceName string) zerolog.Logger {
 		Logger()
 }
 
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx carrying fields that GetHandlerLogger adds
+// to every log entry, such as the authenticated caller.
+func WithFields(ctx context.Context, fields map[string]string) context.Context {
+	merged := make(map[string]string)
+	if existing, ok := ctx.Value(fieldsKey{}).(map[string]string); ok {
+		for key, value := range existing {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
 // GetHandlerLogger returns a logger for HTTP/gRPC handlers
-func GetHandlerLogger(handlerType, method, path string) zerolog.Logger {
-	return Log.With().
+func GetHandlerLogger(ctx context.Context, handlerType, method, path string) zerolog.Logger {
+	logContext := Log.With().
 		Str("component", "handler").
 		Str("type", handlerType).
 		Str("method", method).
-		Str("path", path).
-		Logger()
+		Str("path", path)
+
+	if fields, ok := ctx.Value(fieldsKey{}).(map[string]string); ok {
+		for key, value := range fields {
+			logContext = logContext.Str(key, value)
+		}
+	}
+
+	return logContext.Logger()
 }
 
 // LogStartup logs application startup information
@@ -0,0 +1,65 @@
+package protoconv
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/auth/apikey"
+)
+
+// ToScopes converts protobuf API key scopes to their names. Unknown scopes
+// are passed through so that validation can reject them.
+func ToScopes(scopes []draftv1.APIKeyScope) []string {
+	names := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		switch scope {
+		case draftv1.APIKeyScope_API_KEY_SCOPE_UPLOAD:
+			names = append(names, apikey.ScopeUpload)
+		case draftv1.APIKeyScope_API_KEY_SCOPE_CONFIRM:
+			names = append(names, apikey.ScopeConfirm)
+		case draftv1.APIKeyScope_API_KEY_SCOPE_DOWNLOAD:
+			names = append(names, apikey.ScopeDownload)
+		case draftv1.APIKeyScope_API_KEY_SCOPE_ADMIN:
+			names = append(names, apikey.ScopeAdmin)
+		default:
+			names = append(names, scope.String())
+		}
+	}
+	return names
+}
+
+// FromAPIKey converts an API key to its protobuf description.
+func FromAPIKey(key apikey.Key) *draftv1.APIKey {
+	message := &draftv1.APIKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		KeyPrefix: key.Prefix,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+		ExpiresAt: optionalTimestamp(key.ExpiresAt),
+		RevokedAt: optionalTimestamp(key.RevokedAt),
+	}
+
+	for _, scope := range key.Scopes {
+		switch scope {
+		case apikey.ScopeUpload:
+			message.Scopes = append(message.Scopes, draftv1.APIKeyScope_API_KEY_SCOPE_UPLOAD)
+		case apikey.ScopeConfirm:
+			message.Scopes = append(message.Scopes, draftv1.APIKeyScope_API_KEY_SCOPE_CONFIRM)
+		case apikey.ScopeDownload:
+			message.Scopes = append(message.Scopes, draftv1.APIKeyScope_API_KEY_SCOPE_DOWNLOAD)
+		case apikey.ScopeAdmin:
+			message.Scopes = append(message.Scopes, draftv1.APIKeyScope_API_KEY_SCOPE_ADMIN)
+		}
+	}
+
+	return message
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,23 @@
+package protoconv
+
+import (
+	draftv1 "github.com/snowmerak/DraftStore/gen/draft/v1"
+	"github.com/snowmerak/DraftStore/lib/storage"
+)
+
+// ToChecksum converts a protobuf checksum declaration to a storage checksum.
+// Unknown algorithms are passed through so that validation can reject them.
+func ToChecksum(algorithm draftv1.ChecksumAlgorithm, value string) storage.Checksum {
+	switch algorithm {
+	case draftv1.ChecksumAlgorithm_CHECKSUM_ALGORITHM_UNSPECIFIED:
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmNone, Value: value}
+	case draftv1.ChecksumAlgorithm_CHECKSUM_ALGORITHM_SHA256:
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmSHA256, Value: value}
+	case draftv1.ChecksumAlgorithm_CHECKSUM_ALGORITHM_CRC32C:
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmCRC32C, Value: value}
+	case draftv1.ChecksumAlgorithm_CHECKSUM_ALGORITHM_MD5:
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithmMD5, Value: value}
+	default:
+		return storage.Checksum{Algorithm: storage.ChecksumAlgorithm(algorithm.String()), Value: value}
+	}
+}